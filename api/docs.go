@@ -38,38 +38,137 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/identities/students": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/v1/identities/dead-letters": {
+            "get": {
+                "description": "Events that could not be published after every retry, from the oldest failure. The value is the\nrecord value as it was going to be published, base64 encoded.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "Registration"
+                    "Administration"
                 ],
-                "summary": "Register a student",
+                "summary": "List the dead-lettered events",
                 "parameters": [
                     {
-                        "description": "Student creation information",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Admin API key with the identity.events.admin scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Topic the events were going to be published to",
+                        "name": "topic",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor sent in the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentRegisterRequest"
+                            "$ref": "#/definitions/pkg_gateways_httpserver.DeadLettersPageResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.ValidationHTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/identities/dead-letters/{id}": {
+            "delete": {
+                "description": "The event is deleted without being published, consumers never receive it. The discard is audited.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Administration"
+                ],
+                "summary": "Discard a dead-lettered event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key with the identity.events.admin scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentRegisterResponse"
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
                         }
@@ -83,38 +182,63 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/identities/students/login": {
+        "/v1/identities/dead-letters/{id}/retry": {
             "post": {
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "The event is published as it was stored and deleted once published, a failure is recorded in it\nand it is kept. The retry is audited. Only the oldest dead letter of a key is published, and not\nafter a later event of the key was published, so the events of each student stay in order.",
                 "produces": [
-                    "application/json"
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Administration"
                 ],
-                "summary": "Authenticate a student",
+                "summary": "Publish a dead-lettered event again",
                 "parameters": [
                     {
-                        "description": "Student credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/pkg_gateways_httpserver.AuthenticateStudentRequest"
-                        }
+                        "type": "string",
+                        "description": "Admin API key with the identity.events.admin scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/pkg_gateways_httpserver.AuthenticateStudentResponse"
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
                         }
@@ -124,36 +248,110 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
                     }
                 }
             }
         },
-        "/v1/identities/students/verify-auth": {
-            "post": {
+        "/v1/identities/students": {
+            "get": {
+                "description": "Students are listed from the latest registered one, every informed filter must match.\nThe name is matched ignoring accents, by any of its words, a part of it or a similar name.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Administration"
                 ],
-                "summary": "Verifies if Student Authentication is valid",
+                "summary": "Search students",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Authorization token",
-                        "name": "authorization",
+                        "description": "Admin API key with the identity.students.read scope",
+                        "name": "X-API-Key",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Student name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Student email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Student CPF, with or without punctuation",
+                        "name": "cpf",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "active",
+                            "suspended",
+                            "graduated",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Student status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Course ID",
+                        "name": "course_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First registration day, in the yyyy-mm-dd format",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last registration day, in the yyyy-mm-dd format",
+                        "name": "registered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor sent in the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size, up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentsPageResponse"
                         }
                     },
                     "401": {
@@ -168,6 +366,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.ValidationHTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -175,27 +379,735 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "pkg_gateways_httpserver.AuthenticateStudentRequest": {
-            "type": "object",
-            "properties": {
-                "secret": {
-                    "type": "string",
-                    "example": "celacanto-provoca-maremoto"
-                },
-                "student_id": {
-                    "type": "string",
-                    "example": "201210204310"
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Registration"
+                ],
+                "summary": "Register a student",
+                "parameters": [
+                    {
+                        "description": "Student creation information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentRegisterRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries sent with the same key and body get the first response replayed",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentRegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.ValidationHTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
                 }
             }
         },
-        "pkg_gateways_httpserver.AuthenticateStudentResponse": {
-            "type": "object",
-            "properties": {
-                "expires_at": {
+        "/v1/identities/students/imports": {
+            "post": {
+                "description": "The file is sent as the request body, a CSV must have a header with the registration request fields.\nEvery row is validated as a registration, valid ones are stored in batches and have their events published.\nThe report lists every row, send Accept text/csv to download it as a CSV file.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Administration"
+                ],
+                "summary": "Import students from a CSV or JSONL file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key with the identity.students.write scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file, nothing is stored",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "File format, taken from the Content-Type when not informed",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Import file",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentsImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.ValidationHTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/identities/students/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Authenticate a student",
+                "parameters": [
+                    {
+                        "description": "Student credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.AuthenticateStudentRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.AuthenticateStudentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/identities/students/verify-auth": {
+            "post": {
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verifies if Student Authentication is valid",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Registered service the token must have been issued to",
+                        "name": "audience",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.VerifyAuthenticationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/identities/students/{id}/data-export": {
+            "get": {
+                "description": "LGPD data subject access export, with the student data, courses, active sessions, login history\nand the events published about the student. Every export is audited.",
+                "produces": [
+                    "application/json",
+                    "application/zip",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Administration"
+                ],
+                "summary": "Export every personal data of a student",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key with the identity.students.export scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "zip"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_tccav_identity-service_pkg_gateways_dataexport.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/identities/students/{id}/erasure": {
+            "post": {
+                "description": "LGPD deletion, the student is pseudonymized and cancelled, its login history and sessions are deleted,\nthe key its events were encrypted with is destroyed and a student_erased event is published.\nErasing an already erased student completes any step left behind and returns the first erasure date.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Administration"
+                ],
+                "summary": "Erase every personal data of a student",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key with the identity.students.erase scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.StudentErasureResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/identities/students/{id}/status": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "Administration"
+                ],
+                "summary": "Change a student status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key with the identity.students.write scope",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Student ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.ChangeStudentStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language of the error messages, en or pt-BR",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.ChangeStudentStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/pkg_gateways_httpserver.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_tccav_identity-service_pkg_gateways_dataexport.Course": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "1f6a4d3a-38c7-43fe-9790-2408fe595c93"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ciência da Computação"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "open"
+                }
+            }
+        },
+        "github_com_tccav_identity-service_pkg_gateways_dataexport.Document": {
+            "type": "object",
+            "properties": {
+                "courses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tccav_identity-service_pkg_gateways_dataexport.Course"
+                    }
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tccav_identity-service_pkg_gateways_dataexport.Event"
+                    }
+                },
+                "generated_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-16T10:00:00Z"
+                },
+                "logins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tccav_identity-service_pkg_gateways_dataexport.Login"
+                    }
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_tccav_identity-service_pkg_gateways_dataexport.Session"
+                    }
+                },
+                "student": {
+                    "$ref": "#/definitions/github_com_tccav_identity-service_pkg_gateways_dataexport.Student"
+                }
+            }
+        },
+        "github_com_tccav_identity-service_pkg_gateways_dataexport.Event": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "string",
+                    "example": "6f0c1c4e-2b8e-4a8e-8f5d-3b0b7b8e9f10"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "student_registered"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "published_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-09T12:00:00Z"
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "identity.cdc.students.0"
+                }
+            }
+        },
+        "github_com_tccav_identity-service_pkg_gateways_dataexport.Login": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-16T10:00:00Z"
+                },
+                "failure_reason": {
+                    "type": "string",
+                    "example": "invalid_credentials"
+                },
+                "succeeded": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_tccav_identity-service_pkg_gateways_dataexport.Session": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-16T13:00:00Z"
+                },
+                "token_id": {
+                    "type": "string",
+                    "example": "0b7c5e0e-6c4a-4f4e-9d4c-0f0a3c3f4b1e"
+                }
+            }
+        },
+        "github_com_tccav_identity-service_pkg_gateways_dataexport.Student": {
+            "type": "object",
+            "properties": {
+                "birth_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "1990-10-18"
+                },
+                "course_id": {
+                    "type": "string",
+                    "example": "1f6a4d3a-38c7-43fe-9790-2408fe595c93"
+                },
+                "cpf": {
+                    "type": "string",
+                    "example": "11111111030"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "jdoe@ol.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "201210204310"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "registered_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-09T12:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.AuthenticateStudentRequest": {
+            "type": "object",
+            "properties": {
+                "audience": {
+                    "type": "string",
+                    "example": "courses-service"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "celacanto-provoca-maremoto"
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "201210204310"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.AuthenticateStudentResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
                     "type": "string",
                     "format": "datetime",
                     "example": "2023-10-18T19:32:00.000Z"
@@ -211,14 +1123,217 @@ const docTemplate = `{
                 }
             }
         },
+        "pkg_gateways_httpserver.ChangeStudentStatusRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Disciplinary suspension"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "active",
+                        "suspended",
+                        "graduated",
+                        "cancelled"
+                    ],
+                    "example": "suspended"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.ChangeStudentStatusResponse": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-10-18T19:32:00.000Z"
+                },
+                "previous_status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "suspended"
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "201210204310"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.DeadLetterHeaderResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "ce_type"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "student_registered"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.DeadLetterResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "error": {
+                    "type": "string",
+                    "example": "context deadline exceeded"
+                },
+                "event_id": {
+                    "type": "string",
+                    "format": "uuidv4",
+                    "example": "4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "student_registered"
+                },
+                "failed_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-08-06T10:00:00Z"
+                },
+                "headers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg_gateways_httpserver.DeadLetterHeaderResponse"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuidv4",
+                    "example": "0b7f2c8e-6a55-4d0e-9a57-3f0c1e9d2a41"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "201210204310"
+                },
+                "topic": {
+                    "type": "string",
+                    "example": "identity.cdc.students.0"
+                },
+                "value": {
+                    "type": "string",
+                    "format": "base64",
+                    "example": "eyJzdHVkZW50X2lkIjoiMjAxMjEwMjA0MzEwIn0="
+                }
+            }
+        },
+        "pkg_gateways_httpserver.DeadLettersPageResponse": {
+            "type": "object",
+            "properties": {
+                "dead_letters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg_gateways_httpserver.DeadLetterResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MjAyMy0wOC0wNlQxMDowMDowMFp8MGI3ZjJjOGU"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.FieldHTTPError": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "Invalid CPF was sent"
+                },
+                "err_code": {
+                    "type": "string",
+                    "example": "identity_service.error.invalid_cpf"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "cpf"
+                }
+            }
+        },
         "pkg_gateways_httpserver.HTTPError": {
             "type": "object",
             "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "Invalid CPF was sent"
+                },
                 "err_code": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "identity_service.error.invalid_cpf"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "identity-service/JhG6t0Vb2L-000001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Invalid CPF"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:identity-service:error:invalid_cpf"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.ImportRowResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg_gateways_httpserver.FieldHTTPError"
+                    }
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "valid",
+                        "imported",
+                        "event_failed",
+                        "failed"
+                    ],
+                    "example": "failed"
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "201210204310"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.StudentErasureResponse": {
+            "type": "object",
+            "properties": {
+                "erased_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-23T09:00:00Z"
+                },
+                "revoked_sessions": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "message": {
-                    "type": "string"
+                "student_id": {
+                    "type": "string",
+                    "example": "201210204310"
                 }
             }
         },
@@ -266,6 +1381,187 @@ const docTemplate = `{
                     "example": "201210204310"
                 }
             }
+        },
+        "pkg_gateways_httpserver.StudentResponse": {
+            "type": "object",
+            "properties": {
+                "birth_date": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "1990-10-18"
+                },
+                "course_id": {
+                    "type": "string",
+                    "format": "uuidv4",
+                    "example": "1f6a4d3a-38c7-43fe-9790-2408fe595c93"
+                },
+                "cpf": {
+                    "type": "string",
+                    "example": "11111111030"
+                },
+                "email": {
+                    "type": "string",
+                    "format": "email",
+                    "example": "jdoe@ol.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "201210204310"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "registered_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-07-09T12:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "active",
+                        "suspended",
+                        "graduated",
+                        "cancelled"
+                    ],
+                    "example": "active"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.StudentsImportResponse": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "event_failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg_gateways_httpserver.ImportRowResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "valid": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "pkg_gateways_httpserver.StudentsPageResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MjAyMy0wNy0wOVQxMjowMDowMFp8MjAxMjEwMjA0MzEw"
+                },
+                "students": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg_gateways_httpserver.StudentResponse"
+                    }
+                }
+            }
+        },
+        "pkg_gateways_httpserver.TokenClaims": {
+            "type": "object",
+            "properties": {
+                "course_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "1"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Maria Silva"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.ValidationHTTPError": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "Invalid CPF was sent"
+                },
+                "err_code": {
+                    "type": "string",
+                    "example": "identity_service.error.invalid_cpf"
+                },
+                "fields": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pkg_gateways_httpserver.FieldHTTPError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "identity-service/JhG6t0Vb2L-000001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Invalid CPF"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "urn:identity-service:error:invalid_cpf"
+                }
+            }
+        },
+        "pkg_gateways_httpserver.VerifyAuthenticationResponse": {
+            "type": "object",
+            "properties": {
+                "audience": {
+                    "type": "string",
+                    "example": "courses-service"
+                },
+                "claims": {
+                    "$ref": "#/definitions/pkg_gateways_httpserver.TokenClaims"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "format": "datetime",
+                    "example": "2023-10-18T19:32:00.000Z"
+                },
+                "student_id": {
+                    "type": "string",
+                    "example": "12345678910"
+                },
+                "token_id": {
+                    "type": "string",
+                    "format": "uuidv4",
+                    "example": "1f6a4d3a-38c7-43fe-9790-2408fe595c93"
+                }
+            }
         }
     }
 }`
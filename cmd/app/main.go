@@ -14,10 +14,8 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/extra/redisotel/v9"
-	goredis "github.com/redis/go-redis/v9"
 	httpswagger "github.com/swaggo/http-swagger"
 	"github.com/twmb/franz-go/pkg/kgo"
-	"github.com/twmb/franz-go/pkg/sasl/plain"
 	"github.com/twmb/franz-go/plugin/kotel"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
@@ -27,12 +25,15 @@ import (
 
 	_ "github.com/tccav/identity-service/api"
 	"github.com/tccav/identity-service/pkg/config"
+	"github.com/tccav/identity-service/pkg/domain/identities"
 	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
 	"github.com/tccav/identity-service/pkg/gateways/httpserver"
+	"github.com/tccav/identity-service/pkg/gateways/inmemory"
 	"github.com/tccav/identity-service/pkg/gateways/kafka"
 	"github.com/tccav/identity-service/pkg/gateways/opentelemetry"
 	"github.com/tccav/identity-service/pkg/gateways/postgres"
 	"github.com/tccav/identity-service/pkg/gateways/redis"
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
 )
 
 var (
@@ -100,15 +101,26 @@ func main() {
 	defer pool.Close()
 	logger.Info("db conn pool fetched")
 
-	redisOptions := goredis.Options{
-		Addr: configs.MemoryDB.URL(),
-	}
-	if configs.MemoryDB.User != "" {
-		redisOptions.Username = configs.MemoryDB.User
-		redisOptions.Password = configs.MemoryDB.Password
+	redisClient, err := redis.NewUniversalClient(redis.Connection{
+		Mode:             configs.MemoryDB.Mode,
+		Addresses:        configs.MemoryDB.Addresses,
+		MasterName:       configs.MemoryDB.MasterName,
+		Username:         configs.MemoryDB.User,
+		Password:         configs.MemoryDB.Password,
+		SentinelUsername: configs.MemoryDB.SentinelUser,
+		SentinelPassword: configs.MemoryDB.SentinelPassword,
+		TLS: tlsconfig.Config{
+			Enabled:  configs.MemoryDB.TLSEnabled,
+			CAFile:   configs.MemoryDB.TLSCAFile,
+			CertFile: configs.MemoryDB.TLSCertFile,
+			KeyFile:  configs.MemoryDB.TLSKeyFile,
+		},
+	})
+	if err != nil {
+		logger.Error("invalid memory db connection configs", zap.Error(err))
+		return
 	}
 
-	redisClient := goredis.NewClient(&redisOptions)
 	err = redisClient.Ping(ctx).Err()
 	if err != nil {
 		logger.Error("failed to fetch memory db conn", zap.Error(err))
@@ -139,16 +151,37 @@ func main() {
 	}
 	kotelService := kotel.NewKotel(kotelOps...)
 
-	kOpts := []kgo.Opt{
-		kgo.SeedBrokers(configs.Kafka.URL()),
-		kgo.WithHooks(kotelService.Hooks()...),
+	kOpts, err := kafka.ConnectionOptions(kafka.Connection{
+		Brokers: configs.Kafka.Brokers,
+		TLS: tlsconfig.Config{
+			Enabled:  configs.Kafka.TLSEnabled,
+			CAFile:   configs.Kafka.TLSCAFile,
+			CertFile: configs.Kafka.TLSCertFile,
+			KeyFile:  configs.Kafka.TLSKeyFile,
+		},
+		SASL: kafka.SASLConfig{
+			Mechanism:      configs.Kafka.SASLMechanism,
+			User:           configs.Kafka.User,
+			Password:       configs.Kafka.Password,
+			OAuthToken:     configs.Kafka.OAuthToken,
+			OAuthTokenFile: configs.Kafka.OAuthTokenFile,
+		},
+	})
+	if err != nil {
+		logger.Error("invalid kafka connection configs", zap.Error(err))
+		return
 	}
-	if configs.Kafka.User != "" {
-		kOpts = append(kOpts, kgo.SASL(plain.Auth{
-			User: configs.Kafka.User,
-			Pass: configs.Kafka.Password,
-		}.AsMechanism()))
+	kOpts = append(kOpts,
+		kgo.WithHooks(kotelService.Hooks()...),
+		kgo.RecordDeliveryTimeout(configs.Kafka.DeliveryTimeout),
+	)
+
+	producerOpts, err := kafka.ProducerOptions(configs.Kafka.ProducerAcks, configs.Kafka.IdempotentWrites)
+	if err != nil {
+		logger.Error("invalid kafka producer configs", zap.Error(err))
+		return
 	}
+	kOpts = append(kOpts, producerOpts...)
 
 	kafkaClient, err := kgo.NewClient(kOpts...)
 	if err != nil {
@@ -164,18 +197,189 @@ func main() {
 	}
 	logger.Info("kafka client created")
 
-	producer := kafka.NewProducer(kafkaClient)
+	// kOpts is shared with the other clients, appending must not write to its backing array
+	consumerOpts := append(kOpts[:len(kOpts):len(kOpts)],
+		kgo.ConsumerGroup(configs.Kafka.ConsumerGroup),
+		kgo.ConsumeTopics(configs.Kafka.CoursesTopic),
+		kgo.DisableAutoCommit(),
+	)
+	coursesClient, err := kgo.NewClient(consumerOpts...)
+	if err != nil {
+		logger.Error("unable to create kafka consumer", zap.Error(err))
+		return
+	}
+	defer coursesClient.Close()
+	logger.Info("kafka consumer client created")
+
+	var schemaRegistry kafka.SchemaRegistry
+	if configs.Kafka.SchemaRegistryURL != "" {
+		schemaRegistry = kafka.NewSchemaRegistryClient(
+			configs.Kafka.SchemaRegistryURL,
+			configs.Kafka.SchemaRegistryUser,
+			configs.Kafka.SchemaRegistryPassword,
+		)
+	}
+
+	serializer, err := kafka.NewSerializer(configs.Kafka.Serializer, schemaRegistry)
+	if err != nil {
+		logger.Error("unable to create kafka events serializer", zap.Error(err))
+		return
+	}
 
-	studentsProducer := kafka.NewStudentsProducer(producer)
+	deadLettersRepository := postgres.NewDeadLettersRepository(pool)
+	retryPolicy := kafka.RetryPolicy{
+		Attempts:   configs.Kafka.PublishAttempts,
+		Backoff:    configs.Kafka.PublishBackoff,
+		MaxBackoff: configs.Kafka.PublishMaxBackoff,
+	}
+	producer, err := kafka.NewProducer(kafkaClient, serializer).Resilient(retryPolicy, deadLettersRepository, logger)
+	if err != nil {
+		logger.Error("unable to create kafka producer", zap.Error(err))
+		return
+	}
 
-	repository := postgres.NewStudentsRepository(pool)
-	tokenRepository := redis.NewTokensRepository(redisClient)
+	err = kafka.RegisterQueueMetrics(kafkaClient, deadLettersRepository)
+	if err != nil {
+		logger.Error("failed to init kafka producer metrics", zap.Error(err))
+		return
+	}
 
-	useCase := idusecases.NewRegisterUseCase(repository, studentsProducer)
-	authUseCase := idusecases.NewStudentJWTAuthenticator(repository, tokenRepository, configs.Auth)
+	keyring, err := configs.Encryption.Keyring()
+	if err != nil {
+		logger.Error("failed to load encryption master key", zap.Error(err))
+		return
+	}
+
+	repository := postgres.NewStudentsRepository(pool, keyring)
+	keysRepository := postgres.NewStudentKeysRepository(pool)
+	coursesRepository := postgres.NewCoursesRepository(pool)
+	var (
+		tokenRepository  identities.TokensRepository
+		runTokensCleanup func(ctx context.Context)
+	)
+	switch configs.Tokens.Backend {
+	case config.TokensBackendRedis:
+		tokenRepository = redis.NewTokensRepository(redisClient)
+	case config.TokensBackendPostgres:
+		postgresTokens := postgres.NewTokensRepository(pool)
+		tokenRepository = postgresTokens
+		runTokensCleanup = func(ctx context.Context) {
+			postgresTokens.RunCleanup(ctx, configs.Tokens.CleanupInterval, func(err error) {
+				logger.Error("failed to delete expired tokens", zap.Error(err))
+			})
+		}
+	case config.TokensBackendMemory:
+		memoryTokens := inmemory.NewTokensRepository()
+		tokenRepository = memoryTokens
+		runTokensCleanup = func(ctx context.Context) {
+			memoryTokens.RunEviction(ctx, configs.Tokens.CleanupInterval)
+		}
+	default:
+		logger.Error("unknown tokens backend", zap.String("backend", configs.Tokens.Backend))
+		return
+	}
+	logger.Info("tokens store selected", zap.String("backend", configs.Tokens.Backend))
+	var (
+		sessionsRepository identities.SessionsRepository = tokenRepository
+		revocationList     *redis.TokenRevocationList
+	)
+	switch configs.Tokens.VerificationMode {
+	case config.TokenVerificationStored, config.TokenVerificationStateless:
+	default:
+		logger.Error("unknown token verification mode", zap.String("mode", configs.Tokens.VerificationMode))
+		return
+	}
+	if configs.Tokens.PublishesRevocations() {
+		list := redis.NewTokenRevocationList(redisClient)
+		// tokens revoked before the replica started must not verify, it only serves once they are listed
+		if err = list.Load(ctx); err != nil {
+			logger.Error("failed to load token revocations", zap.Error(err))
+			return
+		}
+		revocationList = &list
+		sessionsRepository = idusecases.RevokingSessions(tokenRepository, list)
+	}
+	logger.Info("token verification mode selected",
+		zap.String("mode", configs.Tokens.VerificationMode),
+		zap.Int("cache_size", configs.Tokens.VerificationCacheSize),
+	)
+	loginsRepository := postgres.NewLoginHistoryRepository(pool)
+	auditRepository := postgres.NewAuditRepository(pool)
+
+	studentsProducer := kafka.NewStudentsProducer(producer, keysRepository)
+	authenticationProducer := kafka.NewAuthenticationProducer(producer, logger)
+
+	useCase := idusecases.NewRegisterUseCase(repository, coursesRepository, studentsProducer)
+	courseCatalogUseCase := idusecases.NewCourseCatalogUseCase(coursesRepository)
+	statusUseCase := idusecases.NewStudentStatusUseCase(repository, sessionsRepository, studentsProducer)
+	searchUseCase := idusecases.NewStudentsSearchUseCase(repository)
+	eventsReader, err := kafka.NewStudentEventsReader(keysRepository, schemaRegistry, kOpts...)
+	if err != nil {
+		logger.Error("unable to create students events reader", zap.Error(err))
+		return
+	}
+	dataExportUseCase := idusecases.NewDataExportUseCase(
+		repository,
+		coursesRepository,
+		tokenRepository,
+		loginsRepository,
+		eventsReader,
+		auditRepository,
+	)
+	erasureUseCase := idusecases.NewStudentErasureUseCase(
+		repository,
+		sessionsRepository,
+		studentsProducer,
+		authenticationProducer,
+		auditRepository,
+	)
+	importUseCase := idusecases.NewStudentsImportUseCase(repository, coursesRepository, studentsProducer, configs.Import)
+	deadLettersUseCase := idusecases.NewDeadLettersUseCase(deadLettersRepository, producer, auditRepository)
+	authUseCase := idusecases.NewStudentJWTAuthenticator(
+		repository,
+		tokenRepository,
+		loginsRepository,
+		authenticationProducer,
+		configs.Auth,
+	)
+	if len(configs.Auth.Claims) > 0 {
+		authUseCase, err = authUseCase.WithClaims(repository, configs.Auth)
+		if err != nil {
+			logger.Error("failed to init token claims", zap.Error(err))
+			return
+		}
+	}
+	if configs.Tokens.VerificationMode == config.TokenVerificationStateless {
+		authUseCase = authUseCase.Stateless(*revocationList)
+	}
+	if configs.Tokens.VerificationCacheSize > 0 {
+		authUseCase, err = authUseCase.Cached(
+			*revocationList,
+			configs.Tokens.VerificationCacheSize,
+			configs.Tokens.VerificationCacheMaxTTL,
+		)
+		if err != nil {
+			logger.Error("failed to init token verification cache", zap.Error(err))
+			return
+		}
+	}
+
+	clientIPs, err := httpserver.NewClientIPs(configs.API.TrustedProxies)
+	if err != nil {
+		logger.Error("failed to init client ips", zap.Error(err))
+		return
+	}
 
 	studentsHandler := httpserver.NewStudentsHandler(useCase, logger)
-	authHandler := httpserver.NewAuthenticationHandler(logger, authUseCase)
+	authHandler := httpserver.NewAuthenticationHandler(logger, authUseCase, clientIPs)
+	statusHandler := httpserver.NewStudentStatusHandler(logger, statusUseCase)
+	importHandler := httpserver.NewStudentsImportHandler(logger, importUseCase, configs.Import)
+	searchHandler := httpserver.NewStudentsSearchHandler(logger, searchUseCase)
+	dataExportHandler := httpserver.NewDataExportHandler(logger, dataExportUseCase)
+	erasureHandler := httpserver.NewStudentErasureHandler(logger, erasureUseCase)
+	deadLettersHandler := httpserver.NewDeadLettersHandler(logger, deadLettersUseCase)
+	adminAuthorizer := httpserver.NewAdminAuthorizer(logger, configs.Admin.APIKeys)
+	idempotency := httpserver.NewIdempotency(logger, redis.NewIdempotencyRepository(redisClient), configs.Idempotency)
 
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
@@ -186,9 +390,26 @@ func main() {
 	if configs.Swagger.Enabled {
 		router.Get("/docs/*", httpswagger.Handler())
 	}
-	router.MethodFunc(http.MethodPost, "/v1/identities/students", studentsHandler.RegisterStudent)
+	router.With(idempotency.Handler).
+		MethodFunc(http.MethodPost, "/v1/identities/students", studentsHandler.RegisterStudent)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeStudentsRead)).
+		MethodFunc(http.MethodGet, "/v1/identities/students", searchHandler.SearchStudents)
 	router.MethodFunc(http.MethodPost, "/v1/identities/students/login", authHandler.AuthenticateStudent)
 	router.MethodFunc(http.MethodPost, "/v1/identities/students/verify-auth", authHandler.VerifyAuthentication)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeStudentsWrite)).
+		MethodFunc(http.MethodPost, "/v1/identities/students/{id}/status", statusHandler.ChangeStudentStatus)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeStudentsWrite)).
+		MethodFunc(http.MethodPost, "/v1/identities/students/imports", importHandler.ImportStudents)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeStudentsExport)).
+		MethodFunc(http.MethodGet, "/v1/identities/students/{id}/data-export", dataExportHandler.ExportStudentData)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeStudentsErase)).
+		MethodFunc(http.MethodPost, "/v1/identities/students/{id}/erasure", erasureHandler.EraseStudent)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeEventsAdmin)).
+		MethodFunc(http.MethodGet, "/v1/identities/dead-letters", deadLettersHandler.ListDeadLetters)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeEventsAdmin)).
+		MethodFunc(http.MethodPost, "/v1/identities/dead-letters/{id}/retry", deadLettersHandler.RetryDeadLetter)
+	router.With(adminAuthorizer.RequireScope(httpserver.ScopeEventsAdmin)).
+		MethodFunc(http.MethodDelete, "/v1/identities/dead-letters/{id}", deadLettersHandler.DiscardDeadLetter)
 	router.Get("/healthcheck", httpserver.Healthcheck)
 	logger.Info("handlers and routes configured")
 
@@ -208,7 +429,31 @@ func main() {
 	notifyContext, stop := signal.NotifyContext(ctx, os.Kill, os.Interrupt)
 	defer stop()
 
+	if runTokensCleanup != nil {
+		go runTokensCleanup(notifyContext)
+	}
+	if revocationList != nil {
+		go revocationList.Run(notifyContext, configs.Tokens.RevocationsEvictionInterval, func(err error) {
+			logger.Error("failed to receive token revocations", zap.Error(err))
+		})
+	}
+
+	coursesConsumer := kafka.NewCoursesConsumer(coursesClient, courseCatalogUseCase, logger)
+	go coursesConsumer.Run(notifyContext)
+
+	// the activity queue is stopped once the server is shut down rather than on the signal, to publish the
+	// activity of the requests served while shutting down
+	activityCtx, stopActivity := context.WithCancel(ctx)
+	defer stopActivity()
+	activityDone := make(chan struct{})
+	go func() {
+		authenticationProducer.Run(activityCtx)
+		close(activityDone)
+	}()
+
+	shutdownDone := make(chan struct{})
 	go func(sigCtx context.Context) {
+		defer close(shutdownDone)
 		<-sigCtx.Done()
 		logger.Info("shutdown signal received")
 		shutdownCtx, c := context.WithTimeout(ctx, 30*time.Second)
@@ -227,4 +472,16 @@ func main() {
 		logger.Error("server listening has failed", zap.Error(err))
 		return
 	}
+
+	// the authentication activity is published asynchronously, what is still queued or buffered is sent before
+	// closing
+	<-shutdownDone
+	stopActivity()
+	<-activityDone
+	flushCtx, cancelFlush := context.WithTimeout(ctx, 10*time.Second)
+	defer cancelFlush()
+	err = kafkaClient.Flush(flushCtx)
+	if err != nil {
+		logger.Error("failed to flush kafka producer", zap.Error(err))
+	}
 }
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/config"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+)
+
+func newDBPool(ctx context.Context) (*pgxpool.Pool, error) {
+	dbConfigs, err := config.LoadDBConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.New(ctx, dbConfigs.URL())
+	if err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// newStudentsRepository loads the master key the students personal data is encrypted with.
+func newStudentsRepository(pool *pgxpool.Pool) (postgres.StudentsRepository, error) {
+	encryptionConfigs, err := config.LoadEncryptionConfigs()
+	if err != nil {
+		return postgres.StudentsRepository{}, err
+	}
+
+	keyring, err := encryptionConfigs.Keyring()
+	if err != nil {
+		return postgres.StudentsRepository{}, err
+	}
+
+	return postgres.NewStudentsRepository(pool, keyring), nil
+}
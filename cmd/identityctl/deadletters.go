@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+)
+
+func runDeadLettersList(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("dead-letters list", flag.ContinueOnError)
+	topic := flags.String("topic", "", "only lists the dead letters of the topic")
+	format := flags.String("format", "text", "output format, text or json")
+	if err := flags.Parse(args); err != nil || (*format != "text" && *format != "json") {
+		return errUsage
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	useCase := idusecases.NewDeadLettersUseCase(postgres.NewDeadLettersRepository(pool), nil, nil)
+
+	var deadLetters []entities.DeadLetter
+	err = eachDeadLetters(ctx, useCase, *topic, func(page []entities.DeadLetter) error {
+		deadLetters = append(deadLetters, page...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("dead letters listed", zap.Int("dead_letters", len(deadLetters)))
+
+	if *format == "json" {
+		return json.NewEncoder(os.Stdout).Encode(deadLetters)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTOPIC\tEVENT TYPE\tKEY\tATTEMPTS\tFAILED AT\tERROR")
+	for _, deadLetter := range deadLetters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", deadLetter.ID, deadLetter.Topic, deadLetter.EventType, deadLetter.Key,
+			deadLetter.Attempts, deadLetter.FailedAt.Format(time.RFC3339), deadLetter.Error)
+	}
+	return w.Flush()
+}
+
+// runDeadLettersRetry publishes a dead letter, or every one of them, again. The ones failing once more are kept
+// with the failure recorded.
+func runDeadLettersRetry(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("dead-letters retry", flag.ContinueOnError)
+	id := flags.String("id", "", "dead letter id")
+	all := flags.Bool("all", false, "retries every dead letter, or every one of the topic")
+	topic := flags.String("topic", "", "only retries the dead letters of the topic, along with -all")
+	actor := flags.String("actor", "", "who asked for the retry, recorded in the audit log, defaults to the operator user")
+	if err := flags.Parse(args); err != nil || (*id == "") == !*all {
+		return errUsage
+	}
+
+	if *actor == "" {
+		*actor = operatorActor()
+	}
+
+	kOpts, err := kafkaOptions()
+	if err != nil {
+		return err
+	}
+
+	policy, err := retryPolicy()
+	if err != nil {
+		return err
+	}
+
+	serializer, _, err := eventsSerializer()
+	if err != nil {
+		return err
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	kafkaClient, err := newKafkaClient(ctx, kOpts...)
+	if err != nil {
+		return err
+	}
+	defer kafkaClient.Close()
+
+	repository := postgres.NewDeadLettersRepository(pool)
+	producer, err := kafka.NewProducer(kafkaClient, serializer).Resilient(policy, repository, logger)
+	if err != nil {
+		return err
+	}
+	useCase := idusecases.NewDeadLettersUseCase(repository, producer, postgres.NewAuditRepository(pool))
+
+	if !*all {
+		err = useCase.RetryDeadLetter(ctx, identities.DeadLetterInput{ID: *id, Actor: *actor})
+		if err != nil {
+			return err
+		}
+		logger.Info("dead letter published", zap.String("dead_letter_id", *id))
+		return nil
+	}
+
+	var published, failed int
+	err = eachDeadLetters(ctx, useCase, *topic, func(page []entities.DeadLetter) error {
+		for _, deadLetter := range page {
+			err := useCase.RetryDeadLetter(ctx, identities.DeadLetterInput{ID: deadLetter.ID, Actor: *actor})
+			if err != nil {
+				logger.Error("failed to publish dead letter", zap.String("dead_letter_id", deadLetter.ID), zap.Error(err))
+				failed++
+				continue
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("dead letters retried", zap.Int("published", published), zap.Int("failed", failed))
+	return nil
+}
+
+func runDeadLettersDiscard(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("dead-letters discard", flag.ContinueOnError)
+	id := flags.String("id", "", "dead letter id")
+	actor := flags.String("actor", "", "who asked for the discard, recorded in the audit log, defaults to the operator user")
+	if err := flags.Parse(args); err != nil || *id == "" {
+		return errUsage
+	}
+
+	if *actor == "" {
+		*actor = operatorActor()
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	useCase := idusecases.NewDeadLettersUseCase(postgres.NewDeadLettersRepository(pool), nil, postgres.NewAuditRepository(pool))
+
+	err = useCase.DiscardDeadLetter(ctx, identities.DeadLetterInput{ID: *id, Actor: *actor})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("dead letter discarded", zap.String("dead_letter_id", *id), zap.String("actor", *actor))
+	return nil
+}
+
+// eachDeadLetters pages through the dead letters of the topic, or of every topic when empty. Each page is
+// listed before it is handled, so the dead letters deleted while handling it do not break the paging.
+func eachDeadLetters(ctx context.Context, useCase idusecases.DeadLettersUseCase, topic string, handle func([]entities.DeadLetter) error) error {
+	input := identities.ListDeadLettersInput{Topic: topic, Limit: 100}
+	for {
+		page, err := useCase.ListDeadLetters(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		if err = handle(page.DeadLetters); err != nil {
+			return err
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		input.Cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func runStudentsEncrypt(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("students encrypt", flag.ContinueOnError)
+	batchSize := flags.Int("batch-size", 500, "students encrypted per batch")
+	pause := flags.Duration("pause", 100*time.Millisecond, "pause between batches, easing the load on the database while the app runs")
+	after := flags.String("after", "", "student id to resume from, the last id logged by a previous run")
+	if err := flags.Parse(args); err != nil || *batchSize <= 0 {
+		return errUsage
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	repository, err := newStudentsRepository(pool)
+	if err != nil {
+		return err
+	}
+
+	var encrypted, conflicts int
+	lastID := *after
+	for {
+		batch, err := repository.EncryptStudents(ctx, lastID, *batchSize)
+		if err != nil {
+			return err
+		}
+		if batch.LastID == "" {
+			break
+		}
+
+		lastID = batch.LastID
+		encrypted += batch.Encrypted
+		conflicts += len(batch.Conflicts)
+		for _, id := range batch.Conflicts {
+			logger.Warn("student shares its cpf or email with an encrypted one, left unencrypted", zap.String("student_id", id))
+		}
+		logger.Info("students batch encrypted", zap.String("last_id", lastID), zap.Int("encrypted", encrypted))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(*pause):
+		}
+	}
+
+	logger.Info("students encryption finished", zap.Int("encrypted", encrypted), zap.Int("conflicts", conflicts))
+	return nil
+}
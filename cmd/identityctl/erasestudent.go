@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+)
+
+func runStudentsErase(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("students erase", flag.ContinueOnError)
+	id := flags.String("id", "", "student id")
+	actor := flags.String("actor", "", "who asked for the erasure, recorded in the audit log, defaults to the operator user")
+	if err := flags.Parse(args); err != nil || *id == "" {
+		return errUsage
+	}
+
+	if *actor == "" {
+		*actor = operatorActor()
+	}
+
+	kOpts, err := kafkaOptions()
+	if err != nil {
+		return err
+	}
+
+	serializer, _, err := eventsSerializer()
+	if err != nil {
+		return err
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	studentsRepository, err := newStudentsRepository(pool)
+	if err != nil {
+		return err
+	}
+
+	tokensRepository, closeTokens, err := newTokensRepository(ctx, pool)
+	if err != nil {
+		return err
+	}
+	defer closeTokens()
+
+	sessionsRepository, closeRevocations, err := withRevocations(ctx, tokensRepository)
+	if err != nil {
+		return err
+	}
+	defer closeRevocations()
+
+	kafkaClient, err := newKafkaClient(ctx, kOpts...)
+	if err != nil {
+		return err
+	}
+	defer kafkaClient.Close()
+
+	producer := kafka.NewProducer(kafkaClient, serializer)
+	authenticationProducer := kafka.NewAuthenticationProducer(producer, logger)
+	useCase := idusecases.NewStudentErasureUseCase(
+		studentsRepository,
+		sessionsRepository,
+		kafka.NewStudentsProducer(producer, postgres.NewStudentKeysRepository(pool)),
+		authenticationProducer,
+		postgres.NewAuditRepository(pool),
+	)
+
+	report, err := useCase.EraseStudent(ctx, identities.EraseStudentInput{
+		StudentID: *id,
+		Actor:     *actor,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the token revocation is queued and published asynchronously, Run publishes what is queued and returns
+	// once its context is done
+	queueCtx, stopQueue := context.WithCancel(ctx)
+	stopQueue()
+	authenticationProducer.Run(queueCtx)
+	if err = kafkaClient.Flush(ctx); err != nil {
+		logger.Error("failed to publish token revocation", zap.Error(err))
+	}
+
+	logger.Info("student erased",
+		zap.String("student_id", report.Erasure.StudentID),
+		zap.String("actor", *actor),
+		zap.Time("erased_at", report.Erasure.ErasedAt),
+		zap.Int("revoked_sessions", report.RevokedSessions),
+	)
+	return nil
+}
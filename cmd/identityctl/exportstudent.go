@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/user"
+
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+	"github.com/tccav/identity-service/pkg/gateways/dataexport"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+)
+
+func runStudentsExport(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("students export", flag.ContinueOnError)
+	id := flags.String("id", "", "student id")
+	format := flags.String("format", dataexport.FormatJSON, "export format, json or zip")
+	out := flags.String("out", "", "path of the export file, named after the student when not informed")
+	actor := flags.String("actor", "", "who asked for the export, recorded in the audit log, defaults to the operator user")
+	if err := flags.Parse(args); err != nil || *id == "" {
+		return errUsage
+	}
+
+	if _, err := dataexport.ContentType(*format); err != nil {
+		return errUsage
+	}
+
+	if *actor == "" {
+		*actor = operatorActor()
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	studentsRepository, err := newStudentsRepository(pool)
+	if err != nil {
+		return err
+	}
+
+	tokensRepository, closeTokens, err := newTokensRepository(ctx, pool)
+	if err != nil {
+		return err
+	}
+	defer closeTokens()
+
+	kOpts, err := kafkaOptions()
+	if err != nil {
+		return err
+	}
+
+	_, registry, err := eventsSerializer()
+	if err != nil {
+		return err
+	}
+
+	eventsReader, err := kafka.NewStudentEventsReader(postgres.NewStudentKeysRepository(pool), registry, kOpts...)
+	if err != nil {
+		return err
+	}
+
+	useCase := idusecases.NewDataExportUseCase(
+		studentsRepository,
+		postgres.NewCoursesRepository(pool),
+		tokensRepository,
+		postgres.NewLoginHistoryRepository(pool),
+		eventsReader,
+		postgres.NewAuditRepository(pool),
+	)
+
+	export, err := useCase.ExportStudentData(ctx, identities.ExportStudentDataInput{
+		StudentID: *id,
+		Actor:     *actor,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		*out = dataexport.FileName(export, *format)
+	}
+
+	// the export holds personal data, so only the operator can read it
+	f, err := os.OpenFile(*out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = dataexport.Write(f, export, *format)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("student data exported", zap.String("student_id", *id), zap.String("actor", *actor), zap.String("file", *out))
+	return f.Close()
+}
+
+// operatorActor identifies the user running the command in the audit log.
+func operatorActor() string {
+	u, err := user.Current()
+	if err != nil {
+		return "cli:unknown"
+	}
+	return "cli:" + u.Username
+}
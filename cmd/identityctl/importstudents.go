@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/config"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+	"github.com/tccav/identity-service/pkg/gateways/importfile"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+)
+
+func runStudentsImport(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("students import", flag.ContinueOnError)
+	file := flags.String("file", "", "path of the CSV or JSONL file, - reads from stdin")
+	format := flags.String("format", "", "file format, csv or jsonl, taken from the file extension when not informed")
+	dryRun := flags.Bool("dry-run", false, "only validate the file, nothing is stored nor published")
+	report := flags.String("report", "-", "path of the CSV report with the result of each row, - writes to stdout")
+	if err := flags.Parse(args); err != nil || *file == "" {
+		return errUsage
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	reader, err := importfile.NewReader(*format, in)
+	if err != nil {
+		return err
+	}
+
+	importConfigs, err := config.LoadImportConfigs()
+	if err != nil {
+		return err
+	}
+
+	kOpts, err := kafkaOptions()
+	if err != nil {
+		return err
+	}
+
+	serializer, _, err := eventsSerializer()
+	if err != nil {
+		return err
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	studentsRepository, err := newStudentsRepository(pool)
+	if err != nil {
+		return err
+	}
+
+	kafkaClient, err := newKafkaClient(ctx, kOpts...)
+	if err != nil {
+		return err
+	}
+	defer kafkaClient.Close()
+
+	useCase := idusecases.NewStudentsImportUseCase(
+		studentsRepository,
+		postgres.NewCoursesRepository(pool),
+		kafka.NewStudentsProducer(kafka.NewProducer(kafkaClient, serializer), postgres.NewStudentKeysRepository(pool)),
+		importConfigs,
+	)
+
+	result, err := useCase.ImportStudents(ctx, identities.ImportStudentsInput{
+		Rows:   reader,
+		DryRun: *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	logger.Info("students import finished",
+		zap.Bool("dry_run", result.DryRun),
+		zap.Int("total", len(result.Rows)),
+		zap.Int("valid", result.Count(identities.ImportRowValid)),
+		zap.Int("imported", result.Count(identities.ImportRowImported)),
+		zap.Int("event_failed", result.Count(identities.ImportRowEventFailed)),
+		zap.Int("failed", result.Count(identities.ImportRowFailed)),
+	)
+
+	out := os.Stdout
+	if *report != "-" {
+		out, err = os.Create(*report)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	return writeImportReport(out, result)
+}
+
+func writeImportReport(w io.Writer, report identities.ImportReport) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"line", "student_id", "status", "error"})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		var rowErr string
+		if row.Err != nil {
+			rowErr = row.Err.Error()
+		}
+
+		err = writer.Write([]string{strconv.Itoa(row.Line), row.StudentID, string(row.Status), rowErr})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
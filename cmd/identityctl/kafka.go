@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/tccav/identity-service/pkg/config"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
+)
+
+func kafkaOptions() ([]kgo.Opt, error) {
+	kafkaConfigs, err := config.LoadKafkaConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := kafka.ConnectionOptions(kafka.Connection{
+		Brokers: kafkaConfigs.Brokers,
+		TLS: tlsconfig.Config{
+			Enabled:  kafkaConfigs.TLSEnabled,
+			CAFile:   kafkaConfigs.TLSCAFile,
+			CertFile: kafkaConfigs.TLSCertFile,
+			KeyFile:  kafkaConfigs.TLSKeyFile,
+		},
+		SASL: kafka.SASLConfig{
+			Mechanism:      kafkaConfigs.SASLMechanism,
+			User:           kafkaConfigs.User,
+			Password:       kafkaConfigs.Password,
+			OAuthToken:     kafkaConfigs.OAuthToken,
+			OAuthTokenFile: kafkaConfigs.OAuthTokenFile,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, kgo.RecordDeliveryTimeout(kafkaConfigs.DeliveryTimeout))
+
+	producerOpts, err := kafka.ProducerOptions(kafkaConfigs.ProducerAcks, kafkaConfigs.IdempotentWrites)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(opts, producerOpts...), nil
+}
+
+// retryPolicy is how many times a record is published before it is given up on.
+func retryPolicy() (kafka.RetryPolicy, error) {
+	kafkaConfigs, err := config.LoadKafkaConfigs()
+	if err != nil {
+		return kafka.RetryPolicy{}, err
+	}
+
+	return kafka.RetryPolicy{
+		Attempts:   kafkaConfigs.PublishAttempts,
+		Backoff:    kafkaConfigs.PublishBackoff,
+		MaxBackoff: kafkaConfigs.PublishMaxBackoff,
+	}, nil
+}
+
+// eventsSerializer builds the serializer events are published with, the registry is nil when none is
+// configured.
+func eventsSerializer() (kafka.Serializer, kafka.SchemaRegistry, error) {
+	kafkaConfigs, err := config.LoadKafkaConfigs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var registry kafka.SchemaRegistry
+	if kafkaConfigs.SchemaRegistryURL != "" {
+		registry = kafka.NewSchemaRegistryClient(
+			kafkaConfigs.SchemaRegistryURL,
+			kafkaConfigs.SchemaRegistryUser,
+			kafkaConfigs.SchemaRegistryPassword,
+		)
+	}
+
+	serializer, err := kafka.NewSerializer(kafkaConfigs.Serializer, registry)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return serializer, registry, nil
+}
+
+func newKafkaClient(ctx context.Context, opts ...kgo.Opt) (*kgo.Client, error) {
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.Ping(ctx)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
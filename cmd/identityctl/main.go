@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var (
+	AppVersion = "unknown"
+	GoVersion  = "unknown"
+	Time       = "unknown"
+)
+
+var errUsage = errors.New("invalid usage")
+
+// command is an identityctl operation, identified by its path of words (e.g. "students duplicates").
+type command struct {
+	path  string
+	usage string
+	run   func(ctx context.Context, logger *zap.Logger, args []string) error
+}
+
+var commands = []command{
+	{
+		path:  "students duplicates",
+		usage: "lists students sharing the same CPF or email",
+		run:   runStudentsDuplicates,
+	},
+	{
+		path:  "students encrypt",
+		usage: "encrypts the personal data of the students stored before it was encrypted, it can run along with the app",
+		run:   runStudentsEncrypt,
+	},
+	{
+		path:  "students import",
+		usage: "imports students from a CSV or JSONL file, reporting the result of each row",
+		run:   runStudentsImport,
+	},
+	{
+		path:  "students export",
+		usage: "exports every personal data of a student (LGPD data subject access), the export is audited",
+		run:   runStudentsExport,
+	},
+	{
+		path:  "students erase",
+		usage: "erases every personal data of a student (LGPD deletion), the erasure is audited",
+		run:   runStudentsErase,
+	},
+	{
+		path:  "events replay",
+		usage: "republishes the stored students as events marked as replayed, in throttled batches by student id",
+		run:   runEventsReplay,
+	},
+	{
+		path:  "dead-letters list",
+		usage: "lists the events that could not be published after every retry",
+		run:   runDeadLettersList,
+	},
+	{
+		path:  "dead-letters retry",
+		usage: "publishes a dead-lettered event, or every one of them, again, the retry is audited",
+		run:   runDeadLettersRetry,
+	},
+	{
+		path:  "dead-letters discard",
+		usage: "deletes a dead-lettered event without publishing it, the discard is audited",
+		run:   runDeadLettersDiscard,
+	},
+}
+
+func main() {
+	logConfig := zap.NewProductionConfig()
+	logConfig.DisableStacktrace = true
+	logConfig.OutputPaths = []string{"stderr"}
+
+	logger, err := logConfig.Build(
+		zap.Fields(
+			zap.String("version", AppVersion),
+			zap.String("go_version", GoVersion),
+			zap.String("build_time", Time),
+		),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("unable to initialize logger: %s", err))
+	}
+	defer func(logger *zap.Logger) {
+		_ = logger.Sync()
+	}(logger)
+
+	cmd, args, ok := findCommand(os.Args[1:])
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err = cmd.run(ctx, logger.With(zap.String("command", cmd.path)), args)
+	if err != nil {
+		if errors.Is(err, errUsage) {
+			printUsage()
+			os.Exit(2)
+		}
+		logger.Error("command failed", zap.String("command", cmd.path), zap.Error(err))
+		os.Exit(1)
+	}
+}
+
+func findCommand(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.path)
+		if len(args) < len(words) {
+			continue
+		}
+		if strings.Join(args[:len(words)], " ") == cmd.path {
+			return cmd, args[len(words):], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: identityctl <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-30s %s\n", cmd.path, cmd.usage)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/tccav/identity-service/pkg/config"
+	"github.com/tccav/identity-service/pkg/gateways/redis"
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
+)
+
+func newRedisClient(ctx context.Context) (goredis.UniversalClient, error) {
+	memoryDBConfigs, err := config.LoadMemoryDBConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := redis.NewUniversalClient(redis.Connection{
+		Mode:             memoryDBConfigs.Mode,
+		Addresses:        memoryDBConfigs.Addresses,
+		MasterName:       memoryDBConfigs.MasterName,
+		Username:         memoryDBConfigs.User,
+		Password:         memoryDBConfigs.Password,
+		SentinelUsername: memoryDBConfigs.SentinelUser,
+		SentinelPassword: memoryDBConfigs.SentinelPassword,
+		TLS: tlsconfig.Config{
+			Enabled:  memoryDBConfigs.TLSEnabled,
+			CAFile:   memoryDBConfigs.TLSCAFile,
+			CertFile: memoryDBConfigs.TLSCertFile,
+			KeyFile:  memoryDBConfigs.TLSKeyFile,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.Ping(ctx).Err()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+)
+
+// runEventsReplay republishes the stored students as events, for consumers rebuilding their state: a
+// student_registered event with the current data of each student, or the student_erased tombstone of the
+// erased ones. Every event carries the replay ID in the ce_replay header.
+func runEventsReplay(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("events replay", flag.ContinueOnError)
+	after := flags.String("after", "", "student id to replay from, exclusive, the last id logged by a previous run")
+	until := flags.String("until", "", "student id to replay until, inclusive")
+	createdAfter := flags.String("created-after", "", "only replays the students registered after the date, as 2006-01-02 or RFC 3339")
+	batchSize := flags.Int("batch-size", 500, "students read per batch")
+	rate := flags.Int("rate", 100, "events published per second at most")
+	replayID := flags.String("replay-id", "", "id sent in the ce_replay header, defaults to a new one, reuse it when resuming a replay")
+	if err := flags.Parse(args); err != nil || *batchSize <= 0 || *rate <= 0 {
+		return errUsage
+	}
+
+	var created time.Time
+	if *createdAfter != "" {
+		var err error
+		created, err = parseDate(*createdAfter)
+		if err != nil {
+			return errUsage
+		}
+	}
+
+	if *replayID == "" {
+		*replayID = uuid.NewString()
+	}
+	logger = logger.With(zap.String("replay_id", *replayID))
+
+	kOpts, err := kafkaOptions()
+	if err != nil {
+		return err
+	}
+
+	serializer, _, err := eventsSerializer()
+	if err != nil {
+		return err
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	repository, err := newStudentsRepository(pool)
+	if err != nil {
+		return err
+	}
+
+	kafkaClient, err := newKafkaClient(ctx, kOpts...)
+	if err != nil {
+		return err
+	}
+	defer kafkaClient.Close()
+
+	gateway := kafka.NewStudentsProducer(kafka.NewProducer(kafkaClient, serializer), postgres.NewStudentKeysRepository(pool)).
+		Replaying(*replayID)
+
+	throttle := time.NewTicker(time.Second / time.Duration(*rate))
+	defer throttle.Stop()
+
+	var replayed int
+	lastID := *after
+	for {
+		students, err := repository.StreamStudents(ctx, postgres.StudentsRange{
+			After:        lastID,
+			Until:        *until,
+			CreatedAfter: created,
+			Limit:        *batchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(students) == 0 {
+			break
+		}
+
+		for _, student := range students {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle.C:
+			}
+
+			if student.ErasedAt.IsZero() {
+				err = gateway.ProduceStudentRegistered(ctx, student, student.CourseID)
+			} else {
+				err = gateway.ProduceStudentErased(ctx, entities.StudentErasure{StudentID: student.ID, ErasedAt: student.ErasedAt})
+			}
+			if err != nil {
+				logger.Error("failed to replay student", zap.String("student_id", student.ID), zap.String("last_id", lastID))
+				return err
+			}
+
+			lastID = student.ID
+			replayed++
+		}
+		logger.Info("students batch replayed", zap.String("last_id", lastID), zap.Int("replayed", replayed))
+	}
+
+	logger.Info("events replay finished", zap.Int("replayed", replayed))
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
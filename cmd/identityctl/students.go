@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+)
+
+func runStudentsDuplicates(ctx context.Context, logger *zap.Logger, args []string) error {
+	flags := flag.NewFlagSet("students duplicates", flag.ContinueOnError)
+	format := flags.String("format", "text", "output format, text or json")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	pool, err := newDBPool(ctx)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	repository, err := newStudentsRepository(pool)
+	if err != nil {
+		return err
+	}
+
+	groups, err := repository.FindDuplicates(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Info("duplicates report built", zap.Int("groups", len(groups)))
+
+	switch *format {
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(groups)
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FIELD\tVALUE\tSTUDENT IDS")
+		for _, group := range groups {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", group.Field, group.Value, strings.Join(group.StudentIDs, ","))
+		}
+		return w.Flush()
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/config"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/redis"
+)
+
+// newTokensRepository connects to the configured tokens store, the returned func releases its connection. The
+// memory backend lives in the app process, so it cannot be reached from here.
+func newTokensRepository(ctx context.Context, pool *pgxpool.Pool) (identities.TokensRepository, func(), error) {
+	tokensConfigs, err := config.LoadTokensConfigs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch tokensConfigs.Backend {
+	case config.TokensBackendRedis:
+		client, err := newRedisClient(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return redis.NewTokensRepository(client), func() { _ = client.Close() }, nil
+	case config.TokensBackendPostgres:
+		return postgres.NewTokensRepository(pool), func() {}, nil
+	case config.TokensBackendMemory:
+		return nil, nil, fmt.Errorf("the %s tokens backend is only reachable from the app", tokensConfigs.Backend)
+	default:
+		return nil, nil, fmt.Errorf("unknown tokens backend %q", tokensConfigs.Backend)
+	}
+}
+
+// withRevocations makes the revoked sessions reach the app replicas when they verify tokens in the stateless
+// mode or cache them, the returned func releases its connection.
+func withRevocations(ctx context.Context, sessions identities.SessionsRepository) (identities.SessionsRepository, func(), error) {
+	tokensConfigs, err := config.LoadTokensConfigs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch tokensConfigs.VerificationMode {
+	case config.TokenVerificationStored, config.TokenVerificationStateless:
+	default:
+		return nil, nil, fmt.Errorf("unknown token verification mode %q", tokensConfigs.VerificationMode)
+	}
+	if !tokensConfigs.PublishesRevocations() {
+		return sessions, func() {}, nil
+	}
+
+	client, err := newRedisClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	revocations := redis.NewTokenRevocationList(client)
+	return idusecases.RevokingSessions(sessions, revocations), func() { _ = client.Close() }, nil
+}
@@ -0,0 +1,12 @@
+-- migrate:up
+
+create table if not exists courses
+(
+    id         uuid      not null primary key,
+    name       varchar   not null,
+    status     varchar   not null,
+    updated_at timestamp not null default now()
+);
+
+-- migrate:down
+drop table if exists courses
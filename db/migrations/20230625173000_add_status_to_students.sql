@@ -0,0 +1,8 @@
+-- migrate:up
+
+alter table students
+    add column if not exists status varchar not null default 'active';
+
+-- migrate:down
+alter table students
+    drop column if exists status
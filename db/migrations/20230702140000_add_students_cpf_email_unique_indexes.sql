@@ -0,0 +1,10 @@
+-- migrate:up
+
+-- Run `identityctl students duplicates` and clean up the listed students before applying it,
+-- otherwise the indexes creation fails.
+create unique index if not exists students_cpf_key on students (regexp_replace(cpf, '\D', '', 'g'));
+create unique index if not exists students_email_key on students (lower(trim(email)));
+
+-- migrate:down
+drop index if exists students_email_key;
+drop index if exists students_cpf_key;
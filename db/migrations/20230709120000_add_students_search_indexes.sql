@@ -0,0 +1,36 @@
+-- migrate:up
+
+create extension if not exists unaccent;
+create extension if not exists pg_trgm;
+
+-- unaccent is only stable since its dictionary may change, indexes need an immutable function.
+create or replace function immutable_unaccent(text) returns text
+    language sql immutable parallel safe strict
+as
+$$
+select public.unaccent('public.unaccent'::regdictionary, $1)
+$$;
+
+-- students registered before this migration get its execution time as registration date.
+alter table students
+    add column if not exists course_id  uuid,
+    add column if not exists created_at timestamptz not null default now();
+
+create index if not exists students_name_fts_idx on students using gin (to_tsvector('simple', immutable_unaccent(name)));
+create index if not exists students_name_trgm_idx on students using gin (immutable_unaccent(lower(name)) gin_trgm_ops);
+create index if not exists students_created_at_id_idx on students (created_at desc, id desc);
+create index if not exists students_course_id_idx on students (course_id);
+create index if not exists students_status_idx on students (status);
+
+-- migrate:down
+drop index if exists students_status_idx;
+drop index if exists students_course_id_idx;
+drop index if exists students_created_at_id_idx;
+drop index if exists students_name_trgm_idx;
+drop index if exists students_name_fts_idx;
+
+alter table students
+    drop column if exists created_at,
+    drop column if exists course_id;
+
+drop function if exists immutable_unaccent(text);
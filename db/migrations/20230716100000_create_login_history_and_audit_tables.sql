@@ -0,0 +1,27 @@
+-- migrate:up
+
+create table if not exists student_logins
+(
+    id             bigserial   not null primary key,
+    student_id     varchar     not null,
+    succeeded      boolean     not null,
+    failure_reason varchar     not null default '',
+    attempted_at   timestamptz not null
+);
+
+create index if not exists student_logins_student_id_idx on student_logins (student_id, attempted_at desc);
+
+create table if not exists audit_log
+(
+    id         bigserial   not null primary key,
+    action     varchar     not null,
+    student_id varchar     not null,
+    actor      varchar     not null,
+    created_at timestamptz not null
+);
+
+create index if not exists audit_log_student_id_idx on audit_log (student_id, created_at);
+
+-- migrate:down
+drop table if exists audit_log;
+drop table if exists student_logins;
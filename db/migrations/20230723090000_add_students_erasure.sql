@@ -0,0 +1,31 @@
+-- migrate:up
+
+alter table students
+    add column if not exists erased_at timestamptz;
+
+-- erased students have their CPF and email blanked, so they must not take part in the uniqueness checks.
+drop index if exists students_cpf_key;
+drop index if exists students_email_key;
+create unique index if not exists students_cpf_key on students (regexp_replace(cpf, '\D', '', 'g')) where erased_at is null;
+create unique index if not exists students_email_key on students (lower(trim(email))) where erased_at is null;
+
+-- student_keys holds the key encrypting each student personal data in the published events, deleting it
+-- makes those events unreadable.
+create table if not exists student_keys
+(
+    student_id varchar     not null primary key,
+    key_id     uuid        not null,
+    key        bytea       not null,
+    created_at timestamptz not null default now()
+);
+
+-- migrate:down
+drop table if exists student_keys;
+
+drop index if exists students_email_key;
+drop index if exists students_cpf_key;
+create unique index if not exists students_cpf_key on students (regexp_replace(cpf, '\D', '', 'g'));
+create unique index if not exists students_email_key on students (lower(trim(email)));
+
+alter table students
+    drop column if exists erased_at;
@@ -0,0 +1,47 @@
+-- migrate:up
+
+-- CPF, email and birth date are stored encrypted with a data key of each student, wrapped by the master key.
+-- The CPF and email blind indexes keep them unique and searchable. Rows stored before are encrypted by
+-- `identityctl students encrypt`, the plain columns can be dropped once it is done.
+alter table students
+    add column if not exists data_key             text,
+    add column if not exists cpf_encrypted        text,
+    add column if not exists email_encrypted      text,
+    add column if not exists birth_date_encrypted text,
+    add column if not exists cpf_index            bytea,
+    add column if not exists email_index          bytea,
+    alter column cpf drop not null,
+    alter column email drop not null,
+    alter column birth_date drop not null;
+
+-- the plain unique indexes are kept for the rows not encrypted yet, encrypted rows have no plain values
+drop index if exists students_cpf_key;
+drop index if exists students_email_key;
+create unique index if not exists students_plain_cpf_key on students (regexp_replace(cpf, '\D', '', 'g')) where erased_at is null;
+create unique index if not exists students_plain_email_key on students (lower(trim(email))) where erased_at is null;
+create unique index if not exists students_cpf_key on students (cpf_index) where erased_at is null;
+create unique index if not exists students_email_key on students (email_index) where erased_at is null;
+
+-- lets the backfill find the rows left to encrypt without scanning the whole table
+create index if not exists students_not_encrypted_idx on students (id) where data_key is null and erased_at is null;
+
+-- migrate:down
+-- only possible before any row is encrypted, the plain columns can not be restored without the master key
+drop index if exists students_not_encrypted_idx;
+drop index if exists students_email_key;
+drop index if exists students_cpf_key;
+drop index if exists students_plain_email_key;
+drop index if exists students_plain_cpf_key;
+create unique index if not exists students_cpf_key on students (regexp_replace(cpf, '\D', '', 'g')) where erased_at is null;
+create unique index if not exists students_email_key on students (lower(trim(email))) where erased_at is null;
+
+alter table students
+    alter column birth_date set not null,
+    alter column email set not null,
+    alter column cpf set not null,
+    drop column if exists email_index,
+    drop column if exists cpf_index,
+    drop column if exists birth_date_encrypted,
+    drop column if exists email_encrypted,
+    drop column if exists cpf_encrypted,
+    drop column if exists data_key;
@@ -0,0 +1,22 @@
+-- migrate:up
+
+-- dead_letters keeps the events that could not be published after every retry, as the records they were going
+-- to be, until an admin retries or discards them.
+create table if not exists dead_letters
+(
+    id         uuid        not null primary key,
+    topic      varchar     not null,
+    key        varchar     not null,
+    event_id   varchar     not null,
+    event_type varchar     not null,
+    headers    jsonb       not null,
+    value      bytea       not null,
+    error      varchar     not null,
+    attempts   int         not null,
+    failed_at  timestamptz not null
+);
+
+create index if not exists dead_letters_failed_at_idx on dead_letters (failed_at, id);
+
+-- migrate:down
+drop table if exists dead_letters;
@@ -0,0 +1,17 @@
+-- migrate:up
+
+-- tokens keeps the emitted tokens when they are stored in postgres rather than in the memory db. Expired rows
+-- are ignored by every query and deleted by the periodic cleanup.
+create table if not exists tokens
+(
+    id         varchar     not null primary key,
+    student_id varchar     not null,
+    hash       varchar     not null,
+    expires_at timestamptz not null
+);
+
+create index if not exists tokens_student_id_idx on tokens (student_id);
+create index if not exists tokens_expires_at_idx on tokens (expires_at);
+
+-- migrate:down
+drop table if exists tokens;
@@ -0,0 +1,16 @@
+-- migrate:up
+
+-- tokens keep the keyed digest of the signed token rather than the token itself, rows stored before keep the
+-- signed token in the digest column until they expire.
+alter table tokens rename column hash to digest;
+alter table tokens
+    add column if not exists issued_at  timestamptz,
+    add column if not exists user_agent varchar not null default '',
+    add column if not exists ip         varchar not null default '';
+
+-- migrate:down
+alter table tokens
+    drop column if exists issued_at,
+    drop column if exists user_agent,
+    drop column if exists ip;
+alter table tokens rename column digest to hash;
@@ -0,0 +1,23 @@
+-- migrate:up
+
+-- dead letters keep the time of their event, the ones stored before take their failure as an approximation.
+alter table dead_letters add column if not exists event_time timestamptz;
+update dead_letters set event_time = failed_at where event_time is null;
+alter table dead_letters alter column event_time set not null;
+
+create index if not exists dead_letters_key_idx on dead_letters (topic, key, failed_at, id);
+
+-- published_events keeps the time of the last event published for each key, a dead letter older than it can no
+-- longer be published without reordering the events of the key.
+create table if not exists published_events
+(
+    topic      varchar     not null,
+    key        varchar     not null,
+    event_time timestamptz not null,
+    primary key (topic, key)
+);
+
+-- migrate:down
+drop table if exists published_events;
+drop index if exists dead_letters_key_idx;
+alter table dead_letters drop column if exists event_time;
@@ -1,20 +1,30 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"os"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+
+	"github.com/tccav/identity-service/pkg/encryption"
 )
 
 type Configs struct {
-	Telemetry Telemetry
-	Auth      auth
-	API       api
-	DB        db
-	MemoryDB  memoryDB
-	Kafka     kafka
-	Swagger   swagger
+	Telemetry   Telemetry
+	Auth        auth
+	Admin       admin
+	API         api
+	Idempotency idempotency
+	Import      Import
+	Encryption  Encryption
+	DB          db
+	MemoryDB    memoryDB
+	Tokens      tokens
+	Kafka       kafka
+	Swagger     swagger
 }
 
 type Telemetry struct {
@@ -23,23 +33,93 @@ type Telemetry struct {
 	Environment   string `envconfig:"ENVIRONMENT" default:"dev"`
 }
 
+// api TrustedProxies are the gateway addresses whose X-Forwarded-For header is trusted.
 type api struct {
-	Port         int           `envconfig:"API_PORT" default:"8000"`
-	ReadTimeout  time.Duration `envconfig:"API_READ_TIMEOUT" default:"15s"`
-	WriteTimeout time.Duration `envconfig:"API_WRITE_TIMEOUT" default:"15s"`
-	IdleTimeout  time.Duration `envconfig:"API_IDLE_TIMEOUT" default:"1m"`
+	Port           int           `envconfig:"API_PORT" default:"8000"`
+	ReadTimeout    time.Duration `envconfig:"API_READ_TIMEOUT" default:"15s"`
+	WriteTimeout   time.Duration `envconfig:"API_WRITE_TIMEOUT" default:"15s"`
+	IdleTimeout    time.Duration `envconfig:"API_IDLE_TIMEOUT" default:"1m"`
+	TrustedProxies []string      `envconfig:"API_TRUSTED_PROXIES"`
+}
+
+type idempotency struct {
+	TTL     time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+	LockTTL time.Duration `envconfig:"IDEMPOTENCY_LOCK_TTL" default:"30s"`
+	Wait    time.Duration `envconfig:"IDEMPOTENCY_WAIT" default:"5s"`
+}
+
+func (i idempotency) IdempotencyTTL() time.Duration {
+	return i.TTL
+}
+
+func (i idempotency) IdempotencyLockTTL() time.Duration {
+	return i.LockTTL
+}
+
+func (i idempotency) IdempotencyWait() time.Duration {
+	return i.Wait
+}
+
+type Import struct {
+	BatchSize int           `envconfig:"IMPORT_BATCH_SIZE" default:"500"`
+	Timeout   time.Duration `envconfig:"IMPORT_TIMEOUT" default:"10m"`
+}
+
+func (i Import) ImportBatchSize() int {
+	return i.BatchSize
 }
 
+func (i Import) ImportTimeout() time.Duration {
+	return i.Timeout
+}
+
+// Encryption has the base64 master key of the students personal data, or the file it is read from.
+type Encryption struct {
+	MasterKey     string `envconfig:"ENCRYPTION_MASTER_KEY"`
+	MasterKeyFile string `envconfig:"ENCRYPTION_MASTER_KEY_FILE"`
+}
+
+func (e Encryption) Keyring() (encryption.Keyring, error) {
+	encoded := e.MasterKey
+	if encoded == "" && e.MasterKeyFile != "" {
+		content, err := os.ReadFile(e.MasterKeyFile)
+		if err != nil {
+			return encryption.Keyring{}, err
+		}
+		encoded = string(content)
+	}
+
+	if encoded == "" {
+		return encryption.Keyring{}, errors.New("either ENCRYPTION_MASTER_KEY or ENCRYPTION_MASTER_KEY_FILE must be set")
+	}
+
+	key, err := encryption.DecodeKey(encoded)
+	if err != nil {
+		return encryption.Keyring{}, err
+	}
+
+	return encryption.NewKeyring(key)
+}
+
+// auth DigestKey keys the token digests kept in the tokens store, it must differ from Secret.
 type auth struct {
-	Secret   string        `envconfig:"TOKEN_SECRET" required:"true"`
-	Issuer   string        `envconfig:"TOKEN_ISSUER" default:"uerj"`
-	Duration time.Duration `envconfig:"TOKEN_DURATION" default:"3h"`
+	Secret        string        `envconfig:"TOKEN_SECRET" required:"true"`
+	DigestKey     string        `envconfig:"TOKEN_DIGEST_KEY" required:"true"`
+	Issuer        string        `envconfig:"TOKEN_ISSUER" default:"uerj"`
+	Duration      time.Duration `envconfig:"TOKEN_DURATION" default:"3h"`
+	Claims        []string      `envconfig:"TOKEN_CLAIMS"`
+	ClaimsMaxSize int           `envconfig:"TOKEN_CLAIMS_MAX_SIZE" default:"1024"`
+	Audiences     []string      `envconfig:"TOKEN_AUDIENCES"`
 }
 
 func (a auth) TokenSecret() string {
 	return a.Secret
 }
 
+func (a auth) TokenDigestKey() string {
+	return a.DigestKey
+}
+
 func (a auth) TokenIssuer() string {
 	return a.Issuer
 }
@@ -48,6 +128,23 @@ func (a auth) TokenDuration() time.Duration {
 	return a.Duration
 }
 
+func (a auth) TokenAudiences() []string {
+	return a.Audiences
+}
+
+func (a auth) TokenClaims() []string {
+	return a.Claims
+}
+
+func (a auth) TokenClaimsMaxSize() int {
+	return a.ClaimsMaxSize
+}
+
+type admin struct {
+	// APIKeys maps each admin API key to the scopes it grants, separated by "|".
+	APIKeys map[string]string `envconfig:"ADMIN_API_KEYS"`
+}
+
 type db struct {
 	Host     string `envconfig:"DB_HOST" required:"true"`
 	Port     string `envconfig:"DB_PORT" required:"true"`
@@ -65,26 +162,111 @@ func (d db) URL() string {
 	return u
 }
 
+// memoryDB Addresses are the server, sentinels or cluster seed nodes of the Mode.
 type memoryDB struct {
-	Host     string `envconfig:"MEMORY_DB_HOST" required:"true"`
-	Port     string `envconfig:"MEMORY_DB_PORT" required:"true"`
-	User     string `envconfig:"MEMORY_DB_USER"`
-	Password string `envconfig:"MEMORY_DB_PASSWORD"`
+	Mode             string   `envconfig:"MEMORY_DB_MODE" default:"standalone"`
+	Addresses        []string `envconfig:"MEMORY_DB_ADDRESSES"`
+	Host             string   `envconfig:"MEMORY_DB_HOST"`
+	Port             string   `envconfig:"MEMORY_DB_PORT"`
+	MasterName       string   `envconfig:"MEMORY_DB_MASTER_NAME"`
+	User             string   `envconfig:"MEMORY_DB_USER"`
+	Password         string   `envconfig:"MEMORY_DB_PASSWORD"`
+	SentinelUser     string   `envconfig:"MEMORY_DB_SENTINEL_USER"`
+	SentinelPassword string   `envconfig:"MEMORY_DB_SENTINEL_PASSWORD"`
+	TLSEnabled       bool     `envconfig:"MEMORY_DB_TLS_ENABLED" default:"false"`
+	TLSCAFile        string   `envconfig:"MEMORY_DB_TLS_CA_FILE"`
+	TLSCertFile      string   `envconfig:"MEMORY_DB_TLS_CERT_FILE"`
+	TLSKeyFile       string   `envconfig:"MEMORY_DB_TLS_KEY_FILE"`
+}
+
+// resolveAddresses falls back to the MEMORY_DB_HOST and MEMORY_DB_PORT of a standalone server, read before
+// MEMORY_DB_ADDRESSES was added.
+func (m *memoryDB) resolveAddresses() error {
+	addresses, err := addressesOrHostPort(m.Addresses, m.Host, m.Port, "MEMORY_DB_ADDRESSES", "MEMORY_DB_HOST", "MEMORY_DB_PORT")
+	if err != nil {
+		return err
+	}
+	m.Addresses = addresses
+	return nil
+}
+
+// Tokens store backends, as informed in the configs.
+const (
+	TokensBackendRedis    = "redis"
+	TokensBackendPostgres = "postgres"
+	TokensBackendMemory   = "memory"
+)
+
+// Token verification modes, as informed in the configs.
+const (
+	TokenVerificationStored    = "stored"
+	TokenVerificationStateless = "stateless"
+)
+
+// tokens Backend is where the emitted tokens are stored, VerificationMode how they are checked.
+type tokens struct {
+	Backend                     string        `envconfig:"TOKENS_BACKEND" default:"redis"`
+	CleanupInterval             time.Duration `envconfig:"TOKENS_CLEANUP_INTERVAL" default:"1m"`
+	VerificationMode            string        `envconfig:"TOKENS_VERIFICATION_MODE" default:"stored"`
+	RevocationsEvictionInterval time.Duration `envconfig:"TOKENS_REVOCATIONS_EVICTION_INTERVAL" default:"1m"`
+	VerificationCacheSize       int           `envconfig:"TOKENS_VERIFICATION_CACHE_SIZE" default:"0"`
+	VerificationCacheMaxTTL     time.Duration `envconfig:"TOKENS_VERIFICATION_CACHE_MAX_TTL" default:"30s"`
 }
 
-func (d memoryDB) URL() string {
-	return fmt.Sprintf("%s:%s", d.Host, d.Port)
+// PublishesRevocations tells whether the revoked tokens must reach the other replicas, which they only need when
+// verifying tokens in the stateless mode or caching them.
+func (t tokens) PublishesRevocations() bool {
+	return t.VerificationMode == TokenVerificationStateless || t.VerificationCacheSize > 0
 }
 
+// kafka Serializer is the format events are published in, every one but json requires the schema registry.
 type kafka struct {
-	Host     string `envconfig:"KAFKA_HOST" required:"true"`
-	Port     string `envconfig:"KAFKA_PORT" required:"true"`
-	User     string `envconfig:"KAFKA_USER"`
-	Password string `envconfig:"KAFKA_PASSWORD"`
+	Brokers                []string      `envconfig:"KAFKA_BROKERS"`
+	Host                   string        `envconfig:"KAFKA_HOST"`
+	Port                   string        `envconfig:"KAFKA_PORT"`
+	User                   string        `envconfig:"KAFKA_USER"`
+	Password               string        `envconfig:"KAFKA_PASSWORD"`
+	SASLMechanism          string        `envconfig:"KAFKA_SASL_MECHANISM"`
+	OAuthToken             string        `envconfig:"KAFKA_OAUTH_TOKEN"`
+	OAuthTokenFile         string        `envconfig:"KAFKA_OAUTH_TOKEN_FILE"`
+	TLSEnabled             bool          `envconfig:"KAFKA_TLS_ENABLED" default:"false"`
+	TLSCAFile              string        `envconfig:"KAFKA_TLS_CA_FILE"`
+	TLSCertFile            string        `envconfig:"KAFKA_TLS_CERT_FILE"`
+	TLSKeyFile             string        `envconfig:"KAFKA_TLS_KEY_FILE"`
+	ConsumerGroup          string        `envconfig:"KAFKA_CONSUMER_GROUP" default:"identity-service"`
+	CoursesTopic           string        `envconfig:"KAFKA_COURSES_TOPIC" default:"courses.cdc.courses.0"`
+	ProducerAcks           string        `envconfig:"KAFKA_PRODUCER_ACKS" default:"all"`
+	IdempotentWrites       bool          `envconfig:"KAFKA_IDEMPOTENT_WRITES" default:"true"`
+	Serializer             string        `envconfig:"KAFKA_SERIALIZER" default:"json"`
+	SchemaRegistryURL      string        `envconfig:"KAFKA_SCHEMA_REGISTRY_URL"`
+	SchemaRegistryUser     string        `envconfig:"KAFKA_SCHEMA_REGISTRY_USER"`
+	SchemaRegistryPassword string        `envconfig:"KAFKA_SCHEMA_REGISTRY_PASSWORD"`
+	DeliveryTimeout        time.Duration `envconfig:"KAFKA_DELIVERY_TIMEOUT" default:"10s"`
+	PublishAttempts        int           `envconfig:"KAFKA_PUBLISH_ATTEMPTS" default:"3"`
+	PublishBackoff         time.Duration `envconfig:"KAFKA_PUBLISH_BACKOFF" default:"100ms"`
+	PublishMaxBackoff      time.Duration `envconfig:"KAFKA_PUBLISH_MAX_BACKOFF" default:"2s"`
 }
 
-func (k kafka) URL() string {
-	return fmt.Sprintf("%s:%s", k.Host, k.Port)
+// resolveBrokers falls back to the KAFKA_HOST and KAFKA_PORT of a single broker, read before KAFKA_BROKERS was
+// added.
+func (k *kafka) resolveBrokers() error {
+	brokers, err := addressesOrHostPort(k.Brokers, k.Host, k.Port, "KAFKA_BROKERS", "KAFKA_HOST", "KAFKA_PORT")
+	if err != nil {
+		return err
+	}
+	k.Brokers = brokers
+	return nil
+}
+
+// addressesOrHostPort returns the addresses, or the single one of the host and port when they are unset.
+func addressesOrHostPort(addresses []string, host, port, addressesKey, hostKey, portKey string) ([]string, error) {
+	if len(addresses) > 0 {
+		return addresses, nil
+	}
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("required key %s missing value, or %s and %s", addressesKey, hostKey, portKey)
+	}
+	return []string{net.JoinHostPort(host, port)}, nil
 }
 
 type swagger struct {
@@ -97,5 +279,83 @@ func LoadConfigs() (Configs, error) {
 	if err != nil {
 		return Configs{}, err
 	}
+
+	// a digest keyed with the token secret would let anyone reading the tokens store forge tokens
+	if config.Auth.DigestKey == config.Auth.Secret {
+		return Configs{}, errors.New("TOKEN_DIGEST_KEY must differ from TOKEN_SECRET")
+	}
+
+	if err = config.MemoryDB.resolveAddresses(); err != nil {
+		return Configs{}, err
+	}
+	if err = config.Kafka.resolveBrokers(); err != nil {
+		return Configs{}, err
+	}
+	return config, nil
+}
+
+// LoadDBConfigs only loads the database configs, it is meant for commands that do not run the whole app.
+func LoadDBConfigs() (db, error) {
+	var config db
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return db{}, err
+	}
+	return config, nil
+}
+
+// LoadImportConfigs only loads the students import configs.
+func LoadImportConfigs() (Import, error) {
+	var config Import
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return Import{}, err
+	}
+	return config, nil
+}
+
+// LoadEncryptionConfigs only loads the encryption configs.
+func LoadEncryptionConfigs() (Encryption, error) {
+	var config Encryption
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return Encryption{}, err
+	}
+	return config, nil
+}
+
+// LoadKafkaConfigs only loads the Kafka configs.
+func LoadKafkaConfigs() (kafka, error) {
+	var config kafka
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return kafka{}, err
+	}
+	if err = config.resolveBrokers(); err != nil {
+		return kafka{}, err
+	}
+	return config, nil
+}
+
+// LoadTokensConfigs only loads the tokens store configs.
+func LoadTokensConfigs() (tokens, error) {
+	var config tokens
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return tokens{}, err
+	}
+	return config, nil
+}
+
+// LoadMemoryDBConfigs only loads the memory db configs.
+func LoadMemoryDBConfigs() (memoryDB, error) {
+	var config memoryDB
+	err := envconfig.Process("", &config)
+	if err != nil {
+		return memoryDB{}, err
+	}
+	if err = config.resolveAddresses(); err != nil {
+		return memoryDB{}, err
+	}
 	return config, nil
 }
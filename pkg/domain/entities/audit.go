@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+const (
+	AuditActionStudentDataExported     = "student_data_exported"
+	AuditActionStudentDataExportFailed = "student_data_export_failed"
+	AuditActionStudentErased           = "student_erased"
+	// dead letters are events about a student, retrying or discarding one decides what consumers know of it
+	AuditActionDeadLetterRetried   = "dead_letter_retried"
+	AuditActionDeadLetterDiscarded = "dead_letter_discarded"
+)
+
+// AuditEntry records an action performed over a student personal data. Actor identifies who asked for it,
+// for instance the admin API key fingerprint or the operator running a command.
+type AuditEntry struct {
+	Action    string
+	StudentID string
+	Actor     string
+	CreatedAt time.Time
+}
+
+func NewAuditEntry(action string, studentID string, actor string) AuditEntry {
+	return AuditEntry{
+		Action:    action,
+		StudentID: studentID,
+		Actor:     actor,
+		CreatedAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Outcomes of an authentication activity besides the login failure reasons.
+const (
+	AuthOutcomeSucceeded       = "succeeded"
+	AuthOutcomeStudentNotFound = "student_not_found"
+	AuthOutcomeStudentErased   = "student_erased"
+)
+
+// AuthenticationActivity is something done with the credentials of a student: a login, a failed one, a token
+// revoked or a password changed. IP and UserAgent are the ones of the client that did it, empty when an
+// operator did. Outcome is AuthOutcomeSucceeded, a login failure reason or why the token was revoked, and
+// TokenID is empty when every token of the student was revoked.
+type AuthenticationActivity struct {
+	StudentID  string
+	TokenID    string
+	Outcome    string
+	IP         string
+	UserAgent  string
+	OccurredAt time.Time
+}
+
+func NewAuthenticationActivity(studentID, outcome string, client AuthenticationClient) AuthenticationActivity {
+	return AuthenticationActivity{
+		StudentID:  studentID,
+		Outcome:    outcome,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// AuthenticationClient identifies where an authentication request came from.
+type AuthenticationClient struct {
+	IP        string
+	UserAgent string
+}
@@ -0,0 +1,28 @@
+package entities
+
+type CourseStatus string
+
+const (
+	CourseStatusOpen   CourseStatus = "open"
+	CourseStatusClosed CourseStatus = "closed"
+)
+
+// Course is the local copy of a course owned by the courses service. It is kept
+// in sync through the course events consumed from Kafka.
+type Course struct {
+	ID     string
+	Name   string
+	Status CourseStatus
+}
+
+func NewCourse(id string, name string) Course {
+	return Course{
+		ID:     id,
+		Name:   name,
+		Status: CourseStatusOpen,
+	}
+}
+
+func (c Course) IsClosed() bool {
+	return c.Status == CourseStatusClosed
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter is an event that could not be published after every retry, kept as the record it was going to be
+// so it can be published again as is. Key is the ID of the student the event is about, EventTime is when the
+// event happened and orders it among the events of the key.
+type DeadLetter struct {
+	ID        string
+	Topic     string
+	Key       string
+	EventID   string
+	EventType string
+	EventTime time.Time
+	Headers   []EventHeader
+	Value     []byte
+	// Error is the last publishing failure and Attempts counts every try, retries from the store included.
+	Error    string
+	Attempts int
+	FailedAt time.Time
+}
+
+type EventHeader struct {
+	Key   string
+	Value string
+}
+
+func NewDeadLetter(topic, key, eventID, eventType string, eventTime time.Time, headers []EventHeader, value []byte, attempts int, err error) DeadLetter {
+	return DeadLetter{
+		ID:        uuid.NewString(),
+		Topic:     topic,
+		Key:       key,
+		EventID:   eventID,
+		EventType: eventType,
+		EventTime: eventTime.UTC(),
+		Headers:   headers,
+		Value:     value,
+		Error:     err.Error(),
+		Attempts:  attempts,
+		FailedAt:  time.Now().UTC(),
+	}
+}
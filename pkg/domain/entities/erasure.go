@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tccav/identity-service/pkg/encryption"
+)
+
+// StudentErasure records a student personal data being erased, as the LGPD deletion right requires. The
+// student row is kept, with its personal data blanked, so its ID is never given to someone else.
+type StudentErasure struct {
+	StudentID string
+	ErasedAt  time.Time
+}
+
+func NewStudentErasure(studentID string) StudentErasure {
+	return StudentErasure{
+		StudentID: studentID,
+		ErasedAt:  time.Now().UTC(),
+	}
+}
+
+// StudentKey encrypts the student personal data wherever it can not be erased from, like the published
+// events. Destroying the key on erasure leaves that data unreadable.
+type StudentKey struct {
+	ID        string
+	StudentID string
+	Key       []byte
+}
+
+func NewStudentKey(studentID string) (StudentKey, error) {
+	key, err := encryption.NewKey()
+	if err != nil {
+		return StudentKey{}, err
+	}
+
+	return StudentKey{
+		ID:        uuid.NewString(),
+		StudentID: studentID,
+		Key:       key,
+	}, nil
+}
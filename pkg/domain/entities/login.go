@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Reasons a login attempt was refused for.
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureStudentSuspended   = "student_suspended"
+	LoginFailureStudentCancelled   = "student_cancelled"
+)
+
+// LoginAttempt is an entry of the student login history, FailureReason is empty for succeeded attempts.
+type LoginAttempt struct {
+	StudentID     string
+	Succeeded     bool
+	FailureReason string
+	AttemptedAt   time.Time
+}
+
+func NewLoginAttempt(studentID string, failureReason string) LoginAttempt {
+	return LoginAttempt{
+		StudentID:     studentID,
+		Succeeded:     failureReason == "",
+		FailureReason: failureReason,
+		AttemptedAt:   time.Now().UTC(),
+	}
+}
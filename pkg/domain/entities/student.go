@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/mail"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Nhanderu/brdoc"
@@ -16,6 +17,8 @@ var (
 	ErrInvalidCPF       = errors.New("invalid cpf")
 	ErrInvalidEmail     = errors.New("invalid email")
 	ErrInvalidBirthDate = errors.New("invalid birth date")
+	ErrInvalidName      = errors.New("invalid name")
+	ErrInvalidSecret    = errors.New("invalid secret")
 )
 
 type Student struct {
@@ -25,39 +28,81 @@ type Student struct {
 	CPF       string
 	Email     string
 	BirthDate time.Time
+	Status    StudentStatus
+	CourseID  string
+	// CreatedAt is the registration date, it is set once the student is stored.
+	CreatedAt time.Time
+	// ErasedAt is set once the student personal data is erased, see StudentErasure.
+	ErasedAt time.Time
 }
 
+// NewStudent validates every field before building the student, all invalid fields are
+// reported together in a ValidationError.
 func NewStudent(id string, secret string, name string, cpf string, email string, birthDate string) (Student, error) {
-	if _, err := strconv.Atoi(id); err != nil {
-		return Student{}, fmt.Errorf("%w: %s", ErrInvalidStudentID, err)
+	student, err := ValidateStudent(id, secret, name, cpf, email, birthDate)
+	if err != nil {
+		return Student{}, err
 	}
 
-	s, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	err = student.SetSecret(secret)
 	if err != nil {
-		return Student{}, fmt.Errorf("unable to encrypt student secret: %w", err)
+		return Student{}, err
+	}
+
+	return student, nil
+}
+
+// ValidateStudent builds the student without its secret, sparing the hashing cost when the student is
+// only being validated.
+func ValidateStudent(id string, secret string, name string, cpf string, email string, birthDate string) (Student, error) {
+	var validationErr ValidationError
+
+	if _, err := strconv.Atoi(id); err != nil {
+		validationErr.Add("id", fmt.Errorf("%w: %s", ErrInvalidStudentID, err))
 	}
-	secret = string(s)
 
-	if _, err = strconv.Atoi(cpf); err != nil || !brdoc.IsCPF(cpf) {
-		return Student{}, ErrInvalidCPF
+	if strings.TrimSpace(name) == "" {
+		validationErr.Add("name", fmt.Errorf("%w: name is empty", ErrInvalidName))
 	}
 
-	_, err = mail.ParseAddress(email)
-	if err != nil {
-		return Student{}, fmt.Errorf("%w: %s", ErrInvalidEmail, err)
+	if secret == "" {
+		validationErr.Add("secret", fmt.Errorf("%w: secret is empty", ErrInvalidSecret))
+	}
+
+	if _, err := strconv.Atoi(cpf); err != nil || !brdoc.IsCPF(cpf) {
+		validationErr.Add("cpf", ErrInvalidCPF)
+	}
+
+	if _, err := mail.ParseAddress(email); err != nil {
+		validationErr.Add("email", fmt.Errorf("%w: %s", ErrInvalidEmail, err))
 	}
 
 	b, err := time.Parse(time.DateOnly, birthDate)
 	if err != nil {
-		return Student{}, fmt.Errorf("%w: %s", ErrInvalidBirthDate, err)
+		validationErr.Add("birth_date", fmt.Errorf("%w: %s", ErrInvalidBirthDate, err))
+	}
+
+	if err = validationErr.Err(); err != nil {
+		return Student{}, err
 	}
 
 	return Student{
 		ID:        id,
-		Secret:    secret,
 		Name:      name,
 		CPF:       cpf,
 		Email:     email,
 		BirthDate: b,
+		Status:    StudentStatusPending,
 	}, nil
 }
+
+// SetSecret stores the hash of the informed secret.
+func (s *Student) SetSecret(secret string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("unable to encrypt student secret: %w", err)
+	}
+
+	s.Secret = string(hash)
+	return nil
+}
@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// StudentEvent is an event published about a student, as found in its topic.
+type StudentEvent struct {
+	ID          string
+	Type        string
+	Topic       string
+	PublishedAt time.Time
+	Payload     []byte
+}
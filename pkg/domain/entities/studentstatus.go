@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidStudentStatus    = errors.New("invalid student status")
+	ErrInvalidStatusTransition = errors.New("invalid student status transition")
+)
+
+type StudentStatus string
+
+const (
+	StudentStatusPending   StudentStatus = "pending"
+	StudentStatusActive    StudentStatus = "active"
+	StudentStatusSuspended StudentStatus = "suspended"
+	StudentStatusGraduated StudentStatus = "graduated"
+	StudentStatusCancelled StudentStatus = "cancelled"
+)
+
+// studentStatusTransitions maps each status to the statuses it may move to.
+// Graduated and cancelled are final states.
+var studentStatusTransitions = map[StudentStatus][]StudentStatus{
+	StudentStatusPending:   {StudentStatusActive, StudentStatusCancelled},
+	StudentStatusActive:    {StudentStatusSuspended, StudentStatusGraduated, StudentStatusCancelled},
+	StudentStatusSuspended: {StudentStatusActive, StudentStatusCancelled},
+	StudentStatusGraduated: {},
+	StudentStatusCancelled: {},
+}
+
+func ParseStudentStatus(status string) (StudentStatus, error) {
+	s := StudentStatus(status)
+	if _, ok := studentStatusTransitions[s]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidStudentStatus, status)
+	}
+	return s, nil
+}
+
+func (s StudentStatus) CanTransitionTo(next StudentStatus) bool {
+	for _, allowed := range studentStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAuthenticate tells whether students in the status may log in and keep their sessions.
+func (s StudentStatus) CanAuthenticate() bool {
+	return s != StudentStatusSuspended && s != StudentStatusCancelled
+}
+
+// StudentStatusTransition records a student moving from one status to another.
+type StudentStatusTransition struct {
+	StudentID string
+	From      StudentStatus
+	To        StudentStatus
+	Reason    string
+	ChangedAt time.Time
+}
+
+func NewStudentStatusTransition(studentID string, from StudentStatus, to StudentStatus, reason string) (StudentStatusTransition, error) {
+	if !from.CanTransitionTo(to) {
+		return StudentStatusTransition{}, fmt.Errorf("%w: from %s to %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	return StudentStatusTransition{
+		StudentID: studentID,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		ChangedAt: time.Now().UTC(),
+	}, nil
+}
@@ -6,11 +6,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// Token Hash is the signed token handed to the student, it is never stored. The stores keep its keyed Digest
+// instead, along with when and where it was issued. Audience and Claims are only carried by the signed token.
 type Token struct {
 	ID             string
 	UserID         string
+	Audience       string
+	IssuedAt       time.Time
 	ExpirationDate time.Time
+	Client         AuthenticationClient
 	Hash           string
+	Digest         string
+	Claims         TokenClaims
+}
+
+// TokenClaims describe the student as of the login, each claim is left out of the token unless it is set.
+type TokenClaims struct {
+	Name      string
+	CourseIDs []string
+	Status    StudentStatus
 }
 
 func NewToken(userID string, expirationDate time.Time) Token {
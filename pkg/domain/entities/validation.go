@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError ties a validation error to the field that caused it.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError aggregates every invalid field found, so callers can report all of them at once.
+// errors.Is matches any of the underlying field errors.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Add(field string, err error) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Err: err})
+}
+
+// Err returns nil when no field error was added.
+func (e ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Error())
+	}
+	return fmt.Sprintf("invalid fields: %s", strings.Join(msgs, "; "))
+}
+
+func (e ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		errs = append(errs, f)
+	}
+	return errs
+}
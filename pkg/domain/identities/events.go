@@ -6,6 +6,40 @@ import (
 	"github.com/tccav/identity-service/pkg/domain/entities"
 )
 
+//go:generate moq -out idmocks/mock_events.go -pkg idmocks . AuthenticationProducer TokenRevocationList
+
 type StudentsProducer interface {
 	ProduceStudentRegistered(ctx context.Context, student entities.Student, courseID string) error
+	ProduceStudentStatusChanged(ctx context.Context, transition entities.StudentStatusTransition) error
+	// ProduceStudentErased publishes the tombstone telling consumers to drop what they keep about the student.
+	ProduceStudentErased(ctx context.Context, erasure entities.StudentErasure) error
+}
+
+type StudentEventsReader interface {
+	// ReadStudentEvents returns every event still retained in the topics that is about the student.
+	ReadStudentEvents(ctx context.Context, studentID string) ([]entities.StudentEvent, error)
+}
+
+// AuthenticationProducer publishes the authentication activity of the students, for security analytics and
+// notifications. Publishing is asynchronous: an error only means the activity could not be queued, and a
+// Kafka outage never holds a login back.
+type AuthenticationProducer interface {
+	ProduceStudentLoggedIn(ctx context.Context, activity entities.AuthenticationActivity) error
+	ProduceLoginFailed(ctx context.Context, activity entities.AuthenticationActivity) error
+	ProduceTokenRevoked(ctx context.Context, activity entities.AuthenticationActivity) error
+}
+
+// TokenRevocationList spreads the revoked tokens to every replica, so tokens can be verified by their signature
+// alone. Tokens are only listed until they expire, when they would be refused anyway.
+type TokenRevocationList interface {
+	Revoke(ctx context.Context, tokens ...entities.Token) error
+	IsRevoked(id string) bool
+	// OnRevoke calls listener with the ids of the tokens revoked by any replica, this one included.
+	OnRevoke(listener func(ids ...string))
+}
+
+// DeadLetterPublisher publishes a dead letter again as the record it was stored as, without dead-lettering it
+// once more when it fails.
+type DeadLetterPublisher interface {
+	PublishDeadLetter(ctx context.Context, deadLetter entities.DeadLetter) error
 }
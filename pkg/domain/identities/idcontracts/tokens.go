@@ -0,0 +1,177 @@
+// Package idcontracts has the behaviour every implementation of a repository must share, each implementation
+// runs it from its own tests.
+package idcontracts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+// TokensRepository runs the tokens repository contract, newRepository is called once per test. Tokens are
+// registered to random students, so the repositories may share their storage.
+func TokensRepository(t *testing.T, newRepository func(t *testing.T) identities.TokensRepository) {
+	t.Helper()
+
+	newToken := func(studentID, digest string, ttl time.Duration) entities.Token {
+		token := entities.NewToken(studentID, time.Now().Add(ttl).UTC())
+		token.Digest = digest
+		return token
+	}
+
+	t.Run("should get the digest of a registered token", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		repository := newRepository(t)
+		token := newToken(uuid.NewString(), "digest", time.Hour)
+		require.NoError(t, repository.Register(ctx, token))
+
+		// test
+		got, err := repository.GetDigest(ctx, token.ID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, token.Digest, got)
+	})
+
+	t.Run("should not replace a registered token", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		repository := newRepository(t)
+		token := newToken(uuid.NewString(), "first", time.Hour)
+		require.NoError(t, repository.Register(ctx, token))
+
+		// test
+		replacement := token
+		replacement.Digest = "second"
+		require.NoError(t, repository.Register(ctx, replacement))
+
+		// assert
+		got, err := repository.GetDigest(ctx, token.ID)
+		require.NoError(t, err)
+		assert.Equal(t, token.Digest, got)
+	})
+
+	t.Run("should not find a token never registered", func(t *testing.T) {
+		t.Parallel()
+
+		// test
+		_, err := newRepository(t).GetDigest(context.Background(), uuid.NewString())
+
+		// assert
+		assert.ErrorIs(t, err, identities.ErrTokenNotEmitted)
+	})
+
+	t.Run("should forget a token once it expires", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		repository := newRepository(t)
+		studentID := uuid.NewString()
+		token := newToken(studentID, "digest", 500*time.Millisecond)
+		require.NoError(t, repository.Register(ctx, token))
+
+		// test
+		time.Sleep(time.Second)
+
+		// assert
+		_, err := repository.GetDigest(ctx, token.ID)
+		assert.ErrorIs(t, err, identities.ErrTokenNotEmitted)
+
+		sessions, err := repository.ListStudentSessions(ctx, studentID)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+
+		revoked, err := repository.RevokeStudentSessions(ctx, studentID)
+		require.NoError(t, err)
+		assert.Zero(t, revoked)
+	})
+
+	t.Run("should list the sessions of the student without their digests", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		repository := newRepository(t)
+		studentID := uuid.NewString()
+		first := newToken(studentID, "first", time.Hour)
+		second := newToken(studentID, "second", 2*time.Hour)
+		other := newToken(uuid.NewString(), "other", time.Hour)
+		for _, token := range []entities.Token{first, second, other} {
+			require.NoError(t, repository.Register(ctx, token))
+		}
+
+		// test
+		got, err := repository.ListStudentSessions(ctx, studentID)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		byID := map[string]entities.Token{got[0].ID: got[0], got[1].ID: got[1]}
+		for _, token := range []entities.Token{first, second} {
+			session, ok := byID[token.ID]
+			require.True(t, ok, "session %s not listed", token.ID)
+			assert.Equal(t, studentID, session.UserID)
+			assert.Empty(t, session.Hash)
+			assert.Empty(t, session.Digest)
+			assert.WithinDuration(t, token.ExpirationDate, session.ExpirationDate, time.Second)
+		}
+	})
+
+	t.Run("should revoke every session of the student", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		repository := newRepository(t)
+		studentID := uuid.NewString()
+		first := newToken(studentID, "first", time.Hour)
+		second := newToken(studentID, "second", time.Hour)
+		other := newToken(uuid.NewString(), "other", time.Hour)
+		for _, token := range []entities.Token{first, second, other} {
+			require.NoError(t, repository.Register(ctx, token))
+		}
+
+		// test
+		got, err := repository.RevokeStudentSessions(ctx, studentID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, got)
+
+		for _, token := range []entities.Token{first, second} {
+			_, err = repository.GetDigest(ctx, token.ID)
+			assert.ErrorIs(t, err, identities.ErrTokenNotEmitted)
+		}
+		sessions, err := repository.ListStudentSessions(ctx, studentID)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+
+		digest, err := repository.GetDigest(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, other.Digest, digest)
+	})
+
+	t.Run("should revoke nothing of a student without sessions", func(t *testing.T) {
+		t.Parallel()
+
+		// test
+		got, err := newRepository(t).RevokeStudentSessions(context.Background(), uuid.NewString())
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, got)
+	})
+}
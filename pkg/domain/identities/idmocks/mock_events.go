@@ -0,0 +1,343 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package idmocks
+
+import (
+	"context"
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"sync"
+)
+
+// Ensure, that AuthenticationProducerMock does implement identities.AuthenticationProducer.
+// If this is not the case, regenerate this file with moq.
+var _ identities.AuthenticationProducer = &AuthenticationProducerMock{}
+
+// AuthenticationProducerMock is a mock implementation of identities.AuthenticationProducer.
+//
+//	func TestSomethingThatUsesAuthenticationProducer(t *testing.T) {
+//
+//		// make and configure a mocked identities.AuthenticationProducer
+//		mockedAuthenticationProducer := &AuthenticationProducerMock{
+//			ProduceLoginFailedFunc: func(ctx context.Context, activity entities.AuthenticationActivity) error {
+//				panic("mock out the ProduceLoginFailed method")
+//			},
+//			ProduceStudentLoggedInFunc: func(ctx context.Context, activity entities.AuthenticationActivity) error {
+//				panic("mock out the ProduceStudentLoggedIn method")
+//			},
+//			ProduceTokenRevokedFunc: func(ctx context.Context, activity entities.AuthenticationActivity) error {
+//				panic("mock out the ProduceTokenRevoked method")
+//			},
+//		}
+//
+//		// use mockedAuthenticationProducer in code that requires identities.AuthenticationProducer
+//		// and then make assertions.
+//
+//	}
+type AuthenticationProducerMock struct {
+	// ProduceLoginFailedFunc mocks the ProduceLoginFailed method.
+	ProduceLoginFailedFunc func(ctx context.Context, activity entities.AuthenticationActivity) error
+
+	// ProduceStudentLoggedInFunc mocks the ProduceStudentLoggedIn method.
+	ProduceStudentLoggedInFunc func(ctx context.Context, activity entities.AuthenticationActivity) error
+
+	// ProduceTokenRevokedFunc mocks the ProduceTokenRevoked method.
+	ProduceTokenRevokedFunc func(ctx context.Context, activity entities.AuthenticationActivity) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ProduceLoginFailed holds details about calls to the ProduceLoginFailed method.
+		ProduceLoginFailed []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Activity is the activity argument value.
+			Activity entities.AuthenticationActivity
+		}
+		// ProduceStudentLoggedIn holds details about calls to the ProduceStudentLoggedIn method.
+		ProduceStudentLoggedIn []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Activity is the activity argument value.
+			Activity entities.AuthenticationActivity
+		}
+		// ProduceTokenRevoked holds details about calls to the ProduceTokenRevoked method.
+		ProduceTokenRevoked []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Activity is the activity argument value.
+			Activity entities.AuthenticationActivity
+		}
+	}
+	lockProduceLoginFailed     sync.RWMutex
+	lockProduceStudentLoggedIn sync.RWMutex
+	lockProduceTokenRevoked    sync.RWMutex
+}
+
+// ProduceLoginFailed calls ProduceLoginFailedFunc.
+func (mock *AuthenticationProducerMock) ProduceLoginFailed(ctx context.Context, activity entities.AuthenticationActivity) error {
+	if mock.ProduceLoginFailedFunc == nil {
+		panic("AuthenticationProducerMock.ProduceLoginFailedFunc: method is nil but AuthenticationProducer.ProduceLoginFailed was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Activity entities.AuthenticationActivity
+	}{
+		Ctx:      ctx,
+		Activity: activity,
+	}
+	mock.lockProduceLoginFailed.Lock()
+	mock.calls.ProduceLoginFailed = append(mock.calls.ProduceLoginFailed, callInfo)
+	mock.lockProduceLoginFailed.Unlock()
+	return mock.ProduceLoginFailedFunc(ctx, activity)
+}
+
+// ProduceLoginFailedCalls gets all the calls that were made to ProduceLoginFailed.
+// Check the length with:
+//
+//	len(mockedAuthenticationProducer.ProduceLoginFailedCalls())
+func (mock *AuthenticationProducerMock) ProduceLoginFailedCalls() []struct {
+	Ctx      context.Context
+	Activity entities.AuthenticationActivity
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Activity entities.AuthenticationActivity
+	}
+	mock.lockProduceLoginFailed.RLock()
+	calls = mock.calls.ProduceLoginFailed
+	mock.lockProduceLoginFailed.RUnlock()
+	return calls
+}
+
+// ProduceStudentLoggedIn calls ProduceStudentLoggedInFunc.
+func (mock *AuthenticationProducerMock) ProduceStudentLoggedIn(ctx context.Context, activity entities.AuthenticationActivity) error {
+	if mock.ProduceStudentLoggedInFunc == nil {
+		panic("AuthenticationProducerMock.ProduceStudentLoggedInFunc: method is nil but AuthenticationProducer.ProduceStudentLoggedIn was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Activity entities.AuthenticationActivity
+	}{
+		Ctx:      ctx,
+		Activity: activity,
+	}
+	mock.lockProduceStudentLoggedIn.Lock()
+	mock.calls.ProduceStudentLoggedIn = append(mock.calls.ProduceStudentLoggedIn, callInfo)
+	mock.lockProduceStudentLoggedIn.Unlock()
+	return mock.ProduceStudentLoggedInFunc(ctx, activity)
+}
+
+// ProduceStudentLoggedInCalls gets all the calls that were made to ProduceStudentLoggedIn.
+// Check the length with:
+//
+//	len(mockedAuthenticationProducer.ProduceStudentLoggedInCalls())
+func (mock *AuthenticationProducerMock) ProduceStudentLoggedInCalls() []struct {
+	Ctx      context.Context
+	Activity entities.AuthenticationActivity
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Activity entities.AuthenticationActivity
+	}
+	mock.lockProduceStudentLoggedIn.RLock()
+	calls = mock.calls.ProduceStudentLoggedIn
+	mock.lockProduceStudentLoggedIn.RUnlock()
+	return calls
+}
+
+// ProduceTokenRevoked calls ProduceTokenRevokedFunc.
+func (mock *AuthenticationProducerMock) ProduceTokenRevoked(ctx context.Context, activity entities.AuthenticationActivity) error {
+	if mock.ProduceTokenRevokedFunc == nil {
+		panic("AuthenticationProducerMock.ProduceTokenRevokedFunc: method is nil but AuthenticationProducer.ProduceTokenRevoked was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Activity entities.AuthenticationActivity
+	}{
+		Ctx:      ctx,
+		Activity: activity,
+	}
+	mock.lockProduceTokenRevoked.Lock()
+	mock.calls.ProduceTokenRevoked = append(mock.calls.ProduceTokenRevoked, callInfo)
+	mock.lockProduceTokenRevoked.Unlock()
+	return mock.ProduceTokenRevokedFunc(ctx, activity)
+}
+
+// ProduceTokenRevokedCalls gets all the calls that were made to ProduceTokenRevoked.
+// Check the length with:
+//
+//	len(mockedAuthenticationProducer.ProduceTokenRevokedCalls())
+func (mock *AuthenticationProducerMock) ProduceTokenRevokedCalls() []struct {
+	Ctx      context.Context
+	Activity entities.AuthenticationActivity
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Activity entities.AuthenticationActivity
+	}
+	mock.lockProduceTokenRevoked.RLock()
+	calls = mock.calls.ProduceTokenRevoked
+	mock.lockProduceTokenRevoked.RUnlock()
+	return calls
+}
+
+// Ensure, that TokenRevocationListMock does implement identities.TokenRevocationList.
+// If this is not the case, regenerate this file with moq.
+var _ identities.TokenRevocationList = &TokenRevocationListMock{}
+
+// TokenRevocationListMock is a mock implementation of identities.TokenRevocationList.
+//
+//	func TestSomethingThatUsesTokenRevocationList(t *testing.T) {
+//
+//		// make and configure a mocked identities.TokenRevocationList
+//		mockedTokenRevocationList := &TokenRevocationListMock{
+//			IsRevokedFunc: func(id string) bool {
+//				panic("mock out the IsRevoked method")
+//			},
+//			OnRevokeFunc: func(listener func(ids ...string))  {
+//				panic("mock out the OnRevoke method")
+//			},
+//			RevokeFunc: func(ctx context.Context, tokens ...entities.Token) error {
+//				panic("mock out the Revoke method")
+//			},
+//		}
+//
+//		// use mockedTokenRevocationList in code that requires identities.TokenRevocationList
+//		// and then make assertions.
+//
+//	}
+type TokenRevocationListMock struct {
+	// IsRevokedFunc mocks the IsRevoked method.
+	IsRevokedFunc func(id string) bool
+
+	// OnRevokeFunc mocks the OnRevoke method.
+	OnRevokeFunc func(listener func(ids ...string))
+
+	// RevokeFunc mocks the Revoke method.
+	RevokeFunc func(ctx context.Context, tokens ...entities.Token) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// IsRevoked holds details about calls to the IsRevoked method.
+		IsRevoked []struct {
+			// ID is the id argument value.
+			ID string
+		}
+		// OnRevoke holds details about calls to the OnRevoke method.
+		OnRevoke []struct {
+			// Listener is the listener argument value.
+			Listener func(ids ...string)
+		}
+		// Revoke holds details about calls to the Revoke method.
+		Revoke []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Tokens is the tokens argument value.
+			Tokens []entities.Token
+		}
+	}
+	lockIsRevoked sync.RWMutex
+	lockOnRevoke  sync.RWMutex
+	lockRevoke    sync.RWMutex
+}
+
+// IsRevoked calls IsRevokedFunc.
+func (mock *TokenRevocationListMock) IsRevoked(id string) bool {
+	if mock.IsRevokedFunc == nil {
+		panic("TokenRevocationListMock.IsRevokedFunc: method is nil but TokenRevocationList.IsRevoked was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: id,
+	}
+	mock.lockIsRevoked.Lock()
+	mock.calls.IsRevoked = append(mock.calls.IsRevoked, callInfo)
+	mock.lockIsRevoked.Unlock()
+	return mock.IsRevokedFunc(id)
+}
+
+// IsRevokedCalls gets all the calls that were made to IsRevoked.
+// Check the length with:
+//
+//	len(mockedTokenRevocationList.IsRevokedCalls())
+func (mock *TokenRevocationListMock) IsRevokedCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	mock.lockIsRevoked.RLock()
+	calls = mock.calls.IsRevoked
+	mock.lockIsRevoked.RUnlock()
+	return calls
+}
+
+// OnRevoke calls OnRevokeFunc.
+func (mock *TokenRevocationListMock) OnRevoke(listener func(ids ...string)) {
+	if mock.OnRevokeFunc == nil {
+		panic("TokenRevocationListMock.OnRevokeFunc: method is nil but TokenRevocationList.OnRevoke was just called")
+	}
+	callInfo := struct {
+		Listener func(ids ...string)
+	}{
+		Listener: listener,
+	}
+	mock.lockOnRevoke.Lock()
+	mock.calls.OnRevoke = append(mock.calls.OnRevoke, callInfo)
+	mock.lockOnRevoke.Unlock()
+	mock.OnRevokeFunc(listener)
+}
+
+// OnRevokeCalls gets all the calls that were made to OnRevoke.
+// Check the length with:
+//
+//	len(mockedTokenRevocationList.OnRevokeCalls())
+func (mock *TokenRevocationListMock) OnRevokeCalls() []struct {
+	Listener func(ids ...string)
+} {
+	var calls []struct {
+		Listener func(ids ...string)
+	}
+	mock.lockOnRevoke.RLock()
+	calls = mock.calls.OnRevoke
+	mock.lockOnRevoke.RUnlock()
+	return calls
+}
+
+// Revoke calls RevokeFunc.
+func (mock *TokenRevocationListMock) Revoke(ctx context.Context, tokens ...entities.Token) error {
+	if mock.RevokeFunc == nil {
+		panic("TokenRevocationListMock.RevokeFunc: method is nil but TokenRevocationList.Revoke was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Tokens []entities.Token
+	}{
+		Ctx:    ctx,
+		Tokens: tokens,
+	}
+	mock.lockRevoke.Lock()
+	mock.calls.Revoke = append(mock.calls.Revoke, callInfo)
+	mock.lockRevoke.Unlock()
+	return mock.RevokeFunc(ctx, tokens...)
+}
+
+// RevokeCalls gets all the calls that were made to Revoke.
+// Check the length with:
+//
+//	len(mockedTokenRevocationList.RevokeCalls())
+func (mock *TokenRevocationListMock) RevokeCalls() []struct {
+	Ctx    context.Context
+	Tokens []entities.Token
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Tokens []entities.Token
+	}
+	mock.lockRevoke.RLock()
+	calls = mock.calls.Revoke
+	mock.lockRevoke.RUnlock()
+	return calls
+}
@@ -95,7 +95,7 @@ var _ identities.AuthenticationUseCases = &AuthenticationUseCasesMock{}
 //			AuthenticateStudentFunc: func(ctx context.Context, input identities.AuthenticateStudentInput) (entities.Token, error) {
 //				panic("mock out the AuthenticateStudent method")
 //			},
-//			VerifyAuthFunc: func(ctx context.Context, hash string) error {
+//			VerifyAuthFunc: func(ctx context.Context, hash string, audience string) (entities.Token, error) {
 //				panic("mock out the VerifyAuth method")
 //			},
 //		}
@@ -109,7 +109,7 @@ type AuthenticationUseCasesMock struct {
 	AuthenticateStudentFunc func(ctx context.Context, input identities.AuthenticateStudentInput) (entities.Token, error)
 
 	// VerifyAuthFunc mocks the VerifyAuth method.
-	VerifyAuthFunc func(ctx context.Context, hash string) error
+	VerifyAuthFunc func(ctx context.Context, hash string, audience string) (entities.Token, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
@@ -126,6 +126,8 @@ type AuthenticationUseCasesMock struct {
 			Ctx context.Context
 			// Hash is the hash argument value.
 			Hash string
+			// Audience is the audience argument value.
+			Audience string
 		}
 	}
 	lockAuthenticateStudent sync.RWMutex
@@ -169,21 +171,23 @@ func (mock *AuthenticationUseCasesMock) AuthenticateStudentCalls() []struct {
 }
 
 // VerifyAuth calls VerifyAuthFunc.
-func (mock *AuthenticationUseCasesMock) VerifyAuth(ctx context.Context, hash string) error {
+func (mock *AuthenticationUseCasesMock) VerifyAuth(ctx context.Context, hash string, audience string) (entities.Token, error) {
 	if mock.VerifyAuthFunc == nil {
 		panic("AuthenticationUseCasesMock.VerifyAuthFunc: method is nil but AuthenticationUseCases.VerifyAuth was just called")
 	}
 	callInfo := struct {
-		Ctx  context.Context
-		Hash string
+		Ctx      context.Context
+		Hash     string
+		Audience string
 	}{
-		Ctx:  ctx,
-		Hash: hash,
+		Ctx:      ctx,
+		Hash:     hash,
+		Audience: audience,
 	}
 	mock.lockVerifyAuth.Lock()
 	mock.calls.VerifyAuth = append(mock.calls.VerifyAuth, callInfo)
 	mock.lockVerifyAuth.Unlock()
-	return mock.VerifyAuthFunc(ctx, hash)
+	return mock.VerifyAuthFunc(ctx, hash, audience)
 }
 
 // VerifyAuthCalls gets all the calls that were made to VerifyAuth.
@@ -191,15 +195,671 @@ func (mock *AuthenticationUseCasesMock) VerifyAuth(ctx context.Context, hash str
 //
 //	len(mockedAuthenticationUseCases.VerifyAuthCalls())
 func (mock *AuthenticationUseCasesMock) VerifyAuthCalls() []struct {
-	Ctx  context.Context
-	Hash string
+	Ctx      context.Context
+	Hash     string
+	Audience string
 } {
 	var calls []struct {
-		Ctx  context.Context
-		Hash string
+		Ctx      context.Context
+		Hash     string
+		Audience string
 	}
 	mock.lockVerifyAuth.RLock()
 	calls = mock.calls.VerifyAuth
 	mock.lockVerifyAuth.RUnlock()
 	return calls
 }
+
+// Ensure, that CourseCatalogUseCasesMock does implement identities.CourseCatalogUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.CourseCatalogUseCases = &CourseCatalogUseCasesMock{}
+
+// CourseCatalogUseCasesMock is a mock implementation of identities.CourseCatalogUseCases.
+//
+//	func TestSomethingThatUsesCourseCatalogUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.CourseCatalogUseCases
+//		mockedCourseCatalogUseCases := &CourseCatalogUseCasesMock{
+//			CloseCourseFunc: func(ctx context.Context, id string) error {
+//				panic("mock out the CloseCourse method")
+//			},
+//			SaveCourseFunc: func(ctx context.Context, input identities.SaveCourseInput) error {
+//				panic("mock out the SaveCourse method")
+//			},
+//		}
+//
+//		// use mockedCourseCatalogUseCases in code that requires identities.CourseCatalogUseCases
+//		// and then make assertions.
+//
+//	}
+type CourseCatalogUseCasesMock struct {
+	// CloseCourseFunc mocks the CloseCourse method.
+	CloseCourseFunc func(ctx context.Context, id string) error
+
+	// SaveCourseFunc mocks the SaveCourse method.
+	SaveCourseFunc func(ctx context.Context, input identities.SaveCourseInput) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CloseCourse holds details about calls to the CloseCourse method.
+		CloseCourse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
+		// SaveCourse holds details about calls to the SaveCourse method.
+		SaveCourse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.SaveCourseInput
+		}
+	}
+	lockCloseCourse sync.RWMutex
+	lockSaveCourse  sync.RWMutex
+}
+
+// CloseCourse calls CloseCourseFunc.
+func (mock *CourseCatalogUseCasesMock) CloseCourse(ctx context.Context, id string) error {
+	if mock.CloseCourseFunc == nil {
+		panic("CourseCatalogUseCasesMock.CloseCourseFunc: method is nil but CourseCatalogUseCases.CloseCourse was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockCloseCourse.Lock()
+	mock.calls.CloseCourse = append(mock.calls.CloseCourse, callInfo)
+	mock.lockCloseCourse.Unlock()
+	return mock.CloseCourseFunc(ctx, id)
+}
+
+// CloseCourseCalls gets all the calls that were made to CloseCourse.
+// Check the length with:
+//
+//	len(mockedCourseCatalogUseCases.CloseCourseCalls())
+func (mock *CourseCatalogUseCasesMock) CloseCourseCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockCloseCourse.RLock()
+	calls = mock.calls.CloseCourse
+	mock.lockCloseCourse.RUnlock()
+	return calls
+}
+
+// SaveCourse calls SaveCourseFunc.
+func (mock *CourseCatalogUseCasesMock) SaveCourse(ctx context.Context, input identities.SaveCourseInput) error {
+	if mock.SaveCourseFunc == nil {
+		panic("CourseCatalogUseCasesMock.SaveCourseFunc: method is nil but CourseCatalogUseCases.SaveCourse was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.SaveCourseInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockSaveCourse.Lock()
+	mock.calls.SaveCourse = append(mock.calls.SaveCourse, callInfo)
+	mock.lockSaveCourse.Unlock()
+	return mock.SaveCourseFunc(ctx, input)
+}
+
+// SaveCourseCalls gets all the calls that were made to SaveCourse.
+// Check the length with:
+//
+//	len(mockedCourseCatalogUseCases.SaveCourseCalls())
+func (mock *CourseCatalogUseCasesMock) SaveCourseCalls() []struct {
+	Ctx   context.Context
+	Input identities.SaveCourseInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.SaveCourseInput
+	}
+	mock.lockSaveCourse.RLock()
+	calls = mock.calls.SaveCourse
+	mock.lockSaveCourse.RUnlock()
+	return calls
+}
+
+// Ensure, that StudentStatusUseCasesMock does implement identities.StudentStatusUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.StudentStatusUseCases = &StudentStatusUseCasesMock{}
+
+// StudentStatusUseCasesMock is a mock implementation of identities.StudentStatusUseCases.
+//
+//	func TestSomethingThatUsesStudentStatusUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.StudentStatusUseCases
+//		mockedStudentStatusUseCases := &StudentStatusUseCasesMock{
+//			ChangeStudentStatusFunc: func(ctx context.Context, input identities.ChangeStudentStatusInput) (entities.StudentStatusTransition, error) {
+//				panic("mock out the ChangeStudentStatus method")
+//			},
+//		}
+//
+//		// use mockedStudentStatusUseCases in code that requires identities.StudentStatusUseCases
+//		// and then make assertions.
+//
+//	}
+type StudentStatusUseCasesMock struct {
+	// ChangeStudentStatusFunc mocks the ChangeStudentStatus method.
+	ChangeStudentStatusFunc func(ctx context.Context, input identities.ChangeStudentStatusInput) (entities.StudentStatusTransition, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ChangeStudentStatus holds details about calls to the ChangeStudentStatus method.
+		ChangeStudentStatus []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.ChangeStudentStatusInput
+		}
+	}
+	lockChangeStudentStatus sync.RWMutex
+}
+
+// ChangeStudentStatus calls ChangeStudentStatusFunc.
+func (mock *StudentStatusUseCasesMock) ChangeStudentStatus(ctx context.Context, input identities.ChangeStudentStatusInput) (entities.StudentStatusTransition, error) {
+	if mock.ChangeStudentStatusFunc == nil {
+		panic("StudentStatusUseCasesMock.ChangeStudentStatusFunc: method is nil but StudentStatusUseCases.ChangeStudentStatus was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.ChangeStudentStatusInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockChangeStudentStatus.Lock()
+	mock.calls.ChangeStudentStatus = append(mock.calls.ChangeStudentStatus, callInfo)
+	mock.lockChangeStudentStatus.Unlock()
+	return mock.ChangeStudentStatusFunc(ctx, input)
+}
+
+// ChangeStudentStatusCalls gets all the calls that were made to ChangeStudentStatus.
+// Check the length with:
+//
+//	len(mockedStudentStatusUseCases.ChangeStudentStatusCalls())
+func (mock *StudentStatusUseCasesMock) ChangeStudentStatusCalls() []struct {
+	Ctx   context.Context
+	Input identities.ChangeStudentStatusInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.ChangeStudentStatusInput
+	}
+	mock.lockChangeStudentStatus.RLock()
+	calls = mock.calls.ChangeStudentStatus
+	mock.lockChangeStudentStatus.RUnlock()
+	return calls
+}
+
+// Ensure, that ImportStudentsUseCasesMock does implement identities.ImportStudentsUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.ImportStudentsUseCases = &ImportStudentsUseCasesMock{}
+
+// ImportStudentsUseCasesMock is a mock implementation of identities.ImportStudentsUseCases.
+//
+//	func TestSomethingThatUsesImportStudentsUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.ImportStudentsUseCases
+//		mockedImportStudentsUseCases := &ImportStudentsUseCasesMock{
+//			ImportStudentsFunc: func(ctx context.Context, input identities.ImportStudentsInput) (identities.ImportReport, error) {
+//				panic("mock out the ImportStudents method")
+//			},
+//		}
+//
+//		// use mockedImportStudentsUseCases in code that requires identities.ImportStudentsUseCases
+//		// and then make assertions.
+//
+//	}
+type ImportStudentsUseCasesMock struct {
+	// ImportStudentsFunc mocks the ImportStudents method.
+	ImportStudentsFunc func(ctx context.Context, input identities.ImportStudentsInput) (identities.ImportReport, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ImportStudents holds details about calls to the ImportStudents method.
+		ImportStudents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.ImportStudentsInput
+		}
+	}
+	lockImportStudents sync.RWMutex
+}
+
+// ImportStudents calls ImportStudentsFunc.
+func (mock *ImportStudentsUseCasesMock) ImportStudents(ctx context.Context, input identities.ImportStudentsInput) (identities.ImportReport, error) {
+	if mock.ImportStudentsFunc == nil {
+		panic("ImportStudentsUseCasesMock.ImportStudentsFunc: method is nil but ImportStudentsUseCases.ImportStudents was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.ImportStudentsInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockImportStudents.Lock()
+	mock.calls.ImportStudents = append(mock.calls.ImportStudents, callInfo)
+	mock.lockImportStudents.Unlock()
+	return mock.ImportStudentsFunc(ctx, input)
+}
+
+// ImportStudentsCalls gets all the calls that were made to ImportStudents.
+// Check the length with:
+//
+//	len(mockedImportStudentsUseCases.ImportStudentsCalls())
+func (mock *ImportStudentsUseCasesMock) ImportStudentsCalls() []struct {
+	Ctx   context.Context
+	Input identities.ImportStudentsInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.ImportStudentsInput
+	}
+	mock.lockImportStudents.RLock()
+	calls = mock.calls.ImportStudents
+	mock.lockImportStudents.RUnlock()
+	return calls
+}
+
+// Ensure, that StudentsSearchUseCasesMock does implement identities.StudentsSearchUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.StudentsSearchUseCases = &StudentsSearchUseCasesMock{}
+
+// StudentsSearchUseCasesMock is a mock implementation of identities.StudentsSearchUseCases.
+//
+//	func TestSomethingThatUsesStudentsSearchUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.StudentsSearchUseCases
+//		mockedStudentsSearchUseCases := &StudentsSearchUseCasesMock{
+//			SearchStudentsFunc: func(ctx context.Context, input identities.SearchStudentsInput) (identities.StudentsPage, error) {
+//				panic("mock out the SearchStudents method")
+//			},
+//		}
+//
+//		// use mockedStudentsSearchUseCases in code that requires identities.StudentsSearchUseCases
+//		// and then make assertions.
+//
+//	}
+type StudentsSearchUseCasesMock struct {
+	// SearchStudentsFunc mocks the SearchStudents method.
+	SearchStudentsFunc func(ctx context.Context, input identities.SearchStudentsInput) (identities.StudentsPage, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// SearchStudents holds details about calls to the SearchStudents method.
+		SearchStudents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.SearchStudentsInput
+		}
+	}
+	lockSearchStudents sync.RWMutex
+}
+
+// SearchStudents calls SearchStudentsFunc.
+func (mock *StudentsSearchUseCasesMock) SearchStudents(ctx context.Context, input identities.SearchStudentsInput) (identities.StudentsPage, error) {
+	if mock.SearchStudentsFunc == nil {
+		panic("StudentsSearchUseCasesMock.SearchStudentsFunc: method is nil but StudentsSearchUseCases.SearchStudents was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.SearchStudentsInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockSearchStudents.Lock()
+	mock.calls.SearchStudents = append(mock.calls.SearchStudents, callInfo)
+	mock.lockSearchStudents.Unlock()
+	return mock.SearchStudentsFunc(ctx, input)
+}
+
+// SearchStudentsCalls gets all the calls that were made to SearchStudents.
+// Check the length with:
+//
+//	len(mockedStudentsSearchUseCases.SearchStudentsCalls())
+func (mock *StudentsSearchUseCasesMock) SearchStudentsCalls() []struct {
+	Ctx   context.Context
+	Input identities.SearchStudentsInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.SearchStudentsInput
+	}
+	mock.lockSearchStudents.RLock()
+	calls = mock.calls.SearchStudents
+	mock.lockSearchStudents.RUnlock()
+	return calls
+}
+
+// Ensure, that DataExportUseCasesMock does implement identities.DataExportUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.DataExportUseCases = &DataExportUseCasesMock{}
+
+// DataExportUseCasesMock is a mock implementation of identities.DataExportUseCases.
+//
+//	func TestSomethingThatUsesDataExportUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.DataExportUseCases
+//		mockedDataExportUseCases := &DataExportUseCasesMock{
+//			ExportStudentDataFunc: func(ctx context.Context, input identities.ExportStudentDataInput) (identities.StudentDataExport, error) {
+//				panic("mock out the ExportStudentData method")
+//			},
+//		}
+//
+//		// use mockedDataExportUseCases in code that requires identities.DataExportUseCases
+//		// and then make assertions.
+//
+//	}
+type DataExportUseCasesMock struct {
+	// ExportStudentDataFunc mocks the ExportStudentData method.
+	ExportStudentDataFunc func(ctx context.Context, input identities.ExportStudentDataInput) (identities.StudentDataExport, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// ExportStudentData holds details about calls to the ExportStudentData method.
+		ExportStudentData []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.ExportStudentDataInput
+		}
+	}
+	lockExportStudentData sync.RWMutex
+}
+
+// ExportStudentData calls ExportStudentDataFunc.
+func (mock *DataExportUseCasesMock) ExportStudentData(ctx context.Context, input identities.ExportStudentDataInput) (identities.StudentDataExport, error) {
+	if mock.ExportStudentDataFunc == nil {
+		panic("DataExportUseCasesMock.ExportStudentDataFunc: method is nil but DataExportUseCases.ExportStudentData was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.ExportStudentDataInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockExportStudentData.Lock()
+	mock.calls.ExportStudentData = append(mock.calls.ExportStudentData, callInfo)
+	mock.lockExportStudentData.Unlock()
+	return mock.ExportStudentDataFunc(ctx, input)
+}
+
+// ExportStudentDataCalls gets all the calls that were made to ExportStudentData.
+// Check the length with:
+//
+//	len(mockedDataExportUseCases.ExportStudentDataCalls())
+func (mock *DataExportUseCasesMock) ExportStudentDataCalls() []struct {
+	Ctx   context.Context
+	Input identities.ExportStudentDataInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.ExportStudentDataInput
+	}
+	mock.lockExportStudentData.RLock()
+	calls = mock.calls.ExportStudentData
+	mock.lockExportStudentData.RUnlock()
+	return calls
+}
+
+// Ensure, that StudentErasureUseCasesMock does implement identities.StudentErasureUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.StudentErasureUseCases = &StudentErasureUseCasesMock{}
+
+// StudentErasureUseCasesMock is a mock implementation of identities.StudentErasureUseCases.
+//
+//	func TestSomethingThatUsesStudentErasureUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.StudentErasureUseCases
+//		mockedStudentErasureUseCases := &StudentErasureUseCasesMock{
+//			EraseStudentFunc: func(ctx context.Context, input identities.EraseStudentInput) (identities.StudentErasureReport, error) {
+//				panic("mock out the EraseStudent method")
+//			},
+//		}
+//
+//		// use mockedStudentErasureUseCases in code that requires identities.StudentErasureUseCases
+//		// and then make assertions.
+//
+//	}
+type StudentErasureUseCasesMock struct {
+	// EraseStudentFunc mocks the EraseStudent method.
+	EraseStudentFunc func(ctx context.Context, input identities.EraseStudentInput) (identities.StudentErasureReport, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// EraseStudent holds details about calls to the EraseStudent method.
+		EraseStudent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.EraseStudentInput
+		}
+	}
+	lockEraseStudent sync.RWMutex
+}
+
+// EraseStudent calls EraseStudentFunc.
+func (mock *StudentErasureUseCasesMock) EraseStudent(ctx context.Context, input identities.EraseStudentInput) (identities.StudentErasureReport, error) {
+	if mock.EraseStudentFunc == nil {
+		panic("StudentErasureUseCasesMock.EraseStudentFunc: method is nil but StudentErasureUseCases.EraseStudent was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.EraseStudentInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockEraseStudent.Lock()
+	mock.calls.EraseStudent = append(mock.calls.EraseStudent, callInfo)
+	mock.lockEraseStudent.Unlock()
+	return mock.EraseStudentFunc(ctx, input)
+}
+
+// EraseStudentCalls gets all the calls that were made to EraseStudent.
+// Check the length with:
+//
+//	len(mockedStudentErasureUseCases.EraseStudentCalls())
+func (mock *StudentErasureUseCasesMock) EraseStudentCalls() []struct {
+	Ctx   context.Context
+	Input identities.EraseStudentInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.EraseStudentInput
+	}
+	mock.lockEraseStudent.RLock()
+	calls = mock.calls.EraseStudent
+	mock.lockEraseStudent.RUnlock()
+	return calls
+}
+
+// Ensure, that DeadLettersUseCasesMock does implement identities.DeadLettersUseCases.
+// If this is not the case, regenerate this file with moq.
+var _ identities.DeadLettersUseCases = &DeadLettersUseCasesMock{}
+
+// DeadLettersUseCasesMock is a mock implementation of identities.DeadLettersUseCases.
+//
+//	func TestSomethingThatUsesDeadLettersUseCases(t *testing.T) {
+//
+//		// make and configure a mocked identities.DeadLettersUseCases
+//		mockedDeadLettersUseCases := &DeadLettersUseCasesMock{
+//			DiscardDeadLetterFunc: func(ctx context.Context, input identities.DeadLetterInput) error {
+//				panic("mock out the DiscardDeadLetter method")
+//			},
+//			ListDeadLettersFunc: func(ctx context.Context, input identities.ListDeadLettersInput) (identities.DeadLettersPage, error) {
+//				panic("mock out the ListDeadLetters method")
+//			},
+//			RetryDeadLetterFunc: func(ctx context.Context, input identities.DeadLetterInput) error {
+//				panic("mock out the RetryDeadLetter method")
+//			},
+//		}
+//
+//		// use mockedDeadLettersUseCases in code that requires identities.DeadLettersUseCases
+//		// and then make assertions.
+//
+//	}
+type DeadLettersUseCasesMock struct {
+	// DiscardDeadLetterFunc mocks the DiscardDeadLetter method.
+	DiscardDeadLetterFunc func(ctx context.Context, input identities.DeadLetterInput) error
+
+	// ListDeadLettersFunc mocks the ListDeadLetters method.
+	ListDeadLettersFunc func(ctx context.Context, input identities.ListDeadLettersInput) (identities.DeadLettersPage, error)
+
+	// RetryDeadLetterFunc mocks the RetryDeadLetter method.
+	RetryDeadLetterFunc func(ctx context.Context, input identities.DeadLetterInput) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// DiscardDeadLetter holds details about calls to the DiscardDeadLetter method.
+		DiscardDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.DeadLetterInput
+		}
+		// ListDeadLetters holds details about calls to the ListDeadLetters method.
+		ListDeadLetters []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.ListDeadLettersInput
+		}
+		// RetryDeadLetter holds details about calls to the RetryDeadLetter method.
+		RetryDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Input is the input argument value.
+			Input identities.DeadLetterInput
+		}
+	}
+	lockDiscardDeadLetter sync.RWMutex
+	lockListDeadLetters   sync.RWMutex
+	lockRetryDeadLetter   sync.RWMutex
+}
+
+// DiscardDeadLetter calls DiscardDeadLetterFunc.
+func (mock *DeadLettersUseCasesMock) DiscardDeadLetter(ctx context.Context, input identities.DeadLetterInput) error {
+	if mock.DiscardDeadLetterFunc == nil {
+		panic("DeadLettersUseCasesMock.DiscardDeadLetterFunc: method is nil but DeadLettersUseCases.DiscardDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.DeadLetterInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockDiscardDeadLetter.Lock()
+	mock.calls.DiscardDeadLetter = append(mock.calls.DiscardDeadLetter, callInfo)
+	mock.lockDiscardDeadLetter.Unlock()
+	return mock.DiscardDeadLetterFunc(ctx, input)
+}
+
+// DiscardDeadLetterCalls gets all the calls that were made to DiscardDeadLetter.
+// Check the length with:
+//
+//	len(mockedDeadLettersUseCases.DiscardDeadLetterCalls())
+func (mock *DeadLettersUseCasesMock) DiscardDeadLetterCalls() []struct {
+	Ctx   context.Context
+	Input identities.DeadLetterInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.DeadLetterInput
+	}
+	mock.lockDiscardDeadLetter.RLock()
+	calls = mock.calls.DiscardDeadLetter
+	mock.lockDiscardDeadLetter.RUnlock()
+	return calls
+}
+
+// ListDeadLetters calls ListDeadLettersFunc.
+func (mock *DeadLettersUseCasesMock) ListDeadLetters(ctx context.Context, input identities.ListDeadLettersInput) (identities.DeadLettersPage, error) {
+	if mock.ListDeadLettersFunc == nil {
+		panic("DeadLettersUseCasesMock.ListDeadLettersFunc: method is nil but DeadLettersUseCases.ListDeadLetters was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.ListDeadLettersInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockListDeadLetters.Lock()
+	mock.calls.ListDeadLetters = append(mock.calls.ListDeadLetters, callInfo)
+	mock.lockListDeadLetters.Unlock()
+	return mock.ListDeadLettersFunc(ctx, input)
+}
+
+// ListDeadLettersCalls gets all the calls that were made to ListDeadLetters.
+// Check the length with:
+//
+//	len(mockedDeadLettersUseCases.ListDeadLettersCalls())
+func (mock *DeadLettersUseCasesMock) ListDeadLettersCalls() []struct {
+	Ctx   context.Context
+	Input identities.ListDeadLettersInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.ListDeadLettersInput
+	}
+	mock.lockListDeadLetters.RLock()
+	calls = mock.calls.ListDeadLetters
+	mock.lockListDeadLetters.RUnlock()
+	return calls
+}
+
+// RetryDeadLetter calls RetryDeadLetterFunc.
+func (mock *DeadLettersUseCasesMock) RetryDeadLetter(ctx context.Context, input identities.DeadLetterInput) error {
+	if mock.RetryDeadLetterFunc == nil {
+		panic("DeadLettersUseCasesMock.RetryDeadLetterFunc: method is nil but DeadLettersUseCases.RetryDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Input identities.DeadLetterInput
+	}{
+		Ctx:   ctx,
+		Input: input,
+	}
+	mock.lockRetryDeadLetter.Lock()
+	mock.calls.RetryDeadLetter = append(mock.calls.RetryDeadLetter, callInfo)
+	mock.lockRetryDeadLetter.Unlock()
+	return mock.RetryDeadLetterFunc(ctx, input)
+}
+
+// RetryDeadLetterCalls gets all the calls that were made to RetryDeadLetter.
+// Check the length with:
+//
+//	len(mockedDeadLettersUseCases.RetryDeadLetterCalls())
+func (mock *DeadLettersUseCasesMock) RetryDeadLetterCalls() []struct {
+	Ctx   context.Context
+	Input identities.DeadLetterInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Input identities.DeadLetterInput
+	}
+	mock.lockRetryDeadLetter.RLock()
+	calls = mock.calls.RetryDeadLetter
+	mock.lockRetryDeadLetter.RUnlock()
+	return calls
+}
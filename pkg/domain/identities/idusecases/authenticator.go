@@ -2,9 +2,12 @@ package idusecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 
@@ -13,40 +16,111 @@ import (
 )
 
 type tokenMaker interface {
-	createToken(ctx context.Context, userID string) (entities.Token, error)
-	verifyToken(ctx context.Context, hash string) error
+	createToken(
+		ctx context.Context,
+		userID string,
+		client entities.AuthenticationClient,
+		audience string,
+		claims entities.TokenClaims,
+	) (entities.Token, error)
+	verifyToken(ctx context.Context, hash string, audience string) (entities.Token, error)
+	parseToken(hash string, audience string) (entities.Token, error)
+	digest(hash string) string
 }
 
+// Token verification modes, traced in the verification spans.
+const (
+	verificationModeStored    = "stored"
+	verificationModeStateless = "stateless"
+)
+
+// Config TokenAudiences are the registered services tokens may be requested for.
 type Config interface {
 	TokenSecret() string
+	TokenDigestKey() string
 	TokenIssuer() string
 	TokenDuration() time.Duration
+	TokenAudiences() []string
 }
 
 type StudentAuthenticator struct {
 	tokenMaker
 	studentsRepository identities.StudentListerRepository
+	loginsRepository   identities.LoginHistoryRepository
+	activityProducer   identities.AuthenticationProducer
+	revocations        identities.TokenRevocationList
+	cache              *verificationCache
+	claims             *claimsBuilder
+	audiences          map[string]bool
 	tracer             trace.Tracer
 }
 
-func NewStudentJWTAuthenticator(studentRepository identities.StudentListerRepository, tokenRepository identities.TokenRegistererRepository, config Config) StudentAuthenticator {
+func NewStudentJWTAuthenticator(
+	studentRepository identities.StudentListerRepository,
+	tokenRepository identities.TokenRegistererRepository,
+	loginRepository identities.LoginHistoryRepository,
+	activityProducer identities.AuthenticationProducer,
+	config Config,
+) StudentAuthenticator {
 	tracer := otel.Tracer(tracerName)
 
 	maker := jwtTokenMaker{
 		secret:     config.TokenSecret(),
+		digestKey:  config.TokenDigestKey(),
 		issuer:     config.TokenIssuer(),
 		duration:   config.TokenDuration(),
 		repository: tokenRepository,
 		tracer:     tracer,
 	}
 
+	audiences := make(map[string]bool, len(config.TokenAudiences()))
+	for _, audience := range config.TokenAudiences() {
+		audiences[audience] = true
+	}
+
 	return StudentAuthenticator{
 		tokenMaker:         maker,
 		studentsRepository: studentRepository,
+		loginsRepository:   loginRepository,
+		activityProducer:   activityProducer,
+		audiences:          audiences,
 		tracer:             tracer,
 	}
 }
 
+// Stateless returns a copy of the authenticator verifying tokens by their signature and expiration alone, rather
+// than against the tokens store. Revoked tokens are refused through the revocation list instead, see
+// RevokingSessions.
+func (s StudentAuthenticator) Stateless(revocations identities.TokenRevocationList) StudentAuthenticator {
+	s.revocations = revocations
+	return s
+}
+
+// Cached returns a copy of the authenticator keeping up to size verified tokens in memory for maxTTL at most.
+// Cached tokens are dropped once revoked, so the sessions must be revoked through RevokingSessions.
+func (s StudentAuthenticator) Cached(revocations identities.TokenRevocationList, size int, maxTTL time.Duration) (StudentAuthenticator, error) {
+	cache, err := newVerificationCache(otel.Meter(meterName), revocations, size, maxTTL)
+	if err != nil {
+		return StudentAuthenticator{}, err
+	}
+
+	s.cache = cache
+	return s, nil
+}
+
+// WithClaims returns a copy of the authenticator adding the configured claims about the student to the tokens.
+// The optional claims are left out of the tokens they do not fit in, logins only fail with ErrClaimsTooLarge when
+// the required ones exceed their budget.
+func (s StudentAuthenticator) WithClaims(students identities.StudentDataRepository, config ClaimsConfig) (StudentAuthenticator, error) {
+	builder, err := newClaimsBuilder(students, config)
+	if err != nil {
+		return StudentAuthenticator{}, err
+	}
+
+	s.claims = &builder
+	return s, nil
+}
+
 func (s StudentAuthenticator) AuthenticateStudent(ctx context.Context, input identities.AuthenticateStudentInput) (entities.Token, error) {
 	ctx, span := s.tracer.Start(ctx, "StudentAuthenticator.AuthenticateStudent")
 	defer span.End()
@@ -60,41 +134,175 @@ func (s StudentAuthenticator) AuthenticateStudent(ctx context.Context, input ide
 		return entities.Token{}, identities.ErrEmptySecret
 	}
 
+	if input.Audience != "" && !s.audiences[input.Audience] {
+		err := fmt.Errorf("%w: %q", identities.ErrUnknownAudience, input.Audience)
+		span.RecordError(err)
+		return entities.Token{}, err
+	}
+
 	registeredSecret, err := s.studentsRepository.GetStudentSecret(ctx, input.StudentID)
 	if err != nil {
 		span.RecordError(err)
+		if errors.Is(err, identities.ErrStudentNotFound) {
+			activity := entities.NewAuthenticationActivity(input.StudentID, entities.AuthOutcomeStudentNotFound, input.Client)
+			s.produceActivity(ctx, s.activityProducer.ProduceLoginFailed, activity)
+		}
 		return entities.Token{}, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(registeredSecret), []byte(input.StudentSecret))
 	if err != nil {
 		span.RecordError(err)
+		s.recordLogin(ctx, entities.NewLoginAttempt(input.StudentID, entities.LoginFailureInvalidCredentials), input.Client, "")
 		return entities.Token{}, err
 	}
 
-	token, err := s.createToken(ctx, input.StudentID)
+	err = s.checkStudentStatus(ctx, input.StudentID)
 	if err != nil {
 		span.RecordError(err)
+		switch {
+		case errors.Is(err, identities.ErrStudentSuspended):
+			s.recordLogin(ctx, entities.NewLoginAttempt(input.StudentID, entities.LoginFailureStudentSuspended), input.Client, "")
+		case errors.Is(err, identities.ErrStudentCancelled):
+			s.recordLogin(ctx, entities.NewLoginAttempt(input.StudentID, entities.LoginFailureStudentCancelled), input.Client, "")
+		}
 		return entities.Token{}, err
 	}
 
+	var claims entities.TokenClaims
+	if s.claims != nil {
+		claims, err = s.claims.build(ctx, input.StudentID)
+		if err != nil {
+			span.RecordError(err)
+			return entities.Token{}, err
+		}
+	}
+
+	token, err := s.createToken(ctx, input.StudentID, input.Client, input.Audience, claims)
+	if err != nil {
+		span.RecordError(err)
+		return entities.Token{}, err
+	}
+
+	s.recordLogin(ctx, entities.NewLoginAttempt(input.StudentID, ""), input.Client, token.ID)
 	return token, nil
 }
 
-func (s StudentAuthenticator) VerifyAuth(ctx context.Context, hash string) error {
+// VerifyAuth returns the verified token without its signed token, its claims being as of the login. The student
+// status is not checked again, the sessions of the students who may no longer authenticate are revoked.
+func (s StudentAuthenticator) VerifyAuth(ctx context.Context, hash string, audience string) (entities.Token, error) {
 	ctx, span := s.tracer.Start(ctx, "StudentAuthenticator.VerifyAuth")
 	defer span.End()
 
 	if hash == "" {
 		span.RecordError(identities.ErrEmptyToken)
-		return identities.ErrEmptyToken
+		return entities.Token{}, identities.ErrEmptyToken
 	}
 
-	err := s.tokenMaker.verifyToken(ctx, hash)
+	token, err := s.verifyToken(ctx, hash, audience)
 	if err != nil {
 		span.RecordError(err)
+		return entities.Token{}, err
+	}
+
+	token.Hash = ""
+	return token, nil
+}
+
+// verifyToken looks the token up in the cache first, when there is one. Cached tokens are cached regardless of
+// the audience they were verified for, so it is checked again.
+func (s StudentAuthenticator) verifyToken(ctx context.Context, hash string, audience string) (entities.Token, error) {
+	if s.cache == nil {
+		return s.verifyUncachedToken(ctx, hash, audience)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	digest := s.tokenMaker.digest(hash)
+	if token, ok := s.cache.get(ctx, digest); ok {
+		span.SetAttributes(attribute.Bool("auth.verification_cache_hit", true))
+		if audience != "" && token.Audience != audience {
+			return entities.Token{}, identities.ErrWrongAudience
+		}
+		return token, nil
+	}
+	span.SetAttributes(attribute.Bool("auth.verification_cache_hit", false))
+
+	token, err := s.verifyUncachedToken(ctx, hash, audience)
+	if err != nil {
+		return entities.Token{}, err
+	}
+
+	s.cache.add(digest, token)
+	return token, nil
+}
+
+// verifyUncachedToken checks the token against the tokens store, or against the revocation list in the
+// stateless mode.
+func (s StudentAuthenticator) verifyUncachedToken(ctx context.Context, hash string, audience string) (entities.Token, error) {
+	span := trace.SpanFromContext(ctx)
+	if s.revocations == nil {
+		span.SetAttributes(attribute.String("auth.verification_mode", verificationModeStored))
+		return s.tokenMaker.verifyToken(ctx, hash, audience)
+	}
+	span.SetAttributes(attribute.String("auth.verification_mode", verificationModeStateless))
+
+	token, err := s.tokenMaker.parseToken(hash, audience)
+	if err != nil {
+		return entities.Token{}, err
+	}
+
+	if s.revocations.IsRevoked(token.ID) {
+		return entities.Token{}, identities.ErrTokenRevoked
+	}
+
+	return token, nil
+}
+
+// recordLogin keeps the login history and publishes the login activity, failing to do so must not prevent
+// the student from logging in.
+func (s StudentAuthenticator) recordLogin(ctx context.Context, attempt entities.LoginAttempt, client entities.AuthenticationClient, tokenID string) {
+	err := s.loginsRepository.RecordLogin(ctx, attempt)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+
+	if !attempt.Succeeded {
+		activity := entities.NewAuthenticationActivity(attempt.StudentID, attempt.FailureReason, client)
+		s.produceActivity(ctx, s.activityProducer.ProduceLoginFailed, activity)
+		return
+	}
+
+	activity := entities.NewAuthenticationActivity(attempt.StudentID, entities.AuthOutcomeSucceeded, client)
+	activity.TokenID = tokenID
+	s.produceActivity(ctx, s.activityProducer.ProduceStudentLoggedIn, activity)
+}
+
+// produceActivity publishes the authentication activity, failing to do so must not prevent the student from
+// logging in either.
+func (s StudentAuthenticator) produceActivity(
+	ctx context.Context,
+	produce func(context.Context, entities.AuthenticationActivity) error,
+	activity entities.AuthenticationActivity,
+) {
+	err := produce(ctx, activity)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+}
+
+// checkStudentStatus refuses students whose status does not allow them to use the platform.
+func (s StudentAuthenticator) checkStudentStatus(ctx context.Context, studentID string) error {
+	status, err := s.studentsRepository.GetStudentStatus(ctx, studentID)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	switch status {
+	case entities.StudentStatusSuspended:
+		return identities.ErrStudentSuspended
+	case entities.StudentStatusCancelled:
+		return identities.ErrStudentCancelled
+	default:
+		return nil
+	}
 }
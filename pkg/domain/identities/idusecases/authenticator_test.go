@@ -12,6 +12,7 @@ import (
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
 	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
 	"github.com/tccav/identity-service/pkg/gateways/postgres"
 	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
 	"github.com/tccav/identity-service/pkg/gateways/redis"
@@ -19,15 +20,21 @@ import (
 )
 
 type config struct {
-	secret   string
-	issuer   string
-	duration time.Duration
+	secret    string
+	digestKey string
+	issuer    string
+	duration  time.Duration
+	audiences []string
 }
 
 func (v config) TokenSecret() string {
 	return v.secret
 }
 
+func (v config) TokenDigestKey() string {
+	return v.digestKey
+}
+
 func (v config) TokenIssuer() string {
 	return v.issuer
 }
@@ -36,10 +43,16 @@ func (v config) TokenDuration() time.Duration {
 	return v.duration
 }
 
+func (v config) TokenAudiences() []string {
+	return v.audiences
+}
+
 var validConfig = config{
-	secret:   "secret_secret",
-	issuer:   "uerj",
-	duration: time.Hour,
+	secret:    "secret_secret",
+	digestKey: "digest_secret",
+	issuer:    "uerj",
+	duration:  time.Hour,
+	audiences: []string{"courses-service", "grades-service"},
 }
 
 func TestStudentAuthenticator_AuthenticateStudent(t *testing.T) {
@@ -61,10 +74,11 @@ func TestStudentAuthenticator_AuthenticateStudent(t *testing.T) {
 			CPF:       "11111111030",
 			Email:     "jdoe@ol.com",
 			BirthDate: time.Now().UTC(),
+			Status:    entities.StudentStatusActive,
 		}
 
 		db := pgfixtures.NewDB(t)
-		studentsRepository := postgres.NewStudentsRepository(db)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
 
 		err = studentsRepository.CreateStudent(ctx, validStudent)
 		require.NoError(t, err)
@@ -72,11 +86,14 @@ func TestStudentAuthenticator_AuthenticateStudent(t *testing.T) {
 		rDB := rfixtures.NewDB(t)
 		tokensRepository := redis.NewTokensRepository(rDB)
 
-		s := NewStudentJWTAuthenticator(studentsRepository, tokensRepository, validConfig)
+		activityProducer := newActivityProducer()
+		s := NewStudentJWTAuthenticator(studentsRepository, tokensRepository, postgres.NewLoginHistoryRepository(db), activityProducer, validConfig)
 
+		client := entities.AuthenticationClient{IP: "203.0.113.7", UserAgent: "test-agent"}
 		got, err := s.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{
 			StudentID:     validStudent.ID,
 			StudentSecret: password,
+			Client:        client,
 		})
 
 		assert.NoError(t, err)
@@ -84,8 +101,83 @@ func TestStudentAuthenticator_AuthenticateStudent(t *testing.T) {
 		assert.NotEmpty(t, got.ID)
 		assert.NotEmpty(t, got.ExpirationDate)
 		assert.NotEmpty(t, got.Hash)
+
+		stored, err := rDB.HGetAll(ctx, "token:"+got.ID).Result()
+		require.NoError(t, err)
+		assert.NotContains(t, stored, got.Hash)
+		assert.NotEqual(t, got.Hash, stored["digest"])
+		assert.Equal(t, validStudent.ID, stored["user_id"])
+		assert.Equal(t, client.IP, stored["ip"])
+		assert.Equal(t, client.UserAgent, stored["user_agent"])
+
+		logins, err := postgres.NewLoginHistoryRepository(db).ListLogins(ctx, validStudent.ID)
+		require.NoError(t, err)
+		require.Len(t, logins, 1)
+		assert.True(t, logins[0].Succeeded)
+
+		activities := activityProducer.ProduceStudentLoggedInCalls()
+		require.Len(t, activities, 1)
+		assert.Equal(t, validStudent.ID, activities[0].Activity.StudentID)
+		assert.Equal(t, got.ID, activities[0].Activity.TokenID)
+		assert.Equal(t, entities.AuthOutcomeSucceeded, activities[0].Activity.Outcome)
+		assert.Equal(t, client.IP, activities[0].Activity.IP)
+		assert.Equal(t, client.UserAgent, activities[0].Activity.UserAgent)
 	})
 
+	statusCases := []struct {
+		name       string
+		status     entities.StudentStatus
+		wantErr    error
+		wantReason string
+	}{
+		{
+			name:       "should refuse suspended student",
+			status:     entities.StudentStatusSuspended,
+			wantErr:    identities.ErrStudentSuspended,
+			wantReason: entities.LoginFailureStudentSuspended,
+		},
+		{
+			name:       "should refuse cancelled student",
+			status:     entities.StudentStatusCancelled,
+			wantErr:    identities.ErrStudentCancelled,
+			wantReason: entities.LoginFailureStudentCancelled,
+		},
+	}
+	for _, testCase := range statusCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			password := "test_password"
+			db := pgfixtures.NewDB(t)
+			studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+			student := newStoredStudent(t, studentsRepository, password, tc.status)
+
+			loginsRepository := postgres.NewLoginHistoryRepository(db)
+			activityProducer := newActivityProducer()
+			s := NewStudentJWTAuthenticator(studentsRepository, nil, loginsRepository, activityProducer, validConfig)
+
+			got, err := s.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{
+				StudentID:     student.ID,
+				StudentSecret: password,
+			})
+
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Empty(t, got)
+
+			logins, err := loginsRepository.ListLogins(ctx, student.ID)
+			require.NoError(t, err)
+			require.Len(t, logins, 1)
+			assert.False(t, logins[0].Succeeded)
+			assert.Equal(t, tc.wantReason, logins[0].FailureReason)
+
+			activities := activityProducer.ProduceLoginFailedCalls()
+			require.Len(t, activities, 1)
+			assert.Equal(t, tc.wantReason, activities[0].Activity.Outcome)
+		})
+	}
+
 	tt := []struct {
 		name    string
 		input   identities.AuthenticateStudentInput
@@ -105,6 +197,15 @@ func TestStudentAuthenticator_AuthenticateStudent(t *testing.T) {
 			},
 			wantErr: identities.ErrEmptySecret,
 		},
+		{
+			name: "should fail because audience is not a registered service",
+			input: identities.AuthenticateStudentInput{
+				StudentID:     "123456789",
+				StudentSecret: "test_password",
+				Audience:      "unknown-service",
+			},
+			wantErr: identities.ErrUnknownAudience,
+		},
 		{
 			name: "should fail because student does not exist",
 			input: identities.AuthenticateStudentInput{
@@ -121,9 +222,9 @@ func TestStudentAuthenticator_AuthenticateStudent(t *testing.T) {
 			ctx := context.Background()
 
 			db := pgfixtures.NewDB(t)
-			studentsRepository := postgres.NewStudentsRepository(db)
+			studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
 
-			s := NewStudentJWTAuthenticator(studentsRepository, nil, validConfig)
+			s := NewStudentJWTAuthenticator(studentsRepository, nil, postgres.NewLoginHistoryRepository(db), newActivityProducer(), validConfig)
 
 			got, err := s.AuthenticateStudent(ctx, tc.input)
 
@@ -142,22 +243,176 @@ func TestStudentAuthenticator_VerifyAuth(t *testing.T) {
 		// prepare
 		ctx := context.Background()
 
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+
 		rDB := rfixtures.NewDB(t)
 		tokensRepository := redis.NewTokensRepository(rDB)
 
-		s := NewStudentJWTAuthenticator(nil, tokensRepository, validConfig)
+		s := NewStudentJWTAuthenticator(studentsRepository, tokensRepository, nil, newActivityProducer(), validConfig)
 
-		userID := uuid.NewString()
-		token, err := s.createToken(ctx, userID)
+		token, err := s.createToken(ctx, student.ID, entities.AuthenticationClient{}, "", entities.TokenClaims{})
 		require.NoError(t, err)
 
 		// test
-		err = s.VerifyAuth(ctx, token.Hash)
+		_, err = s.VerifyAuth(ctx, token.Hash, "")
 
 		// assert
 		assert.NoError(t, err)
 	})
 
+	t.Run("should verify a token stored before digests were", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+
+		rDB := rfixtures.NewDB(t)
+		s := NewStudentJWTAuthenticator(studentsRepository, redis.NewTokensRepository(rDB), nil, newActivityProducer(), validConfig)
+
+		token, err := jwtTokenMaker{secret: validConfig.secret, issuer: validConfig.issuer, duration: validConfig.duration}.
+			buildSignedJWT(student.ID, "", entities.TokenClaims{})
+		require.NoError(t, err)
+		require.NoError(t, rDB.Set(ctx, "token:"+token.ID, token.Hash, time.Hour).Err())
+
+		// test
+		_, err = s.VerifyAuth(ctx, token.Hash, "")
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should verify a token missing from the store in the stateless mode", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		revocations := &idmocks.TokenRevocationListMock{
+			IsRevokedFunc: func(string) bool { return false },
+		}
+		// without a students store, verifying must not reach the database
+		s := NewStudentJWTAuthenticator(nil, redis.NewTokensRepository(rfixtures.NewDB(t)), nil, newActivityProducer(), validConfig).
+			Stateless(revocations)
+
+		token, err := jwtTokenMaker{secret: validConfig.secret, issuer: validConfig.issuer, duration: validConfig.duration}.
+			buildSignedJWT("201320509911", "", entities.TokenClaims{})
+		require.NoError(t, err)
+
+		// test
+		_, err = s.VerifyAuth(ctx, token.Hash, "")
+
+		// assert
+		assert.NoError(t, err)
+		require.Len(t, revocations.IsRevokedCalls(), 1)
+		assert.Equal(t, token.ID, revocations.IsRevokedCalls()[0].ID)
+	})
+
+	t.Run("should refuse a revoked token in the stateless mode", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+
+		s := NewStudentJWTAuthenticator(studentsRepository, redis.NewTokensRepository(rfixtures.NewDB(t)), nil, newActivityProducer(), validConfig)
+
+		token, err := s.createToken(ctx, student.ID, entities.AuthenticationClient{}, "", entities.TokenClaims{})
+		require.NoError(t, err)
+
+		s = s.Stateless(&idmocks.TokenRevocationListMock{
+			IsRevokedFunc: func(id string) bool { return id == token.ID },
+		})
+
+		// test
+		_, err = s.VerifyAuth(ctx, token.Hash, "")
+
+		// assert
+		assert.ErrorIs(t, err, identities.ErrTokenRevoked)
+	})
+
+	t.Run("should verify a cached token without the tokens and students stores", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		// without a students store, verifying must not reach the database
+		var studentsRepository identities.StudentListerRepository
+		student := entities.Student{ID: "201320509911"}
+
+		rDB := rfixtures.NewDB(t)
+		tokensRepository := redis.NewTokensRepository(rDB)
+		revocations := &idmocks.TokenRevocationListMock{
+			IsRevokedFunc: func(string) bool { return false },
+			OnRevokeFunc:  func(func(ids ...string)) {},
+		}
+		s, err := NewStudentJWTAuthenticator(studentsRepository, tokensRepository, nil, newActivityProducer(), validConfig).
+			Cached(revocations, 10, time.Minute)
+		require.NoError(t, err)
+
+		token, err := s.createToken(ctx, student.ID, entities.AuthenticationClient{}, "", entities.TokenClaims{})
+		require.NoError(t, err)
+		_, err = s.VerifyAuth(ctx, token.Hash, "")
+		require.NoError(t, err)
+		require.NoError(t, rDB.FlushDB(ctx).Err())
+
+		// test
+		_, err = s.VerifyAuth(ctx, token.Hash, "")
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should check the audience only when one is expected", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+
+		revocations := &idmocks.TokenRevocationListMock{
+			IsRevokedFunc: func(string) bool { return false },
+			OnRevokeFunc:  func(func(ids ...string)) {},
+		}
+		s := NewStudentJWTAuthenticator(studentsRepository, redis.NewTokensRepository(rfixtures.NewDB(t)), postgres.NewLoginHistoryRepository(db), newActivityProducer(), validConfig)
+		cached, err := s.Cached(revocations, 10, time.Minute)
+		require.NoError(t, err)
+
+		token, err := s.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{
+			StudentID:     student.ID,
+			StudentSecret: "test_password",
+			Audience:      "courses-service",
+		})
+		require.NoError(t, err)
+
+		for _, authenticator := range []StudentAuthenticator{s, cached} {
+			// test, twice so the cached one verifies the token from its cache
+			for i := 0; i < 2; i++ {
+				got, err := authenticator.VerifyAuth(ctx, token.Hash, "courses-service")
+				require.NoError(t, err)
+				assert.Equal(t, "courses-service", got.Audience)
+
+				_, err = authenticator.VerifyAuth(ctx, token.Hash, "")
+				assert.NoError(t, err)
+
+				_, err = authenticator.VerifyAuth(ctx, token.Hash, "grades-service")
+				assert.ErrorIs(t, err, identities.ErrWrongAudience)
+			}
+		}
+	})
+
 	tt := []struct {
 		name    string
 		input   string
@@ -214,9 +469,9 @@ func TestStudentAuthenticator_VerifyAuth(t *testing.T) {
 			rDB := rfixtures.NewDB(t)
 			tokensRepository := redis.NewTokensRepository(rDB)
 
-			s := NewStudentJWTAuthenticator(nil, tokensRepository, validConfig)
+			s := NewStudentJWTAuthenticator(nil, tokensRepository, nil, newActivityProducer(), validConfig)
 
-			err := s.VerifyAuth(ctx, tc.input)
+			_, err := s.VerifyAuth(ctx, tc.input, "")
 
 			assert.ErrorIs(t, err, tc.wantErr)
 		})
@@ -230,8 +485,44 @@ func generateToken(t *testing.T, config config) entities.Token {
 		secret:   config.secret,
 		issuer:   config.issuer,
 		duration: config.duration,
-	}.buildSignedJWT(uuid.NewString())
+	}.buildSignedJWT(uuid.NewString(), "", entities.TokenClaims{})
 	require.NoError(t, err)
 
 	return token
 }
+
+func newStoredStudent(t *testing.T, repository postgres.StudentsRepository, password string, status entities.StudentStatus) entities.Student {
+	t.Helper()
+
+	encPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	student := entities.Student{
+		ID:        "201116548712",
+		Name:      "John Doe",
+		Secret:    string(encPassword),
+		CPF:       "11111111030",
+		Email:     "jdoe@ol.com",
+		BirthDate: time.Now().UTC(),
+		Status:    status,
+	}
+
+	err = repository.CreateStudent(context.Background(), student)
+	require.NoError(t, err)
+
+	return student
+}
+
+func newActivityProducer() *idmocks.AuthenticationProducerMock {
+	return &idmocks.AuthenticationProducerMock{
+		ProduceStudentLoggedInFunc: func(ctx context.Context, activity entities.AuthenticationActivity) error {
+			return nil
+		},
+		ProduceLoginFailedFunc: func(ctx context.Context, activity entities.AuthenticationActivity) error {
+			return nil
+		},
+		ProduceTokenRevokedFunc: func(ctx context.Context, activity entities.AuthenticationActivity) error {
+			return nil
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package idusecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+// Claims the tokens may carry about the student, as named in the tokens.
+const (
+	ClaimName      = "name"
+	ClaimCourseIDs = "course_ids"
+	ClaimStatus    = "status"
+)
+
+// ClaimsConfig TokenClaims are the names of the claims added to the tokens. TokenClaimsMaxSize is the budget in
+// bytes of the claims encoded as JSON.
+type ClaimsConfig interface {
+	TokenClaims() []string
+	TokenClaimsMaxSize() int
+}
+
+// claimsBuilder takes the claims from the student record, the course the student is enrolled in being its only
+// enrollment.
+type claimsBuilder struct {
+	students identities.StudentDataRepository
+	claims   map[string]bool
+	maxSize  int
+}
+
+func newClaimsBuilder(students identities.StudentDataRepository, config ClaimsConfig) (claimsBuilder, error) {
+	claims := make(map[string]bool, len(config.TokenClaims()))
+	for _, claim := range config.TokenClaims() {
+		switch claim {
+		case ClaimName, ClaimCourseIDs, ClaimStatus:
+			claims[claim] = true
+		default:
+			return claimsBuilder{}, fmt.Errorf("%w: %q", identities.ErrUnknownClaim, claim)
+		}
+	}
+
+	return claimsBuilder{
+		students: students,
+		claims:   claims,
+		maxSize:  config.TokenClaimsMaxSize(),
+	}, nil
+}
+
+func (b claimsBuilder) build(ctx context.Context, studentID string) (entities.TokenClaims, error) {
+	var claims entities.TokenClaims
+	if len(b.claims) == 0 {
+		return claims, nil
+	}
+
+	student, err := b.students.GetStudent(ctx, studentID)
+	if err != nil {
+		return entities.TokenClaims{}, err
+	}
+
+	if b.claims[ClaimName] {
+		claims.Name = student.Name
+	}
+	if b.claims[ClaimCourseIDs] && student.CourseID != "" {
+		claims.CourseIDs = []string{student.CourseID}
+	}
+	if b.claims[ClaimStatus] {
+		claims.Status = student.Status
+	}
+
+	return b.fit(ctx, claims)
+}
+
+// optionalClaims are dropped in this order until the claims fit their budget, the others are required.
+var optionalClaims = []struct {
+	name string
+	drop func(claims *entities.TokenClaims)
+}{
+	{ClaimName, func(claims *entities.TokenClaims) { claims.Name = "" }},
+	{ClaimCourseIDs, func(claims *entities.TokenClaims) { claims.CourseIDs = nil }},
+}
+
+// fit drops the optional claims until the claims fit their budget, so a long name or many courses do not keep
+// the student from logging in. It fails only when the required claims alone exceed the budget.
+func (b claimsBuilder) fit(ctx context.Context, claims entities.TokenClaims) (entities.TokenClaims, error) {
+	if b.maxSize <= 0 {
+		return claims, nil
+	}
+
+	for next := 0; ; next++ {
+		encoded, err := json.Marshal(encodeClaims(claims))
+		if err != nil {
+			return entities.TokenClaims{}, err
+		}
+		if len(encoded) <= b.maxSize {
+			return claims, nil
+		}
+		if next == len(optionalClaims) {
+			return entities.TokenClaims{}, fmt.Errorf("%w: %d of %d bytes", identities.ErrClaimsTooLarge, len(encoded), b.maxSize)
+		}
+
+		optionalClaims[next].drop(&claims)
+		trace.SpanFromContext(ctx).AddEvent("token claim dropped", trace.WithAttributes(
+			attribute.String("claim", optionalClaims[next].name),
+			attribute.Int("claims.size", len(encoded)),
+		))
+	}
+}
+
+// encodeClaims leaves the unset claims out.
+func encodeClaims(claims entities.TokenClaims) map[string]any {
+	encoded := map[string]any{}
+	if claims.Name != "" {
+		encoded[ClaimName] = claims.Name
+	}
+	if len(claims.CourseIDs) > 0 {
+		encoded[ClaimCourseIDs] = claims.CourseIDs
+	}
+	if claims.Status != "" {
+		encoded[ClaimStatus] = string(claims.Status)
+	}
+	return encoded
+}
+
+// decodeClaims ignores the claims of unexpected types, only this app signs the tokens.
+func decodeClaims(private map[string]any) entities.TokenClaims {
+	var claims entities.TokenClaims
+	claims.Name, _ = private[ClaimName].(string)
+	claims.CourseIDs = decodeStrings(private[ClaimCourseIDs])
+	if status, ok := private[ClaimStatus].(string); ok {
+		claims.Status = entities.StudentStatus(status)
+	}
+	return claims
+}
+
+func decodeStrings(value any) []string {
+	values, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	decoded := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			decoded = append(decoded, s)
+		}
+	}
+	return decoded
+}
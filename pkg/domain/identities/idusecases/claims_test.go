@@ -0,0 +1,177 @@
+package idusecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/redis"
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+type claimsConfig struct {
+	claims  []string
+	maxSize int
+}
+
+func (c claimsConfig) TokenClaims() []string {
+	return c.claims
+}
+
+func (c claimsConfig) TokenClaimsMaxSize() int {
+	return c.maxSize
+}
+
+func TestStudentAuthenticator_WithClaims(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should expose the claims of the student on verification", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+
+		s, err := NewStudentJWTAuthenticator(studentsRepository, redis.NewTokensRepository(rfixtures.NewDB(t)), postgres.NewLoginHistoryRepository(db), newActivityProducer(), validConfig).
+			WithClaims(studentsRepository, claimsConfig{
+				claims:  []string{ClaimName, ClaimCourseIDs, ClaimStatus},
+				maxSize: 1024,
+			})
+		require.NoError(t, err)
+
+		token, err := s.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{
+			StudentID:     student.ID,
+			StudentSecret: "test_password",
+		})
+		require.NoError(t, err)
+
+		// test
+		got, err := s.VerifyAuth(ctx, token.Hash, "")
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, entities.TokenClaims{
+			Name:   student.Name,
+			Status: entities.StudentStatusActive,
+		}, got.Claims)
+		assert.Equal(t, token.ID, got.ID)
+		assert.Equal(t, student.ID, got.UserID)
+		assert.Empty(t, got.Hash)
+	})
+
+	t.Run("should refuse the login when the required claims exceed their budget", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+
+		s, err := NewStudentJWTAuthenticator(studentsRepository, redis.NewTokensRepository(rfixtures.NewDB(t)), postgres.NewLoginHistoryRepository(db), newActivityProducer(), validConfig).
+			WithClaims(studentsRepository, claimsConfig{claims: []string{ClaimName, ClaimStatus}, maxSize: 16})
+		require.NoError(t, err)
+
+		// test
+		_, err = s.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{
+			StudentID:     student.ID,
+			StudentSecret: "test_password",
+		})
+
+		// assert
+		assert.ErrorIs(t, err, identities.ErrClaimsTooLarge)
+	})
+
+	t.Run("should refuse an unknown claim", func(t *testing.T) {
+		t.Parallel()
+
+		// test
+		_, err := NewStudentJWTAuthenticator(nil, nil, nil, nil, validConfig).
+			WithClaims(nil, claimsConfig{claims: []string{ClaimName, "cpf"}})
+
+		// assert
+		assert.ErrorIs(t, err, identities.ErrUnknownClaim)
+	})
+}
+
+// studentDataFunc stubs the student records the claims are taken from.
+type studentDataFunc func(ctx context.Context, id string) (entities.Student, error)
+
+func (f studentDataFunc) GetStudent(ctx context.Context, id string) (entities.Student, error) {
+	return f(ctx, id)
+}
+
+func TestClaimsBuilder_build(t *testing.T) {
+	t.Parallel()
+
+	students := studentDataFunc(func(ctx context.Context, id string) (entities.Student, error) {
+		return entities.Student{ID: id, Name: "Maria Silva", CourseID: "1", Status: entities.StudentStatusActive}, nil
+	})
+
+	// the claims below are encoded in 59 bytes, 38 without the name and 19 without the course ids either
+	tt := []struct {
+		name     string
+		maxSize  int
+		expected entities.TokenClaims
+		wantErr  error
+	}{
+		{
+			name:     "should keep every claim within the budget",
+			maxSize:  59,
+			expected: entities.TokenClaims{Name: "Maria Silva", CourseIDs: []string{"1"}, Status: entities.StudentStatusActive},
+		},
+		{
+			name:     "should keep every claim without a budget",
+			expected: entities.TokenClaims{Name: "Maria Silva", CourseIDs: []string{"1"}, Status: entities.StudentStatusActive},
+		},
+		{
+			name:     "should drop the name first",
+			maxSize:  58,
+			expected: entities.TokenClaims{CourseIDs: []string{"1"}, Status: entities.StudentStatusActive},
+		},
+		{
+			name:     "should drop the course ids after the name",
+			maxSize:  37,
+			expected: entities.TokenClaims{Status: entities.StudentStatusActive},
+		},
+		{
+			name:    "should fail because the required claims exceed the budget",
+			maxSize: 18,
+			wantErr: identities.ErrClaimsTooLarge,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			builder, err := newClaimsBuilder(students, claimsConfig{
+				claims:  []string{ClaimName, ClaimCourseIDs, ClaimStatus},
+				maxSize: tc.maxSize,
+			})
+			require.NoError(t, err)
+
+			// test
+			got, err := builder.build(context.Background(), "201116548712")
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
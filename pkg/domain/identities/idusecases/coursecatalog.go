@@ -0,0 +1,65 @@
+package idusecases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type CourseCatalogUseCase struct {
+	repository identities.CoursesRepository
+	tracer     trace.Tracer
+}
+
+func NewCourseCatalogUseCase(repository identities.CoursesRepository) CourseCatalogUseCase {
+	return CourseCatalogUseCase{
+		repository: repository,
+		tracer:     otel.Tracer(tracerName),
+	}
+}
+
+func (c CourseCatalogUseCase) SaveCourse(ctx context.Context, input identities.SaveCourseInput) error {
+	ctx, span := c.tracer.Start(ctx, "CourseCatalogUseCase.SaveCourse")
+	defer span.End()
+
+	_, err := uuid.Parse(input.ID)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, err)
+		span.RecordError(err)
+		return err
+	}
+
+	err = c.repository.SaveCourse(ctx, entities.NewCourse(input.ID, input.Name))
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (c CourseCatalogUseCase) CloseCourse(ctx context.Context, id string) error {
+	ctx, span := c.tracer.Start(ctx, "CourseCatalogUseCase.CloseCourse")
+	defer span.End()
+
+	_, err := uuid.Parse(id)
+	if err != nil {
+		err = fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, err)
+		span.RecordError(err)
+		return err
+	}
+
+	err = c.repository.CloseCourse(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package idusecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+)
+
+func TestCourseCatalogUseCase_SaveCourse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create and then update course", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		repository := postgres.NewCoursesRepository(db)
+
+		c := NewCourseCatalogUseCase(repository)
+		courseID := uuid.NewString()
+
+		// test
+		err := c.SaveCourse(ctx, identities.SaveCourseInput{ID: courseID, Name: "Matemática"})
+		require.NoError(t, err)
+
+		err = c.SaveCourse(ctx, identities.SaveCourseInput{ID: courseID, Name: "Matemática Aplicada"})
+		require.NoError(t, err)
+
+		// assert
+		got, err := repository.GetCourse(ctx, courseID)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Course{ID: courseID, Name: "Matemática Aplicada", Status: entities.CourseStatusOpen}, got)
+	})
+
+	t.Run("should keep course closed when updated", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		repository := postgres.NewCoursesRepository(db)
+
+		c := NewCourseCatalogUseCase(repository)
+		courseID := uuid.NewString()
+		require.NoError(t, c.SaveCourse(ctx, identities.SaveCourseInput{ID: courseID, Name: "Química"}))
+		require.NoError(t, c.CloseCourse(ctx, courseID))
+
+		// test
+		err := c.SaveCourse(ctx, identities.SaveCourseInput{ID: courseID, Name: "Química Orgânica"})
+		require.NoError(t, err)
+
+		// assert
+		got, err := repository.GetCourse(ctx, courseID)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.Course{ID: courseID, Name: "Química Orgânica", Status: entities.CourseStatusClosed}, got)
+	})
+
+	t.Run("should fail due to invalid course id", func(t *testing.T) {
+		t.Parallel()
+
+		c := NewCourseCatalogUseCase(nil)
+
+		err := c.SaveCourse(context.Background(), identities.SaveCourseInput{ID: "invalid", Name: "Matemática"})
+
+		assert.ErrorIs(t, err, identities.ErrInvalidCourseID)
+	})
+}
+
+func TestCourseCatalogUseCase_CloseCourse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should close course", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		repository := postgres.NewCoursesRepository(db)
+
+		c := NewCourseCatalogUseCase(repository)
+		course := entities.NewCourse(uuid.NewString(), "Física")
+		require.NoError(t, repository.SaveCourse(ctx, course))
+
+		// test
+		err := c.CloseCourse(ctx, course.ID)
+
+		// assert
+		assert.NoError(t, err)
+
+		got, err := repository.GetCourse(ctx, course.ID)
+		assert.NoError(t, err)
+		assert.True(t, got.IsClosed())
+	})
+
+	tt := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{
+			name:    "should fail due to invalid course id",
+			input:   "invalid",
+			wantErr: identities.ErrInvalidCourseID,
+		},
+		{
+			name:    "should fail because course is not in the catalog",
+			input:   uuid.NewString(),
+			wantErr: identities.ErrCourseNotFound,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			db := pgfixtures.NewDB(t)
+			c := NewCourseCatalogUseCase(postgres.NewCoursesRepository(db))
+
+			err := c.CloseCourse(ctx, tc.input)
+
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
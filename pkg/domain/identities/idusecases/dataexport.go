@@ -0,0 +1,114 @@
+package idusecases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type DataExportUseCase struct {
+	studentsRepository identities.StudentDataRepository
+	coursesRepository  identities.CourseListerRepository
+	sessionsRepository identities.SessionsRepository
+	loginsRepository   identities.LoginHistoryRepository
+	eventsReader       identities.StudentEventsReader
+	auditRepository    identities.AuditRepository
+	tracer             trace.Tracer
+}
+
+func NewDataExportUseCase(
+	studentsRepository identities.StudentDataRepository,
+	coursesRepository identities.CourseListerRepository,
+	sessionsRepository identities.SessionsRepository,
+	loginsRepository identities.LoginHistoryRepository,
+	eventsReader identities.StudentEventsReader,
+	auditRepository identities.AuditRepository,
+) DataExportUseCase {
+	return DataExportUseCase{
+		studentsRepository: studentsRepository,
+		coursesRepository:  coursesRepository,
+		sessionsRepository: sessionsRepository,
+		loginsRepository:   loginsRepository,
+		eventsReader:       eventsReader,
+		auditRepository:    auditRepository,
+		tracer:             otel.Tracer(tracerName),
+	}
+}
+
+// ExportStudentData gathers every personal data kept about the student. The export is only handed over once
+// it is recorded in the audit log, failed exports are recorded too.
+func (u DataExportUseCase) ExportStudentData(ctx context.Context, input identities.ExportStudentDataInput) (identities.StudentDataExport, error) {
+	ctx, span := u.tracer.Start(ctx, "DataExportUseCase.ExportStudentData")
+	defer span.End()
+
+	if input.StudentID == "" {
+		span.RecordError(identities.ErrEmptyStudentID)
+		return identities.StudentDataExport{}, identities.ErrEmptyStudentID
+	}
+
+	export, err := u.gatherStudentData(ctx, input.StudentID)
+	if err != nil {
+		span.RecordError(err)
+		// the failure is what gets reported, not failing to audit it
+		auditErr := u.auditRepository.RecordAudit(ctx, entities.NewAuditEntry(entities.AuditActionStudentDataExportFailed, input.StudentID, input.Actor))
+		if auditErr != nil {
+			span.RecordError(auditErr)
+		}
+		return identities.StudentDataExport{}, err
+	}
+
+	err = u.auditRepository.RecordAudit(ctx, entities.NewAuditEntry(entities.AuditActionStudentDataExported, input.StudentID, input.Actor))
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentDataExport{}, err
+	}
+
+	return export, nil
+}
+
+func (u DataExportUseCase) gatherStudentData(ctx context.Context, studentID string) (identities.StudentDataExport, error) {
+	student, err := u.studentsRepository.GetStudent(ctx, studentID)
+	if err != nil {
+		return identities.StudentDataExport{}, err
+	}
+
+	export := identities.StudentDataExport{
+		GeneratedAt: time.Now().UTC(),
+		Student:     student,
+	}
+
+	if student.CourseID != "" {
+		course, err := u.coursesRepository.GetCourse(ctx, student.CourseID)
+		switch {
+		case errors.Is(err, identities.ErrCourseNotFound):
+			// courses removed from the catalog are still part of the student data
+			course = entities.Course{ID: student.CourseID}
+		case err != nil:
+			return identities.StudentDataExport{}, err
+		}
+		export.Courses = append(export.Courses, course)
+	}
+
+	export.Sessions, err = u.sessionsRepository.ListStudentSessions(ctx, student.ID)
+	if err != nil {
+		return identities.StudentDataExport{}, err
+	}
+
+	export.Logins, err = u.loginsRepository.ListLogins(ctx, student.ID)
+	if err != nil {
+		return identities.StudentDataExport{}, err
+	}
+
+	export.Events, err = u.eventsReader.ReadStudentEvents(ctx, student.ID)
+	if err != nil {
+		return identities.StudentDataExport{}, err
+	}
+
+	return export, nil
+}
@@ -0,0 +1,130 @@
+package idusecases
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/redis"
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+func TestDataExportUseCase_ExportStudentData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should export every student data and audit it", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		coursesRepository := postgres.NewCoursesRepository(db)
+		loginsRepository := postgres.NewLoginHistoryRepository(db)
+		tokensRepository := redis.NewTokensRepository(rfixtures.NewDB(t))
+		keysRepository := postgres.NewStudentKeysRepository(db)
+		studentsProducer := kafka.NewStudentsProducer(kafka.NewProducer(kfixtures.NewKafkaClient(t), kafka.JSONSerializer{}), keysRepository)
+
+		course := entities.NewCourse(uuid.NewString(), "Ciência da Computação")
+		require.NoError(t, coursesRepository.SaveCourse(ctx, course))
+
+		register := NewRegisterUseCase(studentsRepository, coursesRepository, studentsProducer)
+
+		// the student id is unique to the test, since the students topic is shared by the tests
+		studentID := strconv.FormatInt(time.Now().UnixNano(), 10)
+		input := identities.RegisterStudentInput{
+			ID:        studentID,
+			Name:      "Pedro Lopes",
+			Secret:    "secret_password",
+			CPF:       "52998224725",
+			Email:     "plopes@ol.com",
+			BirthDate: "1994-03-19",
+			CourseID:  course.ID,
+		}
+		_, err := register.RegisterStudent(ctx, input)
+		require.NoError(t, err)
+
+		_, err = NewStudentStatusUseCase(studentsRepository, tokensRepository, studentsProducer).
+			ChangeStudentStatus(ctx, identities.ChangeStudentStatusInput{StudentID: studentID, Status: "active"})
+		require.NoError(t, err)
+
+		auth := NewStudentJWTAuthenticator(studentsRepository, tokensRepository, loginsRepository, newActivityProducer(), validConfig)
+		_, err = auth.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{StudentID: studentID, StudentSecret: "wrong"})
+		require.Error(t, err)
+		token, err := auth.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{StudentID: studentID, StudentSecret: input.Secret})
+		require.NoError(t, err)
+
+		eventsReader, err := kafka.NewStudentEventsReader(keysRepository, nil, kgo.SeedBrokers("localhost:9094"))
+		require.NoError(t, err)
+
+		u := NewDataExportUseCase(
+			studentsRepository,
+			coursesRepository,
+			tokensRepository,
+			loginsRepository,
+			eventsReader,
+			postgres.NewAuditRepository(db),
+		)
+
+		// test
+		got, err := u.ExportStudentData(ctx, identities.ExportStudentDataInput{StudentID: studentID, Actor: "cli:test"})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, studentID, got.Student.ID)
+		assert.Empty(t, got.Student.Secret)
+		assert.Equal(t, []entities.Course{course}, got.Courses)
+		require.Len(t, got.Sessions, 1)
+		assert.Equal(t, token.ID, got.Sessions[0].ID)
+		require.Len(t, got.Logins, 2)
+		assert.True(t, got.Logins[0].Succeeded)
+		assert.Equal(t, entities.LoginFailureInvalidCredentials, got.Logins[1].FailureReason)
+
+		types := make([]string, 0, len(got.Events))
+		for _, evt := range got.Events {
+			types = append(types, evt.Type)
+		}
+		assert.Equal(t, []string{"student_registered", "student_status_changed"}, types)
+		assert.Contains(t, string(got.Events[0].Payload), `"name":"Pedro Lopes"`)
+
+		var audited int
+		err = db.QueryRow(ctx, `SELECT count(*) FROM audit_log WHERE student_id=$1 AND actor=$2 AND action=$3`,
+			studentID, "cli:test", entities.AuditActionStudentDataExported).Scan(&audited)
+		require.NoError(t, err)
+		assert.Equal(t, 1, audited)
+	})
+
+	t.Run("should fail because student does not exist and audit it", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		db := pgfixtures.NewDB(t)
+		u := NewDataExportUseCase(postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t)), nil, nil, nil, nil, postgres.NewAuditRepository(db))
+
+		// test
+		_, err := u.ExportStudentData(ctx, identities.ExportStudentDataInput{StudentID: "000000000", Actor: "cli:test"})
+
+		// assert
+		assert.ErrorIs(t, err, identities.ErrStudentNotFound)
+
+		var audited int
+		err = db.QueryRow(ctx, `SELECT count(*) FROM audit_log WHERE student_id=$1 AND actor=$2 AND action=$3`,
+			"000000000", "cli:test", entities.AuditActionStudentDataExportFailed).Scan(&audited)
+		require.NoError(t, err)
+		assert.Equal(t, 1, audited)
+	})
+}
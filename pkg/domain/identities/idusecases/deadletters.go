@@ -0,0 +1,205 @@
+package idusecases
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type DeadLettersUseCase struct {
+	repository      identities.DeadLettersRepository
+	publisher       identities.DeadLetterPublisher
+	auditRepository identities.AuditRepository
+	tracer          trace.Tracer
+}
+
+func NewDeadLettersUseCase(
+	repository identities.DeadLettersRepository,
+	publisher identities.DeadLetterPublisher,
+	auditRepository identities.AuditRepository,
+) DeadLettersUseCase {
+	return DeadLettersUseCase{
+		repository:      repository,
+		publisher:       publisher,
+		auditRepository: auditRepository,
+		tracer:          otel.Tracer(tracerName),
+	}
+}
+
+func (u DeadLettersUseCase) ListDeadLetters(ctx context.Context, input identities.ListDeadLettersInput) (identities.DeadLettersPage, error) {
+	ctx, span := u.tracer.Start(ctx, "DeadLettersUseCase.ListDeadLetters")
+	defer span.End()
+
+	filter, err := newDeadLettersFilter(input)
+	if err != nil {
+		span.RecordError(err)
+		return identities.DeadLettersPage{}, err
+	}
+
+	// one dead letter more than the limit is fetched to know if there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	deadLetters, err := u.repository.ListDeadLetters(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		return identities.DeadLettersPage{}, err
+	}
+
+	var page identities.DeadLettersPage
+	if len(deadLetters) > limit {
+		deadLetters = deadLetters[:limit]
+		last := deadLetters[limit-1]
+		page.NextCursor = encodeCursor(identities.StudentsCursor{CreatedAt: last.FailedAt, ID: last.ID})
+	}
+	page.DeadLetters = deadLetters
+
+	return page, nil
+}
+
+// newDeadLettersFilter reports the invalid parameters together in a ValidationError, as the students search
+// does. Dead letter cursors share the format of the students ones, with the failure date as the creation one.
+func newDeadLettersFilter(input identities.ListDeadLettersInput) (identities.DeadLettersFilter, error) {
+	var validationErr entities.ValidationError
+
+	filter := identities.DeadLettersFilter{
+		Topic: input.Topic,
+		Limit: input.Limit,
+	}
+
+	switch {
+	case input.Limit == 0:
+		filter.Limit = defaultPageLimit
+	case input.Limit < 0 || input.Limit > maxPageLimit:
+		validationErr.Add("limit", fmt.Errorf("%w: must be between 1 and %d", identities.ErrInvalidPageLimit, maxPageLimit))
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeCursor(input.Cursor)
+		if err != nil {
+			validationErr.Add("cursor", err)
+		}
+		filter.After = &identities.DeadLettersCursor{FailedAt: cursor.CreatedAt, ID: cursor.ID}
+	}
+
+	if err := validationErr.Err(); err != nil {
+		return identities.DeadLettersFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// RetryDeadLetter publishes the dead letter as it was stored. It is deleted once published, a failure is
+// recorded in it and it stays in the store. Only the oldest dead letter of a key is published, and not after a
+// later event of the key, so the events of each key are kept in order.
+func (u DeadLettersUseCase) RetryDeadLetter(ctx context.Context, input identities.DeadLetterInput) error {
+	ctx, span := u.tracer.Start(ctx, "DeadLettersUseCase.RetryDeadLetter")
+	defer span.End()
+
+	deadLetter, err := u.getDeadLetter(ctx, input.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	span.SetAttributes(attribute.String("dead_letter.topic", deadLetter.Topic), attribute.Int("dead_letter.attempts", deadLetter.Attempts))
+
+	err = u.checkOrder(ctx, deadLetter)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = u.publisher.PublishDeadLetter(ctx, deadLetter)
+	if err != nil {
+		span.RecordError(err)
+
+		deadLetter.Attempts++
+		deadLetter.Error = err.Error()
+		if saveErr := u.repository.SaveDeadLetter(ctx, deadLetter); saveErr != nil {
+			span.RecordError(saveErr)
+		}
+		return fmt.Errorf("%w: %s", identities.ErrDeadLetterNotPublished, err)
+	}
+
+	err = u.repository.DeleteDeadLetter(ctx, deadLetter.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = u.auditRepository.RecordAudit(ctx, entities.NewAuditEntry(entities.AuditActionDeadLetterRetried, deadLetter.Key, input.Actor))
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// checkOrder refuses the dead letter behind an older one of its key, or older than an event of its key that was
+// already published, as either would be published out of order.
+func (u DeadLettersUseCase) checkOrder(ctx context.Context, deadLetter entities.DeadLetter) error {
+	if deadLetter.Key == "" {
+		return nil
+	}
+
+	oldest, err := u.repository.ListDeadLetters(ctx, identities.DeadLettersFilter{
+		Topic: deadLetter.Topic,
+		Key:   deadLetter.Key,
+		Limit: 1,
+	})
+	if err != nil {
+		return err
+	}
+	if len(oldest) > 0 && oldest[0].ID != deadLetter.ID {
+		return fmt.Errorf("%w: dead letter %s of the same key must be retried or discarded first", identities.ErrDeadLetterOutOfOrder, oldest[0].ID)
+	}
+
+	lastPublished, err := u.repository.GetLastPublishedEvent(ctx, deadLetter.Topic, deadLetter.Key)
+	if err != nil {
+		return err
+	}
+	if lastPublished.After(deadLetter.EventTime) {
+		return fmt.Errorf("%w: a later event of the same key was published", identities.ErrDeadLetterOutOfOrder)
+	}
+
+	return nil
+}
+
+func (u DeadLettersUseCase) DiscardDeadLetter(ctx context.Context, input identities.DeadLetterInput) error {
+	ctx, span := u.tracer.Start(ctx, "DeadLettersUseCase.DiscardDeadLetter")
+	defer span.End()
+
+	deadLetter, err := u.getDeadLetter(ctx, input.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = u.repository.DeleteDeadLetter(ctx, deadLetter.ID)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	err = u.auditRepository.RecordAudit(ctx, entities.NewAuditEntry(entities.AuditActionDeadLetterDiscarded, deadLetter.Key, input.Actor))
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (u DeadLettersUseCase) getDeadLetter(ctx context.Context, id string) (entities.DeadLetter, error) {
+	if id == "" {
+		return entities.DeadLetter{}, identities.ErrEmptyDeadLetterID
+	}
+	return u.repository.GetDeadLetter(ctx, id)
+}
@@ -0,0 +1,89 @@
+package idusecases
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type StudentErasureUseCase struct {
+	repository         identities.StudentErasureRepository
+	sessionsRepository identities.SessionsRepository
+	eventProducer      identities.StudentsProducer
+	activityProducer   identities.AuthenticationProducer
+	auditRepository    identities.AuditRepository
+	tracer             trace.Tracer
+}
+
+func NewStudentErasureUseCase(
+	repository identities.StudentErasureRepository,
+	sessionsRepository identities.SessionsRepository,
+	eventProducer identities.StudentsProducer,
+	activityProducer identities.AuthenticationProducer,
+	auditRepository identities.AuditRepository,
+) StudentErasureUseCase {
+	return StudentErasureUseCase{
+		repository:         repository,
+		sessionsRepository: sessionsRepository,
+		eventProducer:      eventProducer,
+		activityProducer:   activityProducer,
+		auditRepository:    auditRepository,
+		tracer:             otel.Tracer(tracerName),
+	}
+}
+
+// EraseStudent pseudonymizes the student, destroys the key its published events were encrypted with, revokes
+// its sessions and publishes a tombstone so consumers drop their copies. Every step can be run again, so a
+// failed erasure is completed by retrying it.
+func (u StudentErasureUseCase) EraseStudent(ctx context.Context, input identities.EraseStudentInput) (identities.StudentErasureReport, error) {
+	ctx, span := u.tracer.Start(ctx, "StudentErasureUseCase.EraseStudent")
+	defer span.End()
+
+	if input.StudentID == "" {
+		span.RecordError(identities.ErrEmptyStudentID)
+		return identities.StudentErasureReport{}, identities.ErrEmptyStudentID
+	}
+
+	erasure, err := u.repository.EraseStudent(ctx, entities.NewStudentErasure(input.StudentID))
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentErasureReport{}, err
+	}
+
+	revoked, err := u.sessionsRepository.RevokeStudentSessions(ctx, erasure.StudentID)
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentErasureReport{}, err
+	}
+	span.SetAttributes(attribute.Int("erasure.revoked_sessions", revoked))
+
+	if revoked > 0 {
+		// the activity is only for analytics and notifications, it must not leave the erasure incomplete
+		activity := entities.NewAuthenticationActivity(erasure.StudentID, entities.AuthOutcomeStudentErased, entities.AuthenticationClient{})
+		if err = u.activityProducer.ProduceTokenRevoked(ctx, activity); err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	err = u.eventProducer.ProduceStudentErased(ctx, erasure)
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentErasureReport{}, err
+	}
+
+	err = u.auditRepository.RecordAudit(ctx, entities.NewAuditEntry(entities.AuditActionStudentErased, erasure.StudentID, input.Actor))
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentErasureReport{}, err
+	}
+
+	return identities.StudentErasureReport{
+		Erasure:         erasure,
+		RevokedSessions: revoked,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package idusecases
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/redis"
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+func TestStudentErasureUseCase_EraseStudent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should erase the student and leave its events unreadable", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		coursesRepository := postgres.NewCoursesRepository(db)
+		loginsRepository := postgres.NewLoginHistoryRepository(db)
+		keysRepository := postgres.NewStudentKeysRepository(db)
+		tokensRepository := redis.NewTokensRepository(rfixtures.NewDB(t))
+		studentsProducer := kafka.NewStudentsProducer(kafka.NewProducer(kfixtures.NewKafkaClient(t), kafka.JSONSerializer{}), keysRepository)
+
+		course := entities.NewCourse(uuid.NewString(), "Ciência da Computação")
+		require.NoError(t, coursesRepository.SaveCourse(ctx, course))
+
+		// the student id is unique to the test, since the students topic is shared by the tests
+		studentID := strconv.FormatInt(time.Now().UnixNano(), 10)
+		input := identities.RegisterStudentInput{
+			ID:        studentID,
+			Name:      "Pedro Lopes",
+			Secret:    "secret_password",
+			CPF:       "52998224725",
+			Email:     "plopes@ol.com",
+			BirthDate: "1994-03-19",
+			CourseID:  course.ID,
+		}
+		_, err := NewRegisterUseCase(studentsRepository, coursesRepository, studentsProducer).RegisterStudent(ctx, input)
+		require.NoError(t, err)
+
+		_, err = NewStudentStatusUseCase(studentsRepository, tokensRepository, studentsProducer).
+			ChangeStudentStatus(ctx, identities.ChangeStudentStatusInput{StudentID: studentID, Status: "active"})
+		require.NoError(t, err)
+
+		auth := NewStudentJWTAuthenticator(studentsRepository, tokensRepository, loginsRepository, newActivityProducer(), validConfig)
+		token, err := auth.AuthenticateStudent(ctx, identities.AuthenticateStudentInput{StudentID: studentID, StudentSecret: input.Secret})
+		require.NoError(t, err)
+
+		u := NewStudentErasureUseCase(studentsRepository, tokensRepository, studentsProducer, newActivityProducer(), postgres.NewAuditRepository(db))
+
+		// test
+		got, err := u.EraseStudent(ctx, identities.EraseStudentInput{StudentID: studentID, Actor: "cli:test"})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, studentID, got.Erasure.StudentID)
+		assert.Equal(t, 1, got.RevokedSessions)
+
+		student, err := studentsRepository.GetStudent(ctx, studentID)
+		require.NoError(t, err)
+		assert.Empty(t, student.Name)
+		assert.Empty(t, student.CPF)
+		assert.Empty(t, student.Email)
+		assert.Equal(t, entities.StudentStatusCancelled, student.Status)
+		assert.WithinDuration(t, got.Erasure.ErasedAt, student.ErasedAt, time.Millisecond)
+
+		_, err = auth.VerifyAuth(ctx, token.Hash, "")
+		assert.ErrorIs(t, err, identities.ErrTokenNotEmitted)
+
+		logins, err := loginsRepository.ListLogins(ctx, studentID)
+		require.NoError(t, err)
+		assert.Empty(t, logins)
+
+		_, err = keysRepository.GetStudentKey(ctx, studentID)
+		assert.ErrorIs(t, err, identities.ErrStudentKeyNotFound)
+
+		eventsReader, err := kafka.NewStudentEventsReader(keysRepository, nil, kgo.SeedBrokers("localhost:9094"))
+		require.NoError(t, err)
+		events, err := eventsReader.ReadStudentEvents(ctx, studentID)
+		require.NoError(t, err)
+		types := make([]string, 0, len(events))
+		for _, evt := range events {
+			types = append(types, evt.Type)
+		}
+		assert.Equal(t, []string{"student_registered", "student_status_changed", "student_erased"}, types)
+		assert.NotContains(t, string(events[0].Payload), "Pedro Lopes")
+
+		var audited int
+		err = db.QueryRow(ctx, `SELECT count(*) FROM audit_log WHERE student_id=$1 AND actor=$2 AND action=$3`,
+			studentID, "cli:test", entities.AuditActionStudentErased).Scan(&audited)
+		require.NoError(t, err)
+		assert.Equal(t, 1, audited)
+
+		// the same CPF and email can be registered again once erased
+		input.ID = strconv.FormatInt(time.Now().UnixNano(), 10)
+		_, err = NewRegisterUseCase(studentsRepository, coursesRepository, studentsProducer).RegisterStudent(ctx, input)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should keep the first erasure when erased again", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+
+		db := pgfixtures.NewDB(t)
+		studentsRepository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+		student := newStoredStudent(t, studentsRepository, "test_password", entities.StudentStatusActive)
+		studentsProducer := kafka.NewStudentsProducer(kafka.NewProducer(kfixtures.NewKafkaClient(t), kafka.JSONSerializer{}), postgres.NewStudentKeysRepository(db))
+
+		u := NewStudentErasureUseCase(studentsRepository, redis.NewTokensRepository(rfixtures.NewDB(t)), studentsProducer, newActivityProducer(), postgres.NewAuditRepository(db))
+		first, err := u.EraseStudent(ctx, identities.EraseStudentInput{StudentID: student.ID, Actor: "cli:test"})
+		require.NoError(t, err)
+
+		// test
+		got, err := u.EraseStudent(ctx, identities.EraseStudentInput{StudentID: student.ID, Actor: "cli:test"})
+
+		// assert
+		require.NoError(t, err)
+		assert.WithinDuration(t, first.Erasure.ErasedAt, got.Erasure.ErasedAt, time.Millisecond)
+		assert.Zero(t, got.RevokedSessions)
+	})
+
+	t.Run("should fail because student does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		db := pgfixtures.NewDB(t)
+		u := NewStudentErasureUseCase(postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t)), nil, nil, nil, postgres.NewAuditRepository(db))
+
+		// test
+		_, err := u.EraseStudent(context.Background(), identities.EraseStudentInput{StudentID: "000000000", Actor: "cli:test"})
+
+		// assert
+		assert.ErrorIs(t, err, identities.ErrStudentNotFound)
+	})
+}
@@ -0,0 +1,315 @@
+package idusecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const defaultImportBatchSize = 500
+
+var nonDigits = regexp.MustCompile(`\D`)
+
+type ImportConfig interface {
+	ImportBatchSize() int
+}
+
+type StudentsImportUseCase struct {
+	repository        identities.StudentsImporterRepository
+	coursesRepository identities.CourseListerRepository
+	eventProducer     identities.StudentsProducer
+	batchSize         int
+	tracer            trace.Tracer
+}
+
+func NewStudentsImportUseCase(
+	repository identities.StudentsImporterRepository,
+	coursesRepository identities.CourseListerRepository,
+	eventProducer identities.StudentsProducer,
+	config ImportConfig,
+) StudentsImportUseCase {
+	batchSize := config.ImportBatchSize()
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	return StudentsImportUseCase{
+		repository:        repository,
+		coursesRepository: coursesRepository,
+		eventProducer:     eventProducer,
+		batchSize:         batchSize,
+		tracer:            otel.Tracer(tracerName),
+	}
+}
+
+// importCandidate is a valid row waiting for its batch to be stored.
+type importCandidate struct {
+	result  int
+	student entities.Student
+	secret  string
+}
+
+// importState holds what is shared between the batches of a single import.
+type importState struct {
+	report  identities.ImportReport
+	courses map[string]error
+	seen    map[string]int
+}
+
+// ImportStudents validates every row of the file the same way a registration does, rows are then stored in
+// batches. On a dry run nothing is stored nor published, the report tells which rows would be imported.
+func (u StudentsImportUseCase) ImportStudents(ctx context.Context, input identities.ImportStudentsInput) (identities.ImportReport, error) {
+	ctx, span := u.tracer.Start(ctx, "StudentsImportUseCase.ImportStudents")
+	defer span.End()
+
+	state := importState{
+		report:  identities.ImportReport{DryRun: input.DryRun},
+		courses: make(map[string]error),
+		seen:    make(map[string]int),
+	}
+
+	batch := make([]importCandidate, 0, u.batchSize)
+	for {
+		row, err := input.Rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && !errors.Is(err, identities.ErrMalformedImportRow) {
+			span.RecordError(err)
+			return identities.ImportReport{}, err
+		}
+
+		state.report.Rows = append(state.report.Rows, identities.ImportRowResult{Line: row.Line, StudentID: row.Input.ID})
+		result := len(state.report.Rows) - 1
+
+		if err == nil {
+			var candidate importCandidate
+			candidate, err = u.validateRow(ctx, &state, row)
+			if err == nil {
+				candidate.result = result
+				batch = append(batch, candidate)
+			}
+		}
+		if err != nil {
+			state.report.Rows[result].Status = identities.ImportRowFailed
+			state.report.Rows[result].Err = err
+		}
+
+		if len(batch) == u.batchSize {
+			err = u.importBatch(ctx, &state, batch, input.DryRun)
+			if err != nil {
+				span.RecordError(err)
+				return identities.ImportReport{}, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	err := u.importBatch(ctx, &state, batch, input.DryRun)
+	if err != nil {
+		span.RecordError(err)
+		return identities.ImportReport{}, err
+	}
+
+	span.SetAttributes(
+		attribute.Bool("import.dry_run", input.DryRun),
+		attribute.Int("import.rows", len(state.report.Rows)),
+		attribute.Int("import.failed", state.report.Count(identities.ImportRowFailed)),
+	)
+
+	return state.report, nil
+}
+
+// validateRow runs the registration validations plus the ones that only make sense inside a file, like a
+// student being repeated in it. Unexpected errors are returned as is so the import is aborted.
+func (u StudentsImportUseCase) validateRow(ctx context.Context, state *importState, row identities.StudentImportRow) (importCandidate, error) {
+	in := row.Input
+
+	var validationErr entities.ValidationError
+	student, err := entities.ValidateStudent(in.ID, in.Secret, in.Name, in.CPF, in.Email, in.BirthDate)
+	if err != nil && !errors.As(err, &validationErr) {
+		return importCandidate{}, err
+	}
+
+	if _, uuidErr := uuid.Parse(in.CourseID); uuidErr != nil {
+		validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, uuidErr))
+	}
+
+	if err = validationErr.Err(); err != nil {
+		return importCandidate{}, err
+	}
+
+	err = u.checkCourse(ctx, state, in.CourseID)
+	if err != nil {
+		return importCandidate{}, err
+	}
+
+	keys := []string{
+		"id:" + student.ID,
+		"cpf:" + nonDigits.ReplaceAllString(student.CPF, ""),
+		"email:" + strings.ToLower(strings.TrimSpace(student.Email)),
+	}
+	for _, key := range keys {
+		if line, ok := state.seen[key]; ok {
+			return importCandidate{}, fmt.Errorf("%w: same %s as line %d", identities.ErrDuplicatedImportRow, key[:strings.Index(key, ":")], line)
+		}
+	}
+	for _, key := range keys {
+		state.seen[key] = row.Line
+	}
+
+	student.CourseID = in.CourseID
+	return importCandidate{student: student, secret: in.Secret}, nil
+}
+
+// checkCourse caches the course lookups, since most rows of a file share a handful of courses.
+func (u StudentsImportUseCase) checkCourse(ctx context.Context, state *importState, courseID string) error {
+	if err, ok := state.courses[courseID]; ok {
+		return err
+	}
+
+	var validationErr entities.ValidationError
+
+	course, err := u.coursesRepository.GetCourse(ctx, courseID)
+	switch {
+	case errors.Is(err, identities.ErrCourseNotFound):
+		validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, err))
+	case err != nil:
+		return err
+	case course.IsClosed():
+		validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, identities.ErrCourseClosed))
+	}
+
+	state.courses[courseID] = validationErr.Err()
+	return state.courses[courseID]
+}
+
+func (u StudentsImportUseCase) importBatch(ctx context.Context, state *importState, batch []importCandidate, dryRun bool) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ctx, span := u.tracer.Start(ctx, "StudentsImportUseCase.importBatch", trace.WithAttributes(attribute.Int("import.batch_size", len(batch))))
+	defer span.End()
+
+	students := make([]entities.Student, 0, len(batch))
+	for _, candidate := range batch {
+		students = append(students, candidate.student)
+	}
+
+	conflicts, err := u.repository.FindConflicts(ctx, students)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	valid := make([]importCandidate, 0, len(batch))
+	for i, candidate := range batch {
+		if conflicts[i] != nil {
+			state.report.Rows[candidate.result].Status = identities.ImportRowFailed
+			state.report.Rows[candidate.result].Err = conflicts[i]
+			continue
+		}
+		valid = append(valid, candidate)
+	}
+
+	if dryRun {
+		for _, candidate := range valid {
+			state.report.Rows[candidate.result].Status = identities.ImportRowValid
+		}
+		return nil
+	}
+
+	err = hashSecrets(valid)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	u.storeBatch(ctx, state, valid)
+
+	for _, candidate := range valid {
+		row := &state.report.Rows[candidate.result]
+		if row.Status != identities.ImportRowImported {
+			continue
+		}
+
+		err = u.eventProducer.ProduceStudentRegistered(ctx, candidate.student, candidate.student.CourseID)
+		if err != nil {
+			span.RecordError(err)
+			row.Status = identities.ImportRowEventFailed
+			row.Err = err
+		}
+	}
+
+	return nil
+}
+
+// storeBatch copies the whole batch at once. When that fails, for instance because a student was registered
+// after the conflicts check, the batch is stored row by row so only the offending rows are reported.
+func (u StudentsImportUseCase) storeBatch(ctx context.Context, state *importState, batch []importCandidate) {
+	students := make([]entities.Student, 0, len(batch))
+	for _, candidate := range batch {
+		students = append(students, candidate.student)
+	}
+
+	err := u.repository.CreateStudents(ctx, students)
+	if err == nil {
+		for _, candidate := range batch {
+			state.report.Rows[candidate.result].Status = identities.ImportRowImported
+		}
+		return
+	}
+
+	for _, candidate := range batch {
+		row := &state.report.Rows[candidate.result]
+		err = u.repository.CreateStudent(ctx, candidate.student)
+		if err != nil {
+			row.Status = identities.ImportRowFailed
+			row.Err = err
+			continue
+		}
+		row.Status = identities.ImportRowImported
+	}
+}
+
+// hashSecrets spreads the bcrypt cost of the batch over the available CPUs.
+func hashSecrets(batch []importCandidate) error {
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i := range batch {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(candidate *importCandidate) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			if err := candidate.student.SetSecret(candidate.secret); err != nil {
+				once.Do(func() { firstErr = err })
+			}
+		}(&batch[i])
+	}
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,148 @@
+package idusecases
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+)
+
+type importConfig int
+
+func (c importConfig) ImportBatchSize() int {
+	return int(c)
+}
+
+// rowsReader reads the rows from memory, a row without input is read as malformed.
+type rowsReader struct {
+	rows []identities.StudentImportRow
+}
+
+func (r *rowsReader) Next() (identities.StudentImportRow, error) {
+	if len(r.rows) == 0 {
+		return identities.StudentImportRow{}, io.EOF
+	}
+
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	if row.Input == (identities.RegisterStudentInput{}) {
+		return row, identities.ErrMalformedImportRow
+	}
+	return row, nil
+}
+
+func TestStudentsImportUseCase_ImportStudents(t *testing.T) {
+	t.Parallel()
+
+	openCourse := entities.NewCourse(uuid.NewString(), "Ciência da Computação")
+	closedCourse := entities.Course{ID: uuid.NewString(), Name: "Engenharia de Produção", Status: entities.CourseStatusClosed}
+
+	pedro := identities.RegisterStudentInput{
+		ID:        "201320509911",
+		Name:      "Pedro Lopes",
+		Secret:    "secret_password",
+		CPF:       "52998224725",
+		Email:     "plopes@ol.com",
+		BirthDate: "1994-03-19",
+		CourseID:  openCourse.ID,
+	}
+	maria := identities.RegisterStudentInput{
+		ID:        "201320509912",
+		Name:      "Maria Souza",
+		Secret:    "secret_password",
+		CPF:       "39053344705",
+		Email:     "msouza@ol.com",
+		BirthDate: "1995-07-02",
+		CourseID:  openCourse.ID,
+	}
+
+	with := func(input identities.RegisterStudentInput, change func(*identities.RegisterStudentInput)) identities.RegisterStudentInput {
+		change(&input)
+		return input
+	}
+
+	rows := func() []identities.StudentImportRow {
+		return []identities.StudentImportRow{
+			{Line: 2, Input: pedro},
+			{Line: 3, Input: with(pedro, func(i *identities.RegisterStudentInput) { i.ID = "201320509913"; i.CPF = "123" })},
+			{Line: 4, Input: with(maria, func(i *identities.RegisterStudentInput) { i.Email = "jdoe@ol.com" })},
+			{Line: 5, Input: with(maria, func(i *identities.RegisterStudentInput) { i.ID = pedro.ID })},
+			{Line: 6},
+			{Line: 7, Input: with(maria, func(i *identities.RegisterStudentInput) { i.CourseID = closedCourse.ID })},
+		}
+	}
+
+	tt := []struct {
+		name       string
+		dryRun     bool
+		wantStatus identities.ImportRowStatus
+	}{
+		{
+			name:       "should only validate rows on dry run",
+			dryRun:     true,
+			wantStatus: identities.ImportRowValid,
+		},
+		{
+			name:       "should import valid rows",
+			wantStatus: identities.ImportRowImported,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// prepare
+			ctx := context.Background()
+
+			dbConn := pgfixtures.NewDB(t)
+			repository := postgres.NewStudentsRepository(dbConn, pgfixtures.NewKeyring(t))
+			coursesRepository := postgres.NewCoursesRepository(dbConn)
+			for _, course := range []entities.Course{openCourse, closedCourse} {
+				require.NoError(t, coursesRepository.SaveCourse(ctx, course))
+			}
+			newStoredStudent(t, repository, "secret_password", entities.StudentStatusActive)
+
+			kClient := kfixtures.NewKafkaClient(t)
+			eventsProducer := kafka.NewStudentsProducer(kafka.NewProducer(kClient, kafka.JSONSerializer{}), postgres.NewStudentKeysRepository(dbConn))
+
+			u := NewStudentsImportUseCase(repository, coursesRepository, eventsProducer, importConfig(2))
+
+			// test
+			got, err := u.ImportStudents(ctx, identities.ImportStudentsInput{
+				Rows:   &rowsReader{rows: rows()},
+				DryRun: tc.dryRun,
+			})
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.dryRun, got.DryRun)
+			require.Len(t, got.Rows, 6)
+
+			assert.Equal(t, tc.wantStatus, got.Rows[0].Status)
+			assert.NoError(t, got.Rows[0].Err)
+			assert.ErrorIs(t, got.Rows[1].Err, entities.ErrInvalidCPF)
+			assert.ErrorIs(t, got.Rows[2].Err, identities.ErrEmailAlreadyRegistered)
+			assert.ErrorIs(t, got.Rows[3].Err, identities.ErrDuplicatedImportRow)
+			assert.ErrorIs(t, got.Rows[4].Err, identities.ErrMalformedImportRow)
+			assert.ErrorIs(t, got.Rows[5].Err, identities.ErrInvalidCourseID)
+			assert.Equal(t, 5, got.Count(identities.ImportRowFailed))
+
+			_, err = repository.GetStudentStatus(ctx, pedro.ID)
+			if tc.dryRun {
+				assert.ErrorIs(t, err, identities.ErrStudentNotFound)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
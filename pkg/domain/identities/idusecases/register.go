@@ -2,6 +2,7 @@ package idusecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -13,16 +14,22 @@ import (
 )
 
 type RegisterUseCase struct {
-	repository    identities.StudentsRegistererRepository
-	eventProducer identities.StudentsProducer
-	tracer        trace.Tracer
+	repository        identities.StudentsRegistererRepository
+	coursesRepository identities.CourseListerRepository
+	eventProducer     identities.StudentsProducer
+	tracer            trace.Tracer
 }
 
-func NewRegisterUseCase(repository identities.StudentsRegistererRepository, eventProducer identities.StudentsProducer) RegisterUseCase {
+func NewRegisterUseCase(
+	repository identities.StudentsRegistererRepository,
+	coursesRepository identities.CourseListerRepository,
+	eventProducer identities.StudentsProducer,
+) RegisterUseCase {
 	return RegisterUseCase{
-		repository:    repository,
-		eventProducer: eventProducer,
-		tracer:        otel.Tracer(tracerName),
+		repository:        repository,
+		coursesRepository: coursesRepository,
+		eventProducer:     eventProducer,
+		tracer:            otel.Tracer(tracerName),
 	}
 }
 
@@ -30,18 +37,29 @@ func (r RegisterUseCase) RegisterStudent(ctx context.Context, input identities.R
 	ctx, span := r.tracer.Start(ctx, "RegisterUseCase.RegisterStudent")
 	defer span.End()
 
-	_, err := uuid.Parse(input.CourseID)
-	if err != nil {
-		err = fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, err)
+	// the course id is validated along with the student fields, so every invalid field is reported at once
+	var validationErr entities.ValidationError
+	student, err := entities.NewStudent(input.ID, input.Secret, input.Name, input.CPF, input.Email, input.BirthDate)
+	if err != nil && !errors.As(err, &validationErr) {
 		span.RecordError(err)
 		return "", err
 	}
 
-	student, err := entities.NewStudent(input.ID, input.Secret, input.Name, input.CPF, input.Email, input.BirthDate)
+	if _, uuidErr := uuid.Parse(input.CourseID); uuidErr != nil {
+		validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, uuidErr))
+	}
+
+	if err = validationErr.Err(); err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+
+	err = r.checkCourse(ctx, input.CourseID)
 	if err != nil {
 		span.RecordError(err)
 		return "", err
 	}
+	student.CourseID = input.CourseID
 
 	err = r.repository.CreateStudent(ctx, student)
 	if err != nil {
@@ -57,3 +75,20 @@ func (r RegisterUseCase) RegisterStudent(ctx context.Context, input identities.R
 
 	return student.ID, nil
 }
+
+// checkCourse ensures the course exists in the local catalog and still accepts registrations.
+func (r RegisterUseCase) checkCourse(ctx context.Context, courseID string) error {
+	var validationErr entities.ValidationError
+
+	course, err := r.coursesRepository.GetCourse(ctx, courseID)
+	switch {
+	case errors.Is(err, identities.ErrCourseNotFound):
+		validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, err))
+	case err != nil:
+		return err
+	case course.IsClosed():
+		validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, identities.ErrCourseClosed))
+	}
+
+	return validationErr.Err()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
 	"github.com/tccav/identity-service/pkg/domain/identities"
@@ -18,6 +19,9 @@ import (
 func TestRegisterUseCase_RegisterStudent(t *testing.T) {
 	t.Parallel()
 
+	openCourse := entities.NewCourse(uuid.NewString(), "Ciência da Computação")
+	closedCourse := entities.Course{ID: uuid.NewString(), Name: "Engenharia de Produção", Status: entities.CourseStatusClosed}
+
 	validInput := identities.RegisterStudentInput{
 		ID:        "201320509911",
 		Name:      "Pedro Lopes",
@@ -25,7 +29,12 @@ func TestRegisterUseCase_RegisterStudent(t *testing.T) {
 		CPF:       "11111111030",
 		Email:     "plopes@ol.com",
 		BirthDate: "1994-03-19",
-		CourseID:  uuid.NewString(),
+		CourseID:  openCourse.ID,
+	}
+
+	withCourse := func(input identities.RegisterStudentInput, courseID string) identities.RegisterStudentInput {
+		input.CourseID = courseID
+		return input
 	}
 
 	tt := []struct {
@@ -39,6 +48,16 @@ func TestRegisterUseCase_RegisterStudent(t *testing.T) {
 			input: validInput,
 			want:  validInput.ID,
 		},
+		{
+			name:    "should fail because course is not in the catalog",
+			input:   withCourse(validInput, uuid.NewString()),
+			wantErr: identities.ErrInvalidCourseID,
+		},
+		{
+			name:    "should fail because course is closed",
+			input:   withCourse(validInput, closedCourse.ID),
+			wantErr: identities.ErrInvalidCourseID,
+		},
 		{
 			name:    "should fail due to invalid country id",
 			input:   identities.RegisterStudentInput{CourseID: "invalid"},
@@ -75,6 +94,33 @@ func TestRegisterUseCase_RegisterStudent(t *testing.T) {
 			wantErr: entities.ErrInvalidBirthDate,
 		},
 	}
+	t.Run("should report every invalid field at once", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRegisterUseCase(nil, nil, nil)
+
+		got, err := r.RegisterStudent(context.Background(), identities.RegisterStudentInput{
+			ID:        "1as",
+			CPF:       "111.111.110-30",
+			Email:     "ol.com",
+			BirthDate: "19940319",
+			CourseID:  "657970",
+		})
+
+		assert.Empty(t, got)
+
+		var validationErr entities.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+
+		fields := make([]string, 0, len(validationErr.Fields))
+		for _, f := range validationErr.Fields {
+			fields = append(fields, f.Field)
+		}
+		assert.Equal(t, []string{"id", "name", "secret", "cpf", "email", "birth_date", "course_id"}, fields)
+		assert.ErrorIs(t, err, entities.ErrInvalidCPF)
+		assert.ErrorIs(t, err, identities.ErrInvalidCourseID)
+	})
+
 	for _, testCase := range tt {
 		tc := testCase
 		t.Run(tc.name, func(t *testing.T) {
@@ -83,13 +129,17 @@ func TestRegisterUseCase_RegisterStudent(t *testing.T) {
 			ctx := context.Background()
 
 			dbConn := pgfixtures.NewDB(t)
-			repository := postgres.NewStudentsRepository(dbConn)
+			repository := postgres.NewStudentsRepository(dbConn, pgfixtures.NewKeyring(t))
+			coursesRepository := postgres.NewCoursesRepository(dbConn)
+			for _, course := range []entities.Course{openCourse, closedCourse} {
+				require.NoError(t, coursesRepository.SaveCourse(ctx, course))
+			}
 
 			kClient := kfixtures.NewKafkaClient(t)
-			producer := kafka.NewProducer(kClient)
-			eventsProducer := kafka.NewStudentsProducer(producer)
+			producer := kafka.NewProducer(kClient, kafka.JSONSerializer{})
+			eventsProducer := kafka.NewStudentsProducer(producer, postgres.NewStudentKeysRepository(dbConn))
 
-			r := NewRegisterUseCase(repository, eventsProducer)
+			r := NewRegisterUseCase(repository, coursesRepository, eventsProducer)
 
 			// test
 			got, err := r.RegisterStudent(ctx, tc.input)
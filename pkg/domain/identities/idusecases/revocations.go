@@ -0,0 +1,38 @@
+package idusecases
+
+import (
+	"context"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type revokingSessions struct {
+	identities.SessionsRepository
+	revocations identities.TokenRevocationList
+}
+
+// RevokingSessions lists the revoked sessions in the revocation list before deleting them, so the replicas
+// verifying tokens in the stateless mode refuse them too, and the ones caching them drop them.
+func RevokingSessions(sessions identities.SessionsRepository, revocations identities.TokenRevocationList) identities.SessionsRepository {
+	return revokingSessions{
+		SessionsRepository: sessions,
+		revocations:        revocations,
+	}
+}
+
+// RevokeStudentSessions lists the sessions first, a failed revocation can be retried while the sessions are
+// still there.
+func (r revokingSessions) RevokeStudentSessions(ctx context.Context, studentID string) (int, error) {
+	sessions, err := r.ListStudentSessions(ctx, studentID)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(sessions) > 0 {
+		if err = r.revocations.Revoke(ctx, sessions...); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.SessionsRepository.RevokeStudentSessions(ctx, studentID)
+}
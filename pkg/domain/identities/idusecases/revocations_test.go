@@ -0,0 +1,87 @@
+package idusecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+	"github.com/tccav/identity-service/pkg/gateways/inmemory"
+)
+
+func TestRevokingSessions_RevokeStudentSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list the sessions in the revocation list before revoking them", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		tokensRepository := inmemory.NewTokensRepository()
+		studentID := uuid.NewString()
+		token := entities.NewToken(studentID, time.Now().Add(time.Hour).UTC())
+		require.NoError(t, tokensRepository.Register(ctx, token))
+
+		revocations := &idmocks.TokenRevocationListMock{
+			RevokeFunc: func(context.Context, ...entities.Token) error { return nil },
+		}
+
+		// test
+		got, err := RevokingSessions(tokensRepository, revocations).RevokeStudentSessions(ctx, studentID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+		require.Len(t, revocations.RevokeCalls(), 1)
+		revoked := revocations.RevokeCalls()[0].Tokens
+		require.Len(t, revoked, 1)
+		assert.Equal(t, token.ID, revoked[0].ID)
+		assert.WithinDuration(t, token.ExpirationDate, revoked[0].ExpirationDate, time.Second)
+	})
+
+	t.Run("should keep the sessions when the revocation fails", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		tokensRepository := inmemory.NewTokensRepository()
+		studentID := uuid.NewString()
+		require.NoError(t, tokensRepository.Register(ctx, entities.NewToken(studentID, time.Now().Add(time.Hour))))
+
+		errRevoke := errors.New("unavailable")
+		revocations := &idmocks.TokenRevocationListMock{
+			RevokeFunc: func(context.Context, ...entities.Token) error { return errRevoke },
+		}
+
+		// test
+		_, err := RevokingSessions(tokensRepository, revocations).RevokeStudentSessions(ctx, studentID)
+
+		// assert
+		assert.ErrorIs(t, err, errRevoke)
+		sessions, err := tokensRepository.ListStudentSessions(ctx, studentID)
+		require.NoError(t, err)
+		assert.Len(t, sessions, 1)
+	})
+
+	t.Run("should not publish revocations of a student without sessions", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		revocations := &idmocks.TokenRevocationListMock{}
+
+		// test
+		got, err := RevokingSessions(inmemory.NewTokensRepository(), revocations).
+			RevokeStudentSessions(context.Background(), uuid.NewString())
+
+		// assert
+		require.NoError(t, err)
+		assert.Zero(t, got)
+		assert.Empty(t, revocations.RevokeCalls())
+	})
+}
@@ -0,0 +1,161 @@
+package idusecases
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type StudentsSearchUseCase struct {
+	repository identities.StudentsSearchRepository
+	tracer     trace.Tracer
+}
+
+func NewStudentsSearchUseCase(repository identities.StudentsSearchRepository) StudentsSearchUseCase {
+	return StudentsSearchUseCase{
+		repository: repository,
+		tracer:     otel.Tracer(tracerName),
+	}
+}
+
+// SearchStudents lists the students matching every informed filter from the latest registered one. Invalid
+// parameters are reported together in a ValidationError.
+func (u StudentsSearchUseCase) SearchStudents(ctx context.Context, input identities.SearchStudentsInput) (identities.StudentsPage, error) {
+	ctx, span := u.tracer.Start(ctx, "StudentsSearchUseCase.SearchStudents")
+	defer span.End()
+
+	filter, err := newStudentsFilter(input)
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentsPage{}, err
+	}
+
+	// one student more than the limit is fetched to know if there is a next page
+	limit := filter.Limit
+	filter.Limit++
+
+	students, err := u.repository.SearchStudents(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		return identities.StudentsPage{}, err
+	}
+
+	var page identities.StudentsPage
+	if len(students) > limit {
+		students = students[:limit]
+		last := students[limit-1]
+		page.NextCursor = encodeCursor(identities.StudentsCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	page.Students = students
+
+	span.SetAttributes(attribute.Int("search.results", len(students)))
+	return page, nil
+}
+
+func newStudentsFilter(input identities.SearchStudentsInput) (identities.StudentsFilter, error) {
+	var validationErr entities.ValidationError
+
+	filter := identities.StudentsFilter{
+		Name:     strings.TrimSpace(input.Name),
+		Email:    strings.TrimSpace(input.Email),
+		CPF:      strings.TrimSpace(input.CPF),
+		CourseID: input.CourseID,
+		Limit:    input.Limit,
+	}
+
+	if input.Status != "" {
+		status, err := entities.ParseStudentStatus(input.Status)
+		if err != nil {
+			validationErr.Add("status", err)
+		}
+		filter.Status = status
+	}
+
+	if input.CourseID != "" {
+		if _, err := uuid.Parse(input.CourseID); err != nil {
+			validationErr.Add("course_id", fmt.Errorf("%w: %s", identities.ErrInvalidCourseID, err))
+		}
+	}
+
+	if input.RegisteredFrom != "" {
+		from, err := time.Parse(time.DateOnly, input.RegisteredFrom)
+		if err != nil {
+			validationErr.Add("registered_from", fmt.Errorf("%w: %s", identities.ErrInvalidRegistrationDate, err))
+		}
+		filter.RegisteredFrom = from
+	}
+
+	if input.RegisteredTo != "" {
+		to, err := time.Parse(time.DateOnly, input.RegisteredTo)
+		if err != nil {
+			validationErr.Add("registered_to", fmt.Errorf("%w: %s", identities.ErrInvalidRegistrationDate, err))
+		} else {
+			filter.RegisteredUntil = to.AddDate(0, 0, 1)
+		}
+	}
+
+	if !filter.RegisteredFrom.IsZero() && !filter.RegisteredUntil.IsZero() && !filter.RegisteredFrom.Before(filter.RegisteredUntil) {
+		validationErr.Add("registered_to", fmt.Errorf("%w: range ends before it starts", identities.ErrInvalidRegistrationDate))
+	}
+
+	switch {
+	case input.Limit == 0:
+		filter.Limit = defaultPageLimit
+	case input.Limit < 0 || input.Limit > maxPageLimit:
+		validationErr.Add("limit", fmt.Errorf("%w: must be between 1 and %d", identities.ErrInvalidPageLimit, maxPageLimit))
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeCursor(input.Cursor)
+		if err != nil {
+			validationErr.Add("cursor", err)
+		}
+		filter.After = &cursor
+	}
+
+	if err := validationErr.Err(); err != nil {
+		return identities.StudentsFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// encodeCursor keeps the cursor opaque to clients, so its content can change without breaking them.
+func encodeCursor(cursor identities.StudentsCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(encoded string) (identities.StudentsCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return identities.StudentsCursor{}, fmt.Errorf("%w: %s", identities.ErrInvalidPageCursor, err)
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return identities.StudentsCursor{}, identities.ErrInvalidPageCursor
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return identities.StudentsCursor{}, fmt.Errorf("%w: %s", identities.ErrInvalidPageCursor, err)
+	}
+
+	return identities.StudentsCursor{CreatedAt: t, ID: id}, nil
+}
@@ -0,0 +1,83 @@
+package idusecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+)
+
+func TestStudentsSearchUseCase_SearchStudents(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report every invalid parameter at once", func(t *testing.T) {
+		t.Parallel()
+
+		u := NewStudentsSearchUseCase(nil)
+
+		_, err := u.SearchStudents(context.Background(), identities.SearchStudentsInput{
+			Status:         "expelled",
+			CourseID:       "657970",
+			RegisteredFrom: "2023-07-10",
+			RegisteredTo:   "2023-07-01",
+			Cursor:         "invalid",
+			Limit:          101,
+		})
+
+		var validationErr entities.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+
+		fields := make([]string, 0, len(validationErr.Fields))
+		for _, f := range validationErr.Fields {
+			fields = append(fields, f.Field)
+		}
+		assert.Equal(t, []string{"status", "course_id", "registered_to", "limit", "cursor"}, fields)
+	})
+
+	t.Run("should walk through every page", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		repository := postgres.NewStudentsRepository(pgfixtures.NewDB(t), pgfixtures.NewKeyring(t))
+		for _, s := range []entities.Student{
+			{ID: "201116548712", Name: "John Doe", CPF: "11111111030", Email: "jdoe@ol.com", Status: entities.StudentStatusActive},
+			{ID: "201116548713", Name: "Jane Doe", CPF: "52998224725", Email: "jane@ol.com", Status: entities.StudentStatusActive},
+			{ID: "201116548714", Name: "Joe Doe", CPF: "39053344705", Email: "joe@ol.com", Status: entities.StudentStatusActive},
+		} {
+			require.NoError(t, repository.CreateStudent(ctx, s))
+		}
+
+		u := NewStudentsSearchUseCase(repository)
+
+		// test
+		var (
+			ids    []string
+			cursor string
+			pages  int
+		)
+		for {
+			page, err := u.SearchStudents(ctx, identities.SearchStudentsInput{Name: "doe", Cursor: cursor, Limit: 2})
+			require.NoError(t, err)
+			pages++
+
+			for _, s := range page.Students {
+				ids = append(ids, s.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+
+		// assert
+		assert.Equal(t, 2, pages)
+		assert.ElementsMatch(t, []string{"201116548712", "201116548713", "201116548714"}, ids)
+	})
+}
@@ -0,0 +1,91 @@
+package idusecases
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type StudentStatusUseCase struct {
+	repository    identities.StudentStatusRepository
+	sessions      identities.SessionsRepository
+	eventProducer identities.StudentsProducer
+	tracer        trace.Tracer
+}
+
+// NewStudentStatusUseCase revokes the sessions of the students who may no longer authenticate, so the tokens are
+// not verified against the student status.
+func NewStudentStatusUseCase(
+	repository identities.StudentStatusRepository,
+	sessions identities.SessionsRepository,
+	eventProducer identities.StudentsProducer,
+) StudentStatusUseCase {
+	return StudentStatusUseCase{
+		repository:    repository,
+		sessions:      sessions,
+		eventProducer: eventProducer,
+		tracer:        otel.Tracer(tracerName),
+	}
+}
+
+func (s StudentStatusUseCase) ChangeStudentStatus(ctx context.Context, input identities.ChangeStudentStatusInput) (entities.StudentStatusTransition, error) {
+	ctx, span := s.tracer.Start(ctx, "StudentStatusUseCase.ChangeStudentStatus")
+	defer span.End()
+
+	if input.StudentID == "" {
+		span.RecordError(identities.ErrEmptyStudentID)
+		return entities.StudentStatusTransition{}, identities.ErrEmptyStudentID
+	}
+
+	next, err := entities.ParseStudentStatus(input.Status)
+	if err != nil {
+		span.RecordError(err)
+		return entities.StudentStatusTransition{}, err
+	}
+
+	current, err := s.repository.GetStudentStatus(ctx, input.StudentID)
+	if err != nil {
+		span.RecordError(err)
+		return entities.StudentStatusTransition{}, err
+	}
+
+	transition, err := entities.NewStudentStatusTransition(input.StudentID, current, next, input.Reason)
+	if err != nil {
+		span.RecordError(err)
+		return entities.StudentStatusTransition{}, err
+	}
+	span.SetAttributes(
+		attribute.String("student.status.from", string(transition.From)),
+		attribute.String("student.status.to", string(transition.To)),
+	)
+
+	err = s.repository.UpdateStudentStatus(ctx, transition)
+	if err != nil {
+		span.RecordError(err)
+		return entities.StudentStatusTransition{}, err
+	}
+
+	// FIXIT: the status is not reverted if the sessions could not be revoked, they must be revoked by hand
+	if !transition.To.CanAuthenticate() {
+		revoked, err := s.sessions.RevokeStudentSessions(ctx, input.StudentID)
+		if err != nil {
+			span.RecordError(err)
+			return entities.StudentStatusTransition{}, err
+		}
+		span.SetAttributes(attribute.Int("student.revoked_sessions", revoked))
+	}
+
+	// FIXIT: same as registration, the status is not reverted if the event could not be produced
+	err = s.eventProducer.ProduceStudentStatusChanged(ctx, transition)
+	if err != nil {
+		span.RecordError(err)
+		return entities.StudentStatusTransition{}, err
+	}
+
+	return transition, nil
+}
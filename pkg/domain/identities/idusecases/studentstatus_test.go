@@ -0,0 +1,121 @@
+package idusecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/kafka"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/postgres"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+	"github.com/tccav/identity-service/pkg/gateways/redis"
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+func TestStudentStatusUseCase_ChangeStudentStatus(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name          string
+		currentStatus entities.StudentStatus
+		input         identities.ChangeStudentStatusInput
+		wantSessions  int
+		wantErr       error
+	}{
+		{
+			name:          "should activate pending student",
+			currentStatus: entities.StudentStatusPending,
+			input:         identities.ChangeStudentStatusInput{Status: "active"},
+			wantSessions:  1,
+		},
+		{
+			name:          "should suspend active student revoking the sessions",
+			currentStatus: entities.StudentStatusActive,
+			input:         identities.ChangeStudentStatusInput{Status: "suspended", Reason: "Disciplinary suspension"},
+		},
+		{
+			name:          "should graduate active student keeping the sessions",
+			currentStatus: entities.StudentStatusActive,
+			input:         identities.ChangeStudentStatusInput{Status: "graduated"},
+			wantSessions:  1,
+		},
+		{
+			name:          "should fail due to unknown status",
+			currentStatus: entities.StudentStatusActive,
+			input:         identities.ChangeStudentStatusInput{Status: "expelled"},
+			wantErr:       entities.ErrInvalidStudentStatus,
+		},
+		{
+			name:          "should fail because graduated is a final status",
+			currentStatus: entities.StudentStatusGraduated,
+			input:         identities.ChangeStudentStatusInput{Status: "active"},
+			wantErr:       entities.ErrInvalidStatusTransition,
+		},
+		{
+			name:          "should fail because pending student can not graduate",
+			currentStatus: entities.StudentStatusPending,
+			input:         identities.ChangeStudentStatusInput{Status: "graduated"},
+			wantErr:       entities.ErrInvalidStatusTransition,
+		},
+		{
+			name:          "should fail because student does not exist",
+			currentStatus: entities.StudentStatusActive,
+			input:         identities.ChangeStudentStatusInput{StudentID: "000000000", Status: "suspended"},
+			wantErr:       identities.ErrStudentNotFound,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			ctx := context.Background()
+
+			db := pgfixtures.NewDB(t)
+			repository := postgres.NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+			student := newStoredStudent(t, repository, "test_password", tc.currentStatus)
+
+			kClient := kfixtures.NewKafkaClient(t)
+			eventsProducer := kafka.NewStudentsProducer(kafka.NewProducer(kClient, kafka.JSONSerializer{}), postgres.NewStudentKeysRepository(db))
+
+			tokensRepository := redis.NewTokensRepository(rfixtures.NewDB(t))
+			require.NoError(t, tokensRepository.Register(ctx, entities.NewToken(student.ID, time.Now().Add(time.Hour))))
+
+			s := NewStudentStatusUseCase(repository, tokensRepository, eventsProducer)
+
+			input := tc.input
+			if input.StudentID == "" {
+				input.StudentID = student.ID
+			}
+
+			// test
+			got, err := s.ChangeStudentStatus(ctx, input)
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.currentStatus, got.From)
+			assert.Equal(t, entities.StudentStatus(tc.input.Status), got.To)
+
+			stored, err := repository.GetStudentStatus(ctx, student.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, got.To, stored)
+
+			sessions, err := tokensRepository.ListStudentSessions(ctx, student.ID)
+			assert.NoError(t, err)
+			assert.Len(t, sessions, tc.wantSessions)
+		})
+	}
+}
@@ -2,8 +2,12 @@ package idusecases
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lestrrat-go/jwx/v2/jwa"
@@ -16,20 +20,30 @@ import (
 
 type jwtTokenMaker struct {
 	secret     string
+	digestKey  string
 	issuer     string
 	duration   time.Duration
 	repository identities.TokenRegistererRepository
 	tracer     trace.Tracer
 }
 
-func (m jwtTokenMaker) createToken(ctx context.Context, userID string) (entities.Token, error) {
+func (m jwtTokenMaker) createToken(
+	ctx context.Context,
+	userID string,
+	client entities.AuthenticationClient,
+	audience string,
+	claims entities.TokenClaims,
+) (entities.Token, error) {
 	ctx, span := m.tracer.Start(ctx, "jwtTokenMaker.createToken")
 	defer span.End()
-	token, err := m.buildSignedJWT(userID)
+	token, err := m.buildSignedJWT(userID, audience, claims)
 	if err != nil {
 		return entities.Token{}, err
 	}
 
+	token.Client = client
+	token.Digest = m.digest(token.Hash)
+
 	err = m.repository.Register(ctx, token)
 	if err != nil {
 		return entities.Token{}, err
@@ -38,16 +52,27 @@ func (m jwtTokenMaker) createToken(ctx context.Context, userID string) (entities
 	return token, nil
 }
 
-func (m jwtTokenMaker) buildSignedJWT(userID string) (entities.Token, error) {
+func (m jwtTokenMaker) buildSignedJWT(userID string, audience string, claims entities.TokenClaims) (entities.Token, error) {
 	now := time.Now().UTC()
 	token := entities.NewToken(userID, now.Add(m.duration))
+	token.IssuedAt = now
+	token.Audience = audience
+	token.Claims = claims
 
-	t, err := jwt.NewBuilder().
+	builder := jwt.NewBuilder().
 		JwtID(token.ID).
 		Expiration(token.ExpirationDate).
 		IssuedAt(now).
 		Issuer(m.issuer).
-		Subject(token.UserID).Build()
+		Subject(token.UserID)
+	if audience != "" {
+		builder = builder.Audience([]string{audience})
+	}
+	for name, value := range encodeClaims(claims) {
+		builder = builder.Claim(name, value)
+	}
+
+	t, err := builder.Build()
 	if err != nil {
 		return entities.Token{}, err
 	}
@@ -61,27 +86,75 @@ func (m jwtTokenMaker) buildSignedJWT(userID string) (entities.Token, error) {
 	return token, nil
 }
 
-func (m jwtTokenMaker) verifyToken(ctx context.Context, hash string) error {
-	token, err := jwt.ParseString(
-		hash,
+// verifyToken also checks the token against the one registered under its id.
+func (m jwtTokenMaker) verifyToken(ctx context.Context, hash string, audience string) (entities.Token, error) {
+	token, err := m.parseToken(hash, audience)
+	if err != nil {
+		return entities.Token{}, err
+	}
+
+	storedDigest, err := m.repository.GetDigest(ctx, token.ID)
+	if err != nil {
+		return entities.Token{}, err
+	}
+
+	if !m.matches(storedDigest, hash) {
+		return entities.Token{}, identities.ErrTokenNotEmitted
+	}
+
+	return token, nil
+}
+
+// parseToken only checks the token signature, issuer and expiration, and its audience when one is expected.
+func (m jwtTokenMaker) parseToken(hash string, audience string) (entities.Token, error) {
+	options := []jwt.ParseOption{
 		jwt.WithIssuer(m.issuer),
 		jwt.WithKey(jwa.HS256, []byte(m.secret)),
-	)
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired()) {
-			return identities.ErrTokenExpired
-		}
-		return fmt.Errorf("%w: %s", identities.ErrMalformedToken, err)
+	}
+	if audience != "" {
+		options = append(options, jwt.WithAudience(audience))
 	}
 
-	storedHash, err := m.repository.GetHash(ctx, token.JwtID())
+	token, err := jwt.ParseString(hash, options...)
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired()):
+			return entities.Token{}, identities.ErrTokenExpired
+		case errors.Is(err, jwt.ErrInvalidAudience()):
+			return entities.Token{}, identities.ErrWrongAudience
+		}
+		return entities.Token{}, fmt.Errorf("%w: %s", identities.ErrMalformedToken, err)
 	}
 
-	if storedHash != hash {
-		return identities.ErrTokenNotEmitted
+	var tokenAudience string
+	if audiences := token.Audience(); len(audiences) > 0 {
+		tokenAudience = audiences[0]
 	}
 
-	return nil
+	return entities.Token{
+		ID:             token.JwtID(),
+		UserID:         token.Subject(),
+		Audience:       tokenAudience,
+		IssuedAt:       token.IssuedAt(),
+		ExpirationDate: token.Expiration(),
+		Hash:           hash,
+		Claims:         decodeClaims(token.PrivateClaims()),
+	}, nil
+}
+
+// digest is the hex encoded HMAC-SHA256 of the signed token, keyed apart from the token signature, so whoever
+// reads the tokens store can neither use nor forge the tokens.
+func (m jwtTokenMaker) digest(hash string) string {
+	mac := hmac.New(sha256.New, []byte(m.digestKey))
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// matches compares the stored digest in constant time. Tokens registered before digests were stored keep the
+// signed token itself, told apart by its three dot separated parts, they are compared as is until they expire.
+func (m jwtTokenMaker) matches(storedDigest, hash string) bool {
+	if strings.Count(storedDigest, ".") == 2 {
+		return hmac.Equal([]byte(storedDigest), []byte(hash))
+	}
+	return hmac.Equal([]byte(storedDigest), []byte(m.digest(hash)))
 }
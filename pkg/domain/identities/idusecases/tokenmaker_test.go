@@ -0,0 +1,162 @@
+package idusecases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+func TestJWTTokenMaker_matches(t *testing.T) {
+	t.Parallel()
+
+	maker := jwtTokenMaker{digestKey: validConfig.digestKey}
+	const hash = "header.payload.signature"
+
+	tt := []struct {
+		name   string
+		stored string
+		hash   string
+		want   bool
+	}{
+		{
+			name:   "should match the digest of the token",
+			stored: maker.digest(hash),
+			hash:   hash,
+			want:   true,
+		},
+		{
+			name:   "should match a token stored before digests were",
+			stored: hash,
+			hash:   hash,
+			want:   true,
+		},
+		{
+			name:   "should not match the digest of another token",
+			stored: maker.digest("header.payload.other"),
+			hash:   hash,
+		},
+		{
+			name:   "should not match the digest keyed by another key",
+			stored: jwtTokenMaker{digestKey: "other_secret"}.digest(hash),
+			hash:   hash,
+		},
+		{
+			name:   "should not match the digest presented as the token",
+			stored: maker.digest(hash),
+			hash:   maker.digest(hash),
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, maker.matches(tc.stored, tc.hash))
+		})
+	}
+}
+
+func TestJWTTokenMaker_claims(t *testing.T) {
+	t.Parallel()
+
+	maker := jwtTokenMaker{secret: validConfig.secret, issuer: validConfig.issuer, duration: validConfig.duration}
+
+	tt := []struct {
+		name   string
+		claims entities.TokenClaims
+	}{
+		{
+			name: "should carry every claim set",
+			claims: entities.TokenClaims{
+				Name:      "John Doe",
+				CourseIDs: []string{"1", "2"},
+				Status:    entities.StudentStatusActive,
+			},
+		},
+		{
+			name: "should leave the unset claims out",
+			claims: entities.TokenClaims{
+				Status: entities.StudentStatusSuspended,
+			},
+		},
+		{
+			name: "should carry no claims",
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			token, err := maker.buildSignedJWT("201116548712", "", tc.claims)
+			require.NoError(t, err)
+
+			// test
+			got, err := maker.parseToken(token.Hash, "")
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.claims, got.Claims)
+		})
+	}
+}
+
+func TestJWTTokenMaker_parseToken_audience(t *testing.T) {
+	t.Parallel()
+
+	maker := jwtTokenMaker{secret: validConfig.secret, issuer: validConfig.issuer, duration: validConfig.duration}
+
+	tt := []struct {
+		name     string
+		issuedTo string
+		expected string
+		wantErr  error
+	}{
+		{
+			name:     "should accept the audience the token was issued to",
+			issuedTo: "courses-service",
+			expected: "courses-service",
+		},
+		{
+			name:     "should accept any audience when none is expected",
+			issuedTo: "courses-service",
+		},
+		{
+			name:     "should refuse a token issued to another audience",
+			issuedTo: "courses-service",
+			expected: "grades-service",
+			wantErr:  identities.ErrWrongAudience,
+		},
+		{
+			name:     "should refuse a token issued to no audience when one is expected",
+			expected: "grades-service",
+			wantErr:  identities.ErrWrongAudience,
+		},
+	}
+	for _, tc := range tt {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			token, err := maker.buildSignedJWT("201116548712", tc.issuedTo, entities.TokenClaims{})
+			require.NoError(t, err)
+
+			// test
+			got, err := maker.parseToken(token.Hash, tc.expected)
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.issuedTo, got.Audience)
+		})
+	}
+}
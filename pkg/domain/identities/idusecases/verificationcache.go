@@ -0,0 +1,179 @@
+package idusecases
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const meterName = "github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+
+// verificationCache keeps the last verified tokens by their digest, so the raw tokens are never held in memory.
+// Once full, the least recently used token is dropped. A token is kept for maxTTL at most, never past its
+// expiration, and dropped as soon as it is revoked by any replica.
+type verificationCache struct {
+	mu       *sync.Mutex
+	size     int
+	maxTTL   time.Duration
+	entries  *list.List // the most recently used at the front
+	byDigest map[string]*list.Element
+	byID     map[string]*list.Element
+
+	revocations identities.TokenRevocationList
+	lookups     metric.Int64Counter
+	hits        *atomic.Int64
+	misses      *atomic.Int64
+}
+
+type cachedToken struct {
+	digest    string
+	token     entities.Token
+	expiresAt time.Time
+}
+
+func newVerificationCache(meter metric.Meter, revocations identities.TokenRevocationList, size int, maxTTL time.Duration) (*verificationCache, error) {
+	c := &verificationCache{
+		mu:          &sync.Mutex{},
+		size:        size,
+		maxTTL:      maxTTL,
+		entries:     list.New(),
+		byDigest:    map[string]*list.Element{},
+		byID:        map[string]*list.Element{},
+		revocations: revocations,
+		hits:        &atomic.Int64{},
+		misses:      &atomic.Int64{},
+	}
+
+	if err := c.registerMetrics(meter); err != nil {
+		return nil, err
+	}
+
+	revocations.OnRevoke(c.invalidate)
+	return c, nil
+}
+
+// registerMetrics counts the lookups by result and reports the hit ratio since the process started.
+func (c *verificationCache) registerMetrics(meter metric.Meter) error {
+	lookups, err := meter.Int64Counter("auth.verification_cache.lookups",
+		metric.WithDescription("Verified tokens looked up in the cache, by result"),
+	)
+	if err != nil {
+		return err
+	}
+	c.lookups = lookups
+
+	hitRatio, err := meter.Float64ObservableGauge("auth.verification_cache.hit_ratio",
+		metric.WithDescription("Share of the verified tokens lookups found in the cache"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, observer metric.Observer) error {
+		observer.ObserveFloat64(hitRatio, c.hitRatio())
+		return nil
+	}, hitRatio)
+	return err
+}
+
+func (c *verificationCache) hitRatio() float64 {
+	hits, misses := c.hits.Load(), c.misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// get checks the revocation list again, a token being revoked while verified may have been cached after its
+// invalidation.
+func (c *verificationCache) get(ctx context.Context, digest string) (entities.Token, bool) {
+	token, ok := c.lookup(digest, time.Now())
+	if ok && c.revocations.IsRevoked(token.ID) {
+		c.invalidate(token.ID)
+		token, ok = entities.Token{}, false
+	}
+
+	result := "miss"
+	if ok {
+		result = "hit"
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	c.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+
+	return token, ok
+}
+
+func (c *verificationCache) lookup(digest string, now time.Time) (entities.Token, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.byDigest[digest]
+	if !ok {
+		return entities.Token{}, false
+	}
+
+	cached := element.Value.(cachedToken)
+	if !now.Before(cached.expiresAt) {
+		c.remove(element)
+		return entities.Token{}, false
+	}
+
+	c.entries.MoveToFront(element)
+	return cached.token, true
+}
+
+func (c *verificationCache) add(digest string, token entities.Token) {
+	now := time.Now()
+	ttl := c.maxTTL
+	if remaining := token.ExpirationDate.Sub(now); remaining < ttl {
+		ttl = remaining
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	token.Hash = ""
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.byDigest[digest]; ok {
+		c.remove(element)
+	}
+
+	element := c.entries.PushFront(cachedToken{digest: digest, token: token, expiresAt: now.Add(ttl)})
+	c.byDigest[digest] = element
+	c.byID[token.ID] = element
+
+	for c.entries.Len() > c.size {
+		c.remove(c.entries.Back())
+	}
+}
+
+func (c *verificationCache) invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		if element, ok := c.byID[id]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+// remove must be called with the lock held.
+func (c *verificationCache) remove(element *list.Element) {
+	cached := c.entries.Remove(element).(cachedToken)
+	delete(c.byDigest, cached.digest)
+	delete(c.byID, cached.token.ID)
+}
@@ -0,0 +1,212 @@
+package idusecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+)
+
+func newTestVerificationCache(t *testing.T, revoked map[string]bool, size int, maxTTL time.Duration) (*verificationCache, func(ids ...string)) {
+	t.Helper()
+
+	var listener func(ids ...string)
+	revocations := &idmocks.TokenRevocationListMock{
+		IsRevokedFunc: func(id string) bool { return revoked[id] },
+		OnRevokeFunc:  func(l func(ids ...string)) { listener = l },
+	}
+
+	cache, err := newVerificationCache(otel.Meter(meterName), revocations, size, maxTTL)
+	require.NoError(t, err)
+	require.NotNil(t, listener)
+
+	return cache, listener
+}
+
+func TestVerificationCache(t *testing.T) {
+	t.Parallel()
+
+	newToken := func(ttl time.Duration) entities.Token {
+		token := entities.NewToken(uuid.NewString(), time.Now().Add(ttl))
+		token.Hash = "header.payload.signature"
+		return token
+	}
+
+	t.Run("should get a verified token without its signed token", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		cache, _ := newTestVerificationCache(t, nil, 10, time.Minute)
+		token := newToken(time.Hour)
+		cache.add("digest", token)
+
+		// test
+		got, ok := cache.get(context.Background(), "digest")
+
+		// assert
+		require.True(t, ok)
+		assert.Equal(t, token.ID, got.ID)
+		assert.Empty(t, got.Hash)
+		assert.Equal(t, 1.0, cache.hitRatio())
+	})
+
+	t.Run("should drop the least recently used token once full", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		cache, _ := newTestVerificationCache(t, nil, 2, time.Minute)
+		cache.add("first", newToken(time.Hour))
+		cache.add("second", newToken(time.Hour))
+		_, ok := cache.get(ctx, "first")
+		require.True(t, ok)
+
+		// test
+		cache.add("third", newToken(time.Hour))
+
+		// assert
+		_, ok = cache.get(ctx, "second")
+		assert.False(t, ok)
+		for _, digest := range []string{"first", "third"} {
+			_, ok = cache.get(ctx, digest)
+			assert.True(t, ok, digest)
+		}
+		assert.Equal(t, 0.75, cache.hitRatio())
+	})
+
+	t.Run("should not keep a token past its expiration", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		cache, _ := newTestVerificationCache(t, nil, 10, time.Hour)
+		cache.add("digest", newToken(200*time.Millisecond))
+
+		// test
+		time.Sleep(300 * time.Millisecond)
+
+		// assert
+		_, ok := cache.get(context.Background(), "digest")
+		assert.False(t, ok)
+		assert.Zero(t, cache.entries.Len())
+	})
+
+	t.Run("should not keep a token past the max ttl", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		cache, _ := newTestVerificationCache(t, nil, 10, 200*time.Millisecond)
+		cache.add("digest", newToken(time.Hour))
+
+		// test
+		time.Sleep(300 * time.Millisecond)
+
+		// assert
+		_, ok := cache.get(context.Background(), "digest")
+		assert.False(t, ok)
+	})
+
+	t.Run("should not cache an expired token", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		cache, _ := newTestVerificationCache(t, nil, 10, time.Minute)
+
+		// test
+		cache.add("digest", newToken(-time.Second))
+
+		// assert
+		assert.Zero(t, cache.entries.Len())
+	})
+
+	t.Run("should drop a token once revoked", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		cache, revoke := newTestVerificationCache(t, nil, 10, time.Minute)
+		token := newToken(time.Hour)
+		other := newToken(time.Hour)
+		cache.add("digest", token)
+		cache.add("other", other)
+
+		// test
+		revoke(token.ID, uuid.NewString())
+
+		// assert
+		ctx := context.Background()
+		_, ok := cache.get(ctx, "digest")
+		assert.False(t, ok)
+		_, ok = cache.get(ctx, "other")
+		assert.True(t, ok)
+	})
+
+	t.Run("should not get a token revoked while it was verified", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		token := newToken(time.Hour)
+		cache, _ := newTestVerificationCache(t, map[string]bool{token.ID: true}, 10, time.Minute)
+		cache.add("digest", token)
+
+		// test
+		_, ok := cache.get(context.Background(), "digest")
+
+		// assert
+		assert.False(t, ok)
+		assert.Zero(t, cache.entries.Len())
+	})
+}
+
+func TestVerificationCache_metrics(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter(meterName)
+	revocations := &idmocks.TokenRevocationListMock{
+		IsRevokedFunc: func(string) bool { return false },
+		OnRevokeFunc:  func(func(ids ...string)) {},
+	}
+	cache, err := newVerificationCache(meter, revocations, 10, time.Minute)
+	require.NoError(t, err)
+	cache.add("digest", entities.NewToken(uuid.NewString(), time.Now().Add(time.Hour)))
+
+	// test
+	for _, digest := range []string{"digest", "digest", "digest", "other"} {
+		cache.get(ctx, digest)
+	}
+
+	// assert
+	var collected metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &collected))
+	require.Len(t, collected.ScopeMetrics, 1)
+
+	got := map[string]metricdata.Aggregation{}
+	for _, m := range collected.ScopeMetrics[0].Metrics {
+		got[m.Name] = m.Data
+	}
+
+	lookups, ok := got["auth.verification_cache.lookups"].(metricdata.Sum[int64])
+	require.True(t, ok)
+	byResult := map[string]int64{}
+	for _, point := range lookups.DataPoints {
+		result, _ := point.Attributes.Value(attribute.Key("result"))
+		byResult[result.AsString()] = point.Value
+	}
+	assert.Equal(t, map[string]int64{"hit": 3, "miss": 1}, byResult)
+
+	hitRatio, ok := got["auth.verification_cache.hit_ratio"].(metricdata.Gauge[float64])
+	require.True(t, ok)
+	require.Len(t, hitRatio.DataPoints, 1)
+	assert.Equal(t, 0.75, hitRatio.DataPoints[0].Value)
+}
@@ -2,6 +2,7 @@ package identities
 
 import (
 	"context"
+	"time"
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
 )
@@ -10,11 +11,128 @@ type StudentsRegistererRepository interface {
 	CreateStudent(ctx context.Context, student entities.Student) error
 }
 
+type StudentsImporterRepository interface {
+	StudentsRegistererRepository
+	CreateStudents(ctx context.Context, students []entities.Student) error
+	// FindConflicts returns, for each informed student, the error its registration would fail with
+	// due to an already registered student, or nil if there is none.
+	FindConflicts(ctx context.Context, students []entities.Student) ([]error, error)
+}
+
+// StudentsCursor points to the last student of a page, students are listed from the latest registered one.
+type StudentsCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// StudentsFilter has the search criteria, empty fields are not filtered by. RegisteredUntil is exclusive.
+type StudentsFilter struct {
+	Name            string
+	Email           string
+	CPF             string
+	Status          entities.StudentStatus
+	CourseID        string
+	RegisteredFrom  time.Time
+	RegisteredUntil time.Time
+	After           *StudentsCursor
+	Limit           int
+}
+
+type StudentsSearchRepository interface {
+	SearchStudents(ctx context.Context, filter StudentsFilter) ([]entities.Student, error)
+}
+
 type StudentListerRepository interface {
 	GetStudentSecret(ctx context.Context, id string) (string, error)
+	GetStudentStatus(ctx context.Context, id string) (entities.StudentStatus, error)
+}
+
+type StudentDataRepository interface {
+	// GetStudent returns the student without its secret.
+	GetStudent(ctx context.Context, id string) (entities.Student, error)
+}
+
+type LoginHistoryRepository interface {
+	RecordLogin(ctx context.Context, attempt entities.LoginAttempt) error
+	ListLogins(ctx context.Context, studentID string) ([]entities.LoginAttempt, error)
+}
+
+type StudentErasureRepository interface {
+	// EraseStudent blanks the student personal data, deletes its login history and destroys its key, all at
+	// once. Erasing an already erased student is not an error, its first erasure is returned.
+	EraseStudent(ctx context.Context, erasure entities.StudentErasure) (entities.StudentErasure, error)
+}
+
+type StudentKeysRepository interface {
+	// GetOrCreateStudentKey returns the student key, creating it on the first call.
+	GetOrCreateStudentKey(ctx context.Context, studentID string) (entities.StudentKey, error)
+	GetStudentKey(ctx context.Context, studentID string) (entities.StudentKey, error)
+}
+
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry entities.AuditEntry) error
+}
+
+type StudentStatusRepository interface {
+	GetStudentStatus(ctx context.Context, id string) (entities.StudentStatus, error)
+	UpdateStudentStatus(ctx context.Context, transition entities.StudentStatusTransition) error
+}
+
+type SessionsRepository interface {
+	// ListStudentSessions returns the tokens of the student that did not expire yet, without their hashes.
+	ListStudentSessions(ctx context.Context, studentID string) ([]entities.Token, error)
+	// RevokeStudentSessions deletes every token of the student, returning how many were still valid.
+	RevokeStudentSessions(ctx context.Context, studentID string) (int, error)
 }
 
 type TokenRegistererRepository interface {
+	// Register keeps the token digest and metadata until it expires, never the signed token itself. Registering
+	// a token again does not replace it.
 	Register(ctx context.Context, token entities.Token) error
-	GetHash(ctx context.Context, id string) (string, error)
+	// GetDigest returns the digest of the token. Tokens registered before digests were stored have the signed
+	// token itself instead.
+	GetDigest(ctx context.Context, id string) (string, error)
+}
+
+// TokensRepository is the store of the emitted tokens, its backend is chosen in the configs.
+type TokensRepository interface {
+	TokenRegistererRepository
+	SessionsRepository
+}
+
+type CoursesRepository interface {
+	SaveCourse(ctx context.Context, course entities.Course) error
+	CloseCourse(ctx context.Context, id string) error
+}
+
+type CourseListerRepository interface {
+	GetCourse(ctx context.Context, id string) (entities.Course, error)
+}
+
+// DeadLettersCursor points to the last dead letter of a page, dead letters are listed from the oldest failure.
+type DeadLettersCursor struct {
+	FailedAt time.Time
+	ID       string
+}
+
+// DeadLettersFilter selects the dead letters of a topic and key, or of every one when empty.
+type DeadLettersFilter struct {
+	Topic string
+	Key   string
+	After *DeadLettersCursor
+	Limit int
+}
+
+// DeadLettersRepository keeps the events that could not be published until they are retried or discarded.
+type DeadLettersRepository interface {
+	// SaveDeadLetter stores the dead letter, replacing the one with the same ID.
+	SaveDeadLetter(ctx context.Context, deadLetter entities.DeadLetter) error
+	ListDeadLetters(ctx context.Context, filter DeadLettersFilter) ([]entities.DeadLetter, error)
+	GetDeadLetter(ctx context.Context, id string) (entities.DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, id string) error
+	CountDeadLetters(ctx context.Context) (int, error)
+	// SavePublishedEvent records the time of an event published for the key, only the latest one is kept.
+	SavePublishedEvent(ctx context.Context, topic, key string, eventTime time.Time) error
+	// GetLastPublishedEvent is the time of the latest event published for the key, zero if none was recorded.
+	GetLastPublishedEvent(ctx context.Context, topic, key string) (time.Time, error)
 }
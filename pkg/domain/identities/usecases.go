@@ -3,16 +3,32 @@ package identities
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
 )
 
-//go:generate moq -out idmocks/mock_usecases.go -pkg idmocks . RegisterUseCases AuthenticationUseCases
+//go:generate moq -out idmocks/mock_usecases.go -pkg idmocks . RegisterUseCases AuthenticationUseCases CourseCatalogUseCases StudentStatusUseCases ImportStudentsUseCases StudentsSearchUseCases DataExportUseCases StudentErasureUseCases DeadLettersUseCases
 
 var (
-	ErrInvalidCourseID      = errors.New("invalid course id")
-	ErrStudentAlreadyExists = errors.New("student already exists")
-	ErrStudentNotFound      = errors.New("student not found")
+	ErrInvalidCourseID         = errors.New("invalid course id")
+	ErrStudentAlreadyExists    = errors.New("student already exists")
+	ErrCPFAlreadyRegistered    = errors.New("cpf already registered to another student")
+	ErrEmailAlreadyRegistered  = errors.New("email already registered to another student")
+	ErrStudentNotFound         = errors.New("student not found")
+	ErrCourseNotFound          = errors.New("course not found")
+	ErrCourseClosed            = errors.New("course is closed")
+	ErrStudentStatusChanged    = errors.New("student status was changed concurrently")
+	ErrMalformedImportRow      = errors.New("malformed import row")
+	ErrDuplicatedImportRow     = errors.New("student repeated in the import file")
+	ErrInvalidPageCursor       = errors.New("invalid page cursor")
+	ErrInvalidPageLimit        = errors.New("invalid page limit")
+	ErrInvalidRegistrationDate = errors.New("invalid registration date")
+	ErrStudentKeyNotFound      = errors.New("student key not found")
+	ErrDeadLetterNotFound      = errors.New("dead letter not found")
+	ErrEmptyDeadLetterID       = errors.New("empty dead letter id was sent")
+	ErrDeadLetterNotPublished  = errors.New("dead letter could not be published")
+	ErrDeadLetterOutOfOrder    = errors.New("dead letter would be published out of order")
 
 	ErrEmptyStudentID   = errors.New("empty student id was sent")
 	ErrEmptySecret      = errors.New("empty secret was sent")
@@ -21,6 +37,13 @@ var (
 	ErrTokenExpired     = errors.New("token expired")
 	ErrMalformedToken   = errors.New("malformed token")
 	ErrTokenNotEmitted  = errors.New("informed token was not emitted by this app")
+	ErrTokenRevoked     = errors.New("token revoked")
+	ErrUnknownClaim     = errors.New("unknown token claim")
+	ErrClaimsTooLarge   = errors.New("token claims exceed their size budget")
+	ErrUnknownAudience  = errors.New("audience is not a registered service")
+	ErrWrongAudience    = errors.New("token was not issued to the audience")
+	ErrStudentSuspended = errors.New("student is suspended")
+	ErrStudentCancelled = errors.New("student enrollment is cancelled")
 )
 
 type RegisterStudentInput struct {
@@ -37,12 +60,172 @@ type RegisterUseCases interface {
 	RegisterStudent(ctx context.Context, input RegisterStudentInput) (string, error)
 }
 
+// AuthenticateStudentInput Audience is the registered service the token is meant for, tokens without one are
+// accepted by every service.
 type AuthenticateStudentInput struct {
 	StudentID     string
 	StudentSecret string
+	Audience      string
+	Client        entities.AuthenticationClient
 }
 
 type AuthenticationUseCases interface {
 	AuthenticateStudent(ctx context.Context, input AuthenticateStudentInput) (entities.Token, error)
-	VerifyAuth(ctx context.Context, hash string) error
+	// VerifyAuth only checks the token audience when one is expected.
+	VerifyAuth(ctx context.Context, hash string, audience string) (entities.Token, error)
+}
+
+type SaveCourseInput struct {
+	ID   string
+	Name string
+}
+
+type CourseCatalogUseCases interface {
+	SaveCourse(ctx context.Context, input SaveCourseInput) error
+	CloseCourse(ctx context.Context, id string) error
+}
+
+type ChangeStudentStatusInput struct {
+	StudentID string
+	Status    string
+	Reason    string
+}
+
+type StudentStatusUseCases interface {
+	ChangeStudentStatus(ctx context.Context, input ChangeStudentStatusInput) (entities.StudentStatusTransition, error)
+}
+
+// StudentImportRow is a student read from an import file, Line is its position in the file.
+type StudentImportRow struct {
+	Line  int
+	Input RegisterStudentInput
+}
+
+// StudentsImportReader streams the rows of an import file. Rows that can not be parsed are returned
+// with their line and an ErrMalformedImportRow, io.EOF is returned once every row was read.
+type StudentsImportReader interface {
+	Next() (StudentImportRow, error)
+}
+
+type ImportStudentsInput struct {
+	Rows   StudentsImportReader
+	DryRun bool
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowValid       ImportRowStatus = "valid"
+	ImportRowImported    ImportRowStatus = "imported"
+	ImportRowEventFailed ImportRowStatus = "event_failed"
+	ImportRowFailed      ImportRowStatus = "failed"
+)
+
+type ImportRowResult struct {
+	Line      int
+	StudentID string
+	Status    ImportRowStatus
+	Err       error
+}
+
+type ImportReport struct {
+	DryRun bool
+	Rows   []ImportRowResult
+}
+
+func (r ImportReport) Count(status ImportRowStatus) int {
+	var count int
+	for _, row := range r.Rows {
+		if row.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+type ImportStudentsUseCases interface {
+	ImportStudents(ctx context.Context, input ImportStudentsInput) (ImportReport, error)
+}
+
+// SearchStudentsInput has the raw search parameters, the registration dates are days in the yyyy-mm-dd format
+// and both ends of the range are inclusive.
+type SearchStudentsInput struct {
+	Name           string
+	Email          string
+	CPF            string
+	Status         string
+	CourseID       string
+	RegisteredFrom string
+	RegisteredTo   string
+	Cursor         string
+	Limit          int
+}
+
+// StudentsPage has the found students, NextCursor is empty when there are no more students.
+type StudentsPage struct {
+	Students   []entities.Student
+	NextCursor string
+}
+
+type StudentsSearchUseCases interface {
+	SearchStudents(ctx context.Context, input SearchStudentsInput) (StudentsPage, error)
+}
+
+type ExportStudentDataInput struct {
+	StudentID string
+	Actor     string
+}
+
+// StudentDataExport has every personal data kept about a student, as the LGPD data subject access right requires.
+type StudentDataExport struct {
+	GeneratedAt time.Time
+	Student     entities.Student
+	Courses     []entities.Course
+	Sessions    []entities.Token
+	Logins      []entities.LoginAttempt
+	Events      []entities.StudentEvent
+}
+
+type DataExportUseCases interface {
+	ExportStudentData(ctx context.Context, input ExportStudentDataInput) (StudentDataExport, error)
+}
+
+type EraseStudentInput struct {
+	StudentID string
+	Actor     string
+}
+
+type StudentErasureReport struct {
+	Erasure         entities.StudentErasure
+	RevokedSessions int
+}
+
+type StudentErasureUseCases interface {
+	EraseStudent(ctx context.Context, input EraseStudentInput) (StudentErasureReport, error)
+}
+
+// ListDeadLettersInput pages through the dead letters, Limit defaults to 20, up to 100, and Topic filters by topic.
+type ListDeadLettersInput struct {
+	Topic  string
+	Cursor string
+	Limit  int
+}
+
+// DeadLettersPage has the dead letters from the oldest failure, NextCursor is empty when there are no more.
+type DeadLettersPage struct {
+	DeadLetters []entities.DeadLetter
+	NextCursor  string
+}
+
+// DeadLetterInput identifies the dead letter to retry or discard, Actor is recorded in the audit log.
+type DeadLetterInput struct {
+	ID    string
+	Actor string
+}
+
+type DeadLettersUseCases interface {
+	ListDeadLetters(ctx context.Context, input ListDeadLettersInput) (DeadLettersPage, error)
+	// RetryDeadLetter publishes the dead letter again, it is only deleted once published.
+	RetryDeadLetter(ctx context.Context, input DeadLetterInput) error
+	DiscardDeadLetter(ctx context.Context, input DeadLetterInput) error
 }
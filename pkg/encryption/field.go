@@ -0,0 +1,92 @@
+// Package encryption seals personal data fields with AES-256-GCM, either with a key of their own that can be
+// destroyed to make them unreadable, or with data keys wrapped by a master key, see Keyring.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size of the keys, in bytes, AES-256 is used.
+const KeySize = 32
+
+// fieldPrefix tells a sealed field apart from a plain one and the scheme it was sealed with.
+const fieldPrefix = "enc:v1:"
+
+var (
+	ErrInvalidKey     = errors.New("invalid encryption key")
+	ErrMalformedField = errors.New("malformed encrypted field")
+)
+
+// NewKey generates a random key.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SealField encrypts the value, the additional data is authenticated but not encrypted, it binds the sealed
+// value to its context, like the student it belongs to, so it can not be moved around.
+func SealField(key []byte, value string, additionalData string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(additionalData))
+	return fieldPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenField decrypts a value sealed by SealField with the same key and additional data.
+func OpenField(key []byte, field string, additionalData string) (string, error) {
+	if !IsSealed(field) {
+		return "", ErrMalformedField
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(field, fieldPrefix))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedField
+	}
+
+	value, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(additionalData))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrMalformedField, err)
+	}
+
+	return string(value), nil
+}
+
+// IsSealed tells if the field was sealed by SealField.
+func IsSealed(field string) bool {
+	return strings.HasPrefix(field, fieldPrefix)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	return cipher.NewGCM(block)
+}
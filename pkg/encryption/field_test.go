@@ -0,0 +1,111 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealField(t *testing.T) {
+	t.Parallel()
+
+	key, err := NewKey()
+	require.NoError(t, err)
+	otherKey, err := NewKey()
+	require.NoError(t, err)
+
+	tt := []struct {
+		name       string
+		openKey    []byte
+		openData   string
+		tamper     func(field string) string
+		wantErr    error
+		wantValues bool
+	}{
+		{
+			name:       "should open the field with the same key and data",
+			openKey:    key,
+			openData:   "201320509911",
+			wantValues: true,
+		},
+		{
+			name:     "should not open the field with another key",
+			openKey:  otherKey,
+			openData: "201320509911",
+			wantErr:  ErrMalformedField,
+		},
+		{
+			name:     "should not open the field bound to another student",
+			openKey:  key,
+			openData: "201320509912",
+			wantErr:  ErrMalformedField,
+		},
+		{
+			name:     "should not open a tampered field",
+			openKey:  key,
+			openData: "201320509911",
+			tamper: func(field string) string {
+				sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(field, fieldPrefix))
+				require.NoError(t, err)
+				// flip a bit of the ciphertext, right after the 12 bytes nonce
+				sealed[12] ^= 1
+				return fieldPrefix + base64.RawStdEncoding.EncodeToString(sealed)
+			},
+			wantErr: ErrMalformedField,
+		},
+		{
+			name:     "should not open a plain field",
+			openKey:  key,
+			openData: "201320509911",
+			tamper: func(string) string {
+				return "Pedro Lopes"
+			},
+			wantErr: ErrMalformedField,
+		},
+		{
+			name:     "should not open with a key of the wrong size",
+			openKey:  key[:16],
+			openData: "201320509911",
+			wantErr:  ErrInvalidKey,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			field, err := SealField(key, "Pedro Lopes", "201320509911")
+			require.NoError(t, err)
+			if tc.tamper != nil {
+				field = tc.tamper(field)
+			}
+
+			// test
+			value, err := OpenField(tc.openKey, field, tc.openData)
+
+			// assert
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.wantValues {
+				assert.Equal(t, "Pedro Lopes", value)
+			}
+		})
+	}
+
+	t.Run("should seal the same value differently every time", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := SealField(key, "Pedro Lopes", "201320509911")
+		require.NoError(t, err)
+		second, err := SealField(key, "Pedro Lopes", "201320509911")
+		require.NoError(t, err)
+
+		assert.True(t, IsSealed(first))
+		assert.NotEqual(t, first, second)
+		assert.False(t, bytes.Contains([]byte(first), []byte("Pedro")))
+	})
+}
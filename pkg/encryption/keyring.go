@@ -0,0 +1,83 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// dataKeyAdditionalData binds the wrapped data keys to their use, so a sealed field can not pass for a key.
+const dataKeyAdditionalData = "data-key"
+
+// Keyring implements envelope encryption: every record is sealed with its own data key, which is stored
+// wrapped by the master key. The master key never seals data itself, so rotating it only means rewrapping
+// the data keys.
+type Keyring struct {
+	masterKey []byte
+	indexKey  []byte
+}
+
+func NewKeyring(masterKey []byte) (Keyring, error) {
+	if len(masterKey) != KeySize {
+		return Keyring{}, ErrInvalidKey
+	}
+
+	// the blind index key is derived instead of being the master key, keeping each key to a single use
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte("blind-index"))
+
+	return Keyring{
+		masterKey: masterKey,
+		indexKey:  mac.Sum(nil),
+	}, nil
+}
+
+// DecodeKey decodes a base64 key, surrounding spaces are ignored so keys can be read from files as they are.
+func DecodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// NewDataKey returns a random data key along with it wrapped by the master key, only the wrapped key is
+// meant to be stored.
+func (k Keyring) NewDataKey() ([]byte, string, error) {
+	key, err := NewKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	wrapped, err := SealField(k.masterKey, string(key), dataKeyAdditionalData)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, wrapped, nil
+}
+
+// UnwrapDataKey opens a data key wrapped by NewDataKey.
+func (k Keyring) UnwrapDataKey(wrapped string) ([]byte, error) {
+	key, err := OpenField(k.masterKey, wrapped, dataKeyAdditionalData)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(key), nil
+}
+
+// BlindIndex is a keyed hash of the value, the same value always gets the same index so it can be looked up
+// and kept unique without being stored. The field keeps equal values of different fields from matching.
+// Values must be normalized by the caller.
+func (k Keyring) BlindIndex(field string, value string) []byte {
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,126 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring_NewDataKey(t *testing.T) {
+	t.Parallel()
+
+	masterKey, err := NewKey()
+	require.NoError(t, err)
+	keyring, err := NewKeyring(masterKey)
+	require.NoError(t, err)
+
+	t.Run("should unwrap the data key", func(t *testing.T) {
+		t.Parallel()
+
+		// test
+		key, wrapped, err := keyring.NewDataKey()
+		require.NoError(t, err)
+		got, err := keyring.UnwrapDataKey(wrapped)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, key, got)
+		assert.NotContains(t, wrapped, base64.RawStdEncoding.EncodeToString(key))
+	})
+
+	t.Run("should not unwrap the data key with another master key", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		otherKey, err := NewKey()
+		require.NoError(t, err)
+		other, err := NewKeyring(otherKey)
+		require.NoError(t, err)
+
+		_, wrapped, err := keyring.NewDataKey()
+		require.NoError(t, err)
+
+		// test
+		_, err = other.UnwrapDataKey(wrapped)
+
+		// assert
+		assert.ErrorIs(t, err, ErrMalformedField)
+	})
+
+	t.Run("should not unwrap a sealed field", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		field, err := SealField(masterKey, "11111111030", "201320509911")
+		require.NoError(t, err)
+
+		// test
+		_, err = keyring.UnwrapDataKey(field)
+
+		// assert
+		assert.ErrorIs(t, err, ErrMalformedField)
+	})
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	t.Parallel()
+
+	masterKey, err := NewKey()
+	require.NoError(t, err)
+	keyring, err := NewKeyring(masterKey)
+	require.NoError(t, err)
+	otherKey, err := NewKey()
+	require.NoError(t, err)
+	other, err := NewKeyring(otherKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, keyring.BlindIndex("cpf", "11111111030"), keyring.BlindIndex("cpf", "11111111030"))
+	assert.NotEqual(t, keyring.BlindIndex("cpf", "11111111030"), keyring.BlindIndex("cpf", "52998224725"))
+	assert.NotEqual(t, keyring.BlindIndex("cpf", "11111111030"), keyring.BlindIndex("email", "11111111030"))
+	assert.NotEqual(t, keyring.BlindIndex("cpf", "11111111030"), other.BlindIndex("cpf", "11111111030"))
+}
+
+func TestDecodeKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := NewKey()
+	require.NoError(t, err)
+
+	tt := []struct {
+		name    string
+		encoded string
+		want    []byte
+		wantErr error
+	}{
+		{
+			name:    "should decode key with a trailing new line",
+			encoded: base64.StdEncoding.EncodeToString(key) + "\n",
+			want:    key,
+		},
+		{
+			name:    "should fail due to invalid base64",
+			encoded: "not a key",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "should fail due to short key",
+			encoded: base64.StdEncoding.EncodeToString(key[:16]),
+			wantErr: ErrInvalidKey,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, err := DecodeKey(tc.encoded)
+
+			// assert
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
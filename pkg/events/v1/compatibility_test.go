@@ -0,0 +1,228 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	// registers the generated protobuf messages
+	_ "github.com/tccav/identity-service/pkg/events/v1/eventspb"
+)
+
+// payloads maps every event type to its payload, a type missing here fails the suite.
+var payloads = map[string]any{
+	TypeStudentRegistered:    StudentRegistered{},
+	TypeStudentStatusChanged: StudentStatusChanged{},
+	TypeStudentErased:        StudentErased{},
+	TypeStudentLoggedIn:      StudentLoggedIn{},
+	TypeLoginFailed:          LoginFailed{},
+	TypeTokenRevoked:         TokenRevoked{},
+}
+
+type schema struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+func loadSchema(t *testing.T, eventType string) schema {
+	t.Helper()
+
+	content, err := Schemas.ReadFile("schemas/" + eventType + ".json")
+	require.NoError(t, err, "every event type must have a schema")
+
+	var s schema
+	require.NoError(t, json.Unmarshal(content, &s))
+	return s
+}
+
+// TestSchemas fails when a payload no longer matches its published schema: a field removed, renamed or
+// retyped breaks the consumers relying on it. New fields must be added to the schema too.
+func TestSchemas(t *testing.T) {
+	t.Parallel()
+
+	entries, err := Schemas.ReadDir("schemas")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		eventType := strings.TrimSuffix(entry.Name(), ".json")
+		assert.Contains(t, payloads, eventType, "schema without payload")
+	}
+
+	for eventType, payload := range payloads {
+		eventType, payload := eventType, payload
+		t.Run(eventType, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			s := loadSchema(t, eventType)
+
+			// test
+			fields := marshalFields(t, populated(reflect.TypeOf(payload)))
+
+			// assert
+			for name, property := range s.Properties {
+				value, ok := fields[name]
+				if assert.True(t, ok, "field %q of the schema was removed from the payload", name) {
+					assert.Equal(t, property.Type, jsonType(value), "field %q changed its type", name)
+				}
+			}
+			for name := range fields {
+				assert.Contains(t, s.Properties, name, "field %q is missing from the schema", name)
+			}
+			for _, name := range s.Required {
+				assert.Contains(t, s.Properties, name, "required field %q is not a property", name)
+			}
+		})
+	}
+}
+
+// TestPublishedEvents decodes events published by earlier releases, kept in testdata by event type. They
+// must still be valid against the schema and survive a round trip through the current payload.
+func TestPublishedEvents(t *testing.T) {
+	t.Parallel()
+
+	for eventType, payload := range payloads {
+		eventType, payload := eventType, payload
+		t.Run(eventType, func(t *testing.T) {
+			t.Parallel()
+
+			s := loadSchema(t, eventType)
+			files, err := filepath.Glob(filepath.Join("testdata", eventType, "*.json"))
+			require.NoError(t, err)
+			require.NotEmpty(t, files, "every event type must have published samples")
+
+			for _, file := range files {
+				content, err := os.ReadFile(file)
+				require.NoError(t, err)
+
+				var published map[string]any
+				require.NoError(t, json.Unmarshal(content, &published))
+				for _, name := range s.Required {
+					assert.Contains(t, published, name, "%s: required field %q missing", file, name)
+				}
+
+				decoder := json.NewDecoder(bytes.NewReader(content))
+				decoder.DisallowUnknownFields()
+				decoded := reflect.New(reflect.TypeOf(payload))
+				require.NoError(t, decoder.Decode(decoded.Interface()), "%s can no longer be decoded", file)
+
+				roundTrip := marshalFields(t, decoded.Elem().Interface())
+				for name, value := range published {
+					assert.Equal(t, value, roundTrip[name], "%s: field %q lost in the round trip", file, name)
+				}
+			}
+		})
+	}
+}
+
+// populated fills every field, so no field is left out by an omitempty.
+func populated(typ reflect.Type) any {
+	value := reflect.New(typ).Elem()
+	for i := 0; i < typ.NumField(); i++ {
+		field := value.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString("x")
+		case reflect.Bool:
+			field.SetBool(true)
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			field.SetInt(1)
+		case reflect.Float32, reflect.Float64:
+			field.SetFloat(1.5)
+		}
+	}
+	return value.Interface()
+}
+
+func marshalFields(t *testing.T, payload any) map[string]any {
+	t.Helper()
+
+	content, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(content, &fields))
+	return fields
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+// TestBinarySchemas fails when the Avro or Protobuf schema of a payload lacks a field of the payload or
+// describes one it does not have, the payload fields are all strings.
+func TestBinarySchemas(t *testing.T) {
+	t.Parallel()
+
+	for eventType, payload := range payloads {
+		eventType, payload := eventType, payload
+		t.Run(eventType, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			fields := marshalFields(t, populated(reflect.TypeOf(payload)))
+			want := make([]string, 0, len(fields))
+			for name := range fields {
+				want = append(want, name)
+			}
+
+			content, err := AvroSchemas.ReadFile("avro/" + eventType + ".avsc")
+			require.NoError(t, err, "every event type must have an avro schema")
+			var avroSchema struct {
+				Fields []struct {
+					Name string `json:"name"`
+					Type string `json:"type"`
+				} `json:"fields"`
+			}
+			require.NoError(t, json.Unmarshal(content, &avroSchema))
+
+			file, err := protoregistry.GlobalFiles.FindFileByPath(eventType + ".proto")
+			require.NoError(t, err, "every event type must have a generated protobuf message")
+			require.Equal(t, 1, file.Messages().Len(), "every protobuf schema holds a single message")
+			message := file.Messages().Get(0)
+
+			// test
+			avroFields := make([]string, 0, len(avroSchema.Fields))
+			for _, field := range avroSchema.Fields {
+				assert.Equal(t, "string", field.Type, "avro field %q changed its type", field.Name)
+				avroFields = append(avroFields, field.Name)
+			}
+			protobufFields := make([]string, 0, message.Fields().Len())
+			for i := 0; i < message.Fields().Len(); i++ {
+				field := message.Fields().Get(i)
+				assert.Equal(t, protoreflect.StringKind, field.Kind(), "protobuf field %q changed its type", field.Name())
+				protobufFields = append(protobufFields, string(field.Name()))
+			}
+
+			// assert
+			assert.ElementsMatch(t, want, avroFields)
+			assert.ElementsMatch(t, want, protobufFields)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package events has the payloads of the events published by the identity service, in the version 1 of their
+// schemas. Events follow the CloudEvents 1.0 Kafka binding in binary mode: the attributes are sent as ce_*
+// headers and the record value is the payload, JSON by default. Payloads can be published in Avro or Protobuf
+// as well, framed in the Confluent wire format with the ID of their schema in the registry.
+//
+// Payloads only change in backward compatible ways within a version, fields are added but never removed,
+// renamed nor retyped. Breaking changes go to a new version package.
+package events
+
+import (
+	"embed"
+	"time"
+)
+
+const (
+	SpecVersion             = "1.0"
+	Source                  = "identity-service"
+	DataContentType         = "application/json"
+	AvroDataContentType     = "application/avro"
+	ProtobufDataContentType = "application/protobuf"
+)
+
+// Event types, the same ones published before the CloudEvents attributes were added.
+const (
+	TypeStudentRegistered    = "student_registered"
+	TypeStudentStatusChanged = "student_status_changed"
+	TypeStudentErased        = "student_erased"
+)
+
+// Authentication activity event types, published to their own topic.
+const (
+	TypeStudentLoggedIn = "student_logged_in"
+	TypeLoginFailed     = "login_failed"
+	TypeTokenRevoked    = "token_revoked"
+)
+
+// schemasURL is where the schemas of this version are published, see the schemas, avro and proto directories.
+const schemasURL = "https://github.com/tccav/identity-service/blob/main/pkg/events/v1/"
+
+// Schemas has the JSON schema of each event type payload, named after the type, for consumers that validate
+// the events they receive.
+//
+//go:embed schemas/*.json
+var Schemas embed.FS
+
+// AvroSchemas has the Avro schema of each event type payload, named after the type with the avsc extension.
+//
+//go:embed avro/*.avsc
+var AvroSchemas embed.FS
+
+// ProtobufSchemas has the Protobuf message of each event type payload, named after the type with the proto
+// extension. Each file holds a single message, generated to the eventspb package.
+//
+//go:embed proto/*.proto
+var ProtobufSchemas embed.FS
+
+//go:generate protoc --proto_path=proto --go_out=eventspb --go_opt=paths=source_relative proto/*.proto
+
+// DataSchema is the dataschema attribute of the event type.
+func DataSchema(eventType string) string {
+	return schemasURL + "schemas/" + eventType + ".json"
+}
+
+// AvroDataSchema is the dataschema attribute of the event type published in Avro.
+func AvroDataSchema(eventType string) string {
+	return schemasURL + "avro/" + eventType + ".avsc"
+}
+
+// ProtobufDataSchema is the dataschema attribute of the event type published in Protobuf.
+func ProtobufDataSchema(eventType string) string {
+	return schemasURL + "proto/" + eventType + ".proto"
+}
+
+// Attributes are the CloudEvents attributes of an event, Subject is the ID of the student it is about. Replay
+// is the replay extension attribute: the ID of the replay that republished the event from the stored
+// students, empty on events published as they happened. Consumers that already hold the student state can
+// skip replayed events or apply them idempotently.
+type Attributes struct {
+	ID              string
+	Type            string
+	Source          string
+	Subject         string
+	Time            time.Time
+	DataSchema      string
+	DataContentType string
+	SpecVersion     string
+	Replay          string
+}
+
+// StudentRegistered has the student personal data sealed by the student key identified by PIIKeyID, bound
+// to the student ID. The key is destroyed when the student is erased.
+type StudentRegistered struct {
+	StudentID string `json:"student_id"`
+	Name      string `json:"name"`
+	CPF       string `json:"cpf"`
+	Email     string `json:"email"`
+	BirthDate string `json:"birth_date"`
+	CourseID  string `json:"course_id"`
+	Status    string `json:"status"`
+	PIIKeyID  string `json:"pii_key_id"`
+}
+
+type StudentStatusChanged struct {
+	StudentID      string `json:"student_id"`
+	PreviousStatus string `json:"previous_status"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason"`
+	ChangedAt      string `json:"changed_at"`
+}
+
+// StudentErased is the tombstone of an erased student, consumers must drop every data they keep about it.
+type StudentErased struct {
+	StudentID string `json:"student_id"`
+	ErasedAt  string `json:"erased_at"`
+}
+
+// StudentLoggedIn is published on every login, IP and UserAgent are the ones of the client that logged in.
+type StudentLoggedIn struct {
+	StudentID  string `json:"student_id"`
+	TokenID    string `json:"token_id"`
+	Outcome    string `json:"outcome"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// LoginFailed Outcome is why the login was refused.
+type LoginFailed struct {
+	StudentID  string `json:"student_id"`
+	Outcome    string `json:"outcome"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// TokenRevoked Outcome is why the token was revoked, an empty TokenID means every token of the student was.
+type TokenRevoked struct {
+	StudentID  string `json:"student_id"`
+	TokenID    string `json:"token_id"`
+	Outcome    string `json:"outcome"`
+	IP         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	OccurredAt string `json:"occurred_at"`
+}
@@ -0,0 +1,158 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: student_erased.proto
+
+package eventspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A student personal data was erased, consumers must drop every data they keep about it.
+type StudentErased struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StudentId string `protobuf:"bytes,1,opt,name=student_id,json=studentId,proto3" json:"student_id,omitempty"`
+	ErasedAt  string `protobuf:"bytes,2,opt,name=erased_at,json=erasedAt,proto3" json:"erased_at,omitempty"`
+}
+
+func (x *StudentErased) Reset() {
+	*x = StudentErased{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_student_erased_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StudentErased) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentErased) ProtoMessage() {}
+
+func (x *StudentErased) ProtoReflect() protoreflect.Message {
+	mi := &file_student_erased_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentErased.ProtoReflect.Descriptor instead.
+func (*StudentErased) Descriptor() ([]byte, []int) {
+	return file_student_erased_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StudentErased) GetStudentId() string {
+	if x != nil {
+		return x.StudentId
+	}
+	return ""
+}
+
+func (x *StudentErased) GetErasedAt() string {
+	if x != nil {
+		return x.ErasedAt
+	}
+	return ""
+}
+
+var File_student_erased_proto protoreflect.FileDescriptor
+
+var file_student_erased_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x65, 0x72, 0x61, 0x73, 0x65, 0x64,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x18, 0x74, 0x63, 0x63, 0x61, 0x76, 0x2e, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x76, 0x31,
+	0x22, 0x4b, 0x0a, 0x0d, 0x53, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x45, 0x72, 0x61, 0x73, 0x65,
+	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x75, 0x64, 0x65, 0x6e, 0x74, 0x49, 0x64,
+	0x12, 0x1b, 0x0a, 0x09, 0x65, 0x72, 0x61, 0x73, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x72, 0x61, 0x73, 0x65, 0x64, 0x41, 0x74, 0x42, 0x3a, 0x5a,
+	0x38, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x63, 0x63, 0x61,
+	0x76, 0x2f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x2f, 0x76, 0x31,
+	0x2f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_student_erased_proto_rawDescOnce sync.Once
+	file_student_erased_proto_rawDescData = file_student_erased_proto_rawDesc
+)
+
+func file_student_erased_proto_rawDescGZIP() []byte {
+	file_student_erased_proto_rawDescOnce.Do(func() {
+		file_student_erased_proto_rawDescData = protoimpl.X.CompressGZIP(file_student_erased_proto_rawDescData)
+	})
+	return file_student_erased_proto_rawDescData
+}
+
+var file_student_erased_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_student_erased_proto_goTypes = []interface{}{
+	(*StudentErased)(nil), // 0: tccav.identity.events.v1.StudentErased
+}
+var file_student_erased_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_student_erased_proto_init() }
+func file_student_erased_proto_init() {
+	if File_student_erased_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_student_erased_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StudentErased); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_student_erased_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_student_erased_proto_goTypes,
+		DependencyIndexes: file_student_erased_proto_depIdxs,
+		MessageInfos:      file_student_erased_proto_msgTypes,
+	}.Build()
+	File_student_erased_proto = out.File
+	file_student_erased_proto_rawDesc = nil
+	file_student_erased_proto_goTypes = nil
+	file_student_erased_proto_depIdxs = nil
+}
@@ -0,0 +1,196 @@
+// Package dataexport writes a student data export in a machine-readable format, either a single JSON document
+// or a ZIP archive with one file per kind of data.
+package dataexport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const (
+	FormatJSON = "json"
+	FormatZIP  = "zip"
+)
+
+var ErrUnknownFormat = errors.New("unknown data export format")
+
+type Document struct {
+	GeneratedAt string    `json:"generated_at" swaggertype:"string" format:"datetime" example:"2023-07-16T10:00:00Z"`
+	Student     Student   `json:"student"`
+	Courses     []Course  `json:"courses"`
+	Sessions    []Session `json:"sessions"`
+	Logins      []Login   `json:"logins"`
+	Events      []Event   `json:"events"`
+}
+
+type Student struct {
+	ID           string `json:"id" swaggertype:"string" example:"201210204310"`
+	Name         string `json:"name" swaggertype:"string" example:"John Doe"`
+	CPF          string `json:"cpf" swaggertype:"string" example:"11111111030"`
+	Email        string `json:"email" swaggertype:"string" example:"jdoe@ol.com"`
+	BirthDate    string `json:"birth_date" swaggertype:"string" format:"date" example:"1990-10-18"`
+	Status       string `json:"status" swaggertype:"string" example:"active"`
+	CourseID     string `json:"course_id,omitempty" swaggertype:"string" example:"1f6a4d3a-38c7-43fe-9790-2408fe595c93"`
+	RegisteredAt string `json:"registered_at" swaggertype:"string" format:"datetime" example:"2023-07-09T12:00:00Z"`
+}
+
+type Course struct {
+	ID     string `json:"id" swaggertype:"string" example:"1f6a4d3a-38c7-43fe-9790-2408fe595c93"`
+	Name   string `json:"name,omitempty" swaggertype:"string" example:"Ciência da Computação"`
+	Status string `json:"status,omitempty" swaggertype:"string" example:"open"`
+}
+
+type Session struct {
+	TokenID   string `json:"token_id" swaggertype:"string" example:"0b7c5e0e-6c4a-4f4e-9d4c-0f0a3c3f4b1e"`
+	ExpiresAt string `json:"expires_at" swaggertype:"string" format:"datetime" example:"2023-07-16T13:00:00Z"`
+}
+
+type Login struct {
+	Succeeded     bool   `json:"succeeded" swaggertype:"boolean" example:"false"`
+	FailureReason string `json:"failure_reason,omitempty" swaggertype:"string" example:"invalid_credentials"`
+	AttemptedAt   string `json:"attempted_at" swaggertype:"string" format:"datetime" example:"2023-07-16T10:00:00Z"`
+}
+
+type Event struct {
+	ID          string          `json:"event_id" swaggertype:"string" example:"6f0c1c4e-2b8e-4a8e-8f5d-3b0b7b8e9f10"`
+	Type        string          `json:"event_type" swaggertype:"string" example:"student_registered"`
+	Topic       string          `json:"topic" swaggertype:"string" example:"identity.cdc.students.0"`
+	PublishedAt string          `json:"published_at" swaggertype:"string" format:"datetime" example:"2023-07-09T12:00:00Z"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+func NewDocument(export identities.StudentDataExport) Document {
+	student := export.Student
+	doc := Document{
+		GeneratedAt: export.GeneratedAt.Format(time.RFC3339),
+		Student: Student{
+			ID:           student.ID,
+			Name:         student.Name,
+			CPF:          student.CPF,
+			Email:        student.Email,
+			BirthDate:    student.BirthDate.Format(time.DateOnly),
+			Status:       string(student.Status),
+			CourseID:     student.CourseID,
+			RegisteredAt: student.CreatedAt.UTC().Format(time.RFC3339),
+		},
+		Courses:  make([]Course, 0, len(export.Courses)),
+		Sessions: make([]Session, 0, len(export.Sessions)),
+		Logins:   make([]Login, 0, len(export.Logins)),
+		Events:   make([]Event, 0, len(export.Events)),
+	}
+
+	for _, course := range export.Courses {
+		doc.Courses = append(doc.Courses, Course{ID: course.ID, Name: course.Name, Status: string(course.Status)})
+	}
+	for _, token := range export.Sessions {
+		doc.Sessions = append(doc.Sessions, Session{TokenID: token.ID, ExpiresAt: token.ExpirationDate.UTC().Format(time.RFC3339)})
+	}
+	for _, login := range export.Logins {
+		doc.Logins = append(doc.Logins, Login{
+			Succeeded:     login.Succeeded,
+			FailureReason: login.FailureReason,
+			AttemptedAt:   login.AttemptedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	for _, evt := range export.Events {
+		doc.Events = append(doc.Events, Event{
+			ID:          evt.ID,
+			Type:        evt.Type,
+			Topic:       evt.Topic,
+			PublishedAt: evt.PublishedAt.UTC().Format(time.RFC3339),
+			Payload:     evt.Payload,
+		})
+	}
+
+	return doc
+}
+
+// ContentType returns the media type of the format.
+func ContentType(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return "application/json", nil
+	case FormatZIP:
+		return "application/zip", nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// FileName names the export file after the student and the export date.
+func FileName(export identities.StudentDataExport, format string) string {
+	return fmt.Sprintf("student-%s-data-%s.%s", export.Student.ID, export.GeneratedAt.Format("20060102T150405Z"), strings.ToLower(format))
+}
+
+// Write writes the export in the informed format.
+func Write(w io.Writer, export identities.StudentDataExport, format string) error {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(NewDocument(export))
+	case FormatZIP:
+		return writeZIP(w, NewDocument(export))
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// writeZIP splits the document in one file per kind of data, along with a manifest listing them.
+func writeZIP(w io.Writer, doc Document) error {
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"student.json", doc.Student},
+		{"courses.json", doc.Courses},
+		{"sessions.json", doc.Sessions},
+		{"logins.json", doc.Logins},
+		{"events.json", doc.Events},
+	}
+
+	manifest := struct {
+		GeneratedAt string   `json:"generated_at"`
+		StudentID   string   `json:"student_id"`
+		Files       []string `json:"files"`
+	}{
+		GeneratedAt: doc.GeneratedAt,
+		StudentID:   doc.Student.ID,
+	}
+	for _, file := range files {
+		manifest.Files = append(manifest.Files, file.name)
+	}
+
+	archive := zip.NewWriter(w)
+	err := writeZIPFile(archive, "manifest.json", manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		err = writeZIPFile(archive, file.name, file.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func writeZIPFile(archive *zip.Writer, name string, content any) error {
+	f, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(content)
+}
@@ -0,0 +1,105 @@
+package dataexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+var export = identities.StudentDataExport{
+	GeneratedAt: time.Date(2023, 7, 16, 10, 0, 0, 0, time.UTC),
+	Student: entities.Student{
+		ID:        "201116548712",
+		Name:      "John Doe",
+		CPF:       "11111111030",
+		Email:     "jdoe@ol.com",
+		BirthDate: time.Date(1994, 3, 19, 0, 0, 0, 0, time.UTC),
+		Status:    entities.StudentStatusActive,
+		CourseID:  "1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10",
+		CreatedAt: time.Date(2023, 7, 9, 12, 0, 0, 0, time.UTC),
+	},
+	Courses: []entities.Course{{ID: "1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10", Name: "Ciência da Computação", Status: entities.CourseStatusOpen}},
+	Logins:  []entities.LoginAttempt{{StudentID: "201116548712", FailureReason: entities.LoginFailureInvalidCredentials, AttemptedAt: time.Date(2023, 7, 15, 8, 0, 0, 0, time.UTC)}},
+	Events: []entities.StudentEvent{{
+		ID:          "6f0c1c4e-2b8e-4a8e-8f5d-3b0b7b8e9f10",
+		Type:        "student_registered",
+		Topic:       "identity.cdc.students.0",
+		PublishedAt: time.Date(2023, 7, 9, 12, 0, 0, 0, time.UTC),
+		Payload:     []byte(`{"student_id":"201116548712"}`),
+	}},
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should write json document", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, export, "JSON"))
+
+		var got Document
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		want := NewDocument(export)
+		assertEvents(t, want.Events, got.Events)
+		want.Events, got.Events = nil, nil
+		assert.Equal(t, want, got)
+		assert.Equal(t, "1994-03-19", got.Student.BirthDate)
+		assert.Empty(t, got.Sessions)
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("should write zip with one file per data kind", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, export, FormatZIP))
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(archive.File))
+		for _, f := range archive.File {
+			names = append(names, f.Name)
+		}
+		assert.Equal(t, []string{"manifest.json", "student.json", "courses.json", "sessions.json", "logins.json", "events.json"}, names)
+
+		f, err := archive.Open("events.json")
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+
+		var events []Event
+		require.NoError(t, json.Unmarshal(content, &events))
+		assertEvents(t, NewDocument(export).Events, events)
+	})
+
+	t.Run("should refuse unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorIs(t, Write(io.Discard, export, "xml"), ErrUnknownFormat)
+	})
+}
+
+// assertEvents compares the events payloads by their content, since the documents are indented.
+func assertEvents(t *testing.T, want []Event, got []Event) {
+	t.Helper()
+
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.JSONEq(t, string(want[i].Payload), string(got[i].Payload))
+
+		w, g := want[i], got[i]
+		w.Payload, g.Payload = nil, nil
+		assert.Equal(t, w, g)
+	}
+}
@@ -0,0 +1,83 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAdminAuthorizer_RequireScope(t *testing.T) {
+	t.Parallel()
+
+	keys := map[string]string{
+		"reader-key": ScopeStudentsRead,
+		"admin-key":  ScopeStudentsRead + "|" + ScopeStudentsWrite,
+	}
+
+	tt := []struct {
+		name             string
+		apiKey           string
+		expectedResponse any
+		expectedStatus   int
+	}{
+		{
+			name:           "should let request with scope through",
+			apiKey:         "admin-key",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:             "should fail because api key is missing",
+			expectedResponse: accessUnauthorized,
+			expectedStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:             "should fail because api key is unknown",
+			apiKey:           "unknown-key",
+			expectedResponse: accessUnauthorized,
+			expectedStatus:   http.StatusUnauthorized,
+		},
+		{
+			name:             "should fail because api key lacks scope",
+			apiKey:           "reader-key",
+			expectedResponse: accessForbidden,
+			expectedStatus:   http.StatusForbidden,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			a := NewAdminAuthorizer(zap.NewNop(), keys)
+			handler := a.RequireScope(ScopeStudentsWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, keyFingerprint(tc.apiKey), AdminActor(r.Context()))
+				assert.NotContains(t, AdminActor(r.Context()), tc.apiKey)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/identities/students/123451271/status", nil)
+			if tc.apiKey != "" {
+				r.Header.Set(adminKeyHeader, tc.apiKey)
+			}
+
+			// test
+			handler.ServeHTTP(w, r)
+
+			// assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedResponse != nil {
+				expectedResponse, err := json.Marshal(expectedPayload(tc.expectedResponse, tc.expectedStatus))
+				require.NoError(t, err)
+				assert.Equal(t, string(expectedResponse), strings.TrimSpace(w.Body.String()))
+			}
+		})
+	}
+}
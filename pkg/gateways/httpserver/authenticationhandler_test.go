@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -37,6 +38,7 @@ func TestAuthenticationHandler_AuthenticateStudent(t *testing.T) {
 		requestBody      string
 		expectedUC       entities.Token
 		expectedUCErr    error
+		expectedAudience string
 		expectedResponse any
 		expectedStatus   int
 	}{
@@ -71,6 +73,14 @@ func TestAuthenticationHandler_AuthenticateStudent(t *testing.T) {
 			expectedStatus:   http.StatusBadRequest,
 			expectedResponse: emptySecret,
 		},
+		{
+			name:             "should fail because audience is not a registered service",
+			requestBody:      `{"student_id": "1234678910", "secret": "123467", "audience": "unknown-service"}`,
+			expectedUCErr:    identities.ErrUnknownAudience,
+			expectedAudience: "unknown-service",
+			expectedStatus:   http.StatusBadRequest,
+			expectedResponse: unknownAudience,
+		},
 		{
 			name:             "should fail because student is not registered",
 			requestBody:      hsfixtures.ValidStudentLoginRequestBody,
@@ -85,6 +95,20 @@ func TestAuthenticationHandler_AuthenticateStudent(t *testing.T) {
 			expectedStatus:   http.StatusBadRequest,
 			expectedResponse: invalidCredentials,
 		},
+		{
+			name:             "should fail because student is suspended",
+			requestBody:      hsfixtures.ValidStudentLoginRequestBody,
+			expectedUCErr:    identities.ErrStudentSuspended,
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: studentSuspended,
+		},
+		{
+			name:             "should fail because student is cancelled",
+			requestBody:      hsfixtures.ValidStudentLoginRequestBody,
+			expectedUCErr:    identities.ErrStudentCancelled,
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: studentCancelled,
+		},
 		{
 			name:             "should fail because an unexpected error happened",
 			requestBody:      hsfixtures.ValidStudentLoginRequestBody,
@@ -110,7 +134,7 @@ func TestAuthenticationHandler_AuthenticateStudent(t *testing.T) {
 				},
 			}
 
-			expectedResponse, err := json.Marshal(tc.expectedResponse)
+			expectedResponse, err := json.Marshal(expectedPayload(tc.expectedResponse, tc.expectedStatus))
 			require.NoError(t, err)
 
 			w := httptest.NewRecorder()
@@ -118,8 +142,10 @@ func TestAuthenticationHandler_AuthenticateStudent(t *testing.T) {
 				http.MethodPost,
 				"/v1/identities/students/login",
 				bytes.NewReader([]byte(tc.requestBody)))
+			r.RemoteAddr = "200.20.10.5:52431"
+			r.Header.Set("User-Agent", "Mozilla/5.0")
 
-			h := NewAuthenticationHandler(logger, &useCase)
+			h := NewAuthenticationHandler(logger, &useCase, ClientIPs{})
 
 			// test
 			h.AuthenticateStudent(w, r)
@@ -127,23 +153,51 @@ func TestAuthenticationHandler_AuthenticateStudent(t *testing.T) {
 			// assert
 			assert.Equal(t, string(expectedResponse), strings.TrimSpace(w.Body.String()))
 			assert.Equal(t, tc.expectedStatus, w.Code)
+			for _, call := range useCase.AuthenticateStudentCalls() {
+				assert.Equal(t, entities.AuthenticationClient{IP: "200.20.10.5", UserAgent: "Mozilla/5.0"}, call.Input.Client)
+				assert.Equal(t, tc.expectedAudience, call.Input.Audience)
+			}
 		})
 	}
 }
 
 func TestAuthenticationHandler_VerifyAuthentication(t *testing.T) {
+	verifiedToken := entities.Token{
+		ID:             uuid.NewString(),
+		UserID:         "12345678910",
+		Audience:       "courses-service",
+		ExpirationDate: time.Date(2023, 10, 18, 19, 32, 0, 0, time.UTC),
+		Claims: entities.TokenClaims{
+			Name:      "Maria Silva",
+			CourseIDs: []string{"1"},
+			Status:    entities.StudentStatusActive,
+		},
+	}
+
 	tt := []struct {
 		name             string
 		authHeader       string
+		audience         string
 		expectedUCErr    error
 		expectedStatus   int
 		expectedResponse any
 	}{
 		{
-			name:             "should successfully verify authenticated student",
-			authHeader:       hsfixtures.ValidAuthHeader,
-			expectedStatus:   http.StatusOK,
-			expectedResponse: "",
+			name:           "should successfully verify authenticated student",
+			authHeader:     hsfixtures.ValidAuthHeader,
+			audience:       "courses-service",
+			expectedStatus: http.StatusOK,
+			expectedResponse: VerifyAuthenticationResponse{
+				StudentID: verifiedToken.UserID,
+				TokenID:   verifiedToken.ID,
+				ExpiresAt: "2023-10-18T19:32:00Z",
+				Audience:  "courses-service",
+				Claims: TokenClaims{
+					Name:      "Maria Silva",
+					CourseIDs: []string{"1"},
+					Status:    "active",
+				},
+			},
 		},
 		{
 			name:             "should fail because auth header is malformed",
@@ -158,6 +212,21 @@ func TestAuthenticationHandler_VerifyAuthentication(t *testing.T) {
 			expectedStatus:   http.StatusForbidden,
 			expectedResponse: accessForbidden,
 		},
+		{
+			name:             "should fail because token was issued to another audience",
+			authHeader:       hsfixtures.ValidAuthHeader,
+			audience:         "grades-service",
+			expectedUCErr:    identities.ErrWrongAudience,
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: accessForbidden,
+		},
+		{
+			name:             "should fail because token was revoked",
+			authHeader:       hsfixtures.ValidAuthHeader,
+			expectedUCErr:    identities.ErrTokenRevoked,
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: accessForbidden,
+		},
 		{
 			name:             "should fail token is malformed",
 			authHeader:       "Bearer not_jwt",
@@ -172,6 +241,20 @@ func TestAuthenticationHandler_VerifyAuthentication(t *testing.T) {
 			expectedStatus:   http.StatusUnauthorized,
 			expectedResponse: accessUnauthorized,
 		},
+		{
+			name:             "should fail because student was suspended",
+			authHeader:       hsfixtures.ValidAuthHeader,
+			expectedUCErr:    identities.ErrStudentSuspended,
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: studentSuspended,
+		},
+		{
+			name:             "should fail because student was cancelled",
+			authHeader:       hsfixtures.ValidAuthHeader,
+			expectedUCErr:    identities.ErrStudentCancelled,
+			expectedStatus:   http.StatusForbidden,
+			expectedResponse: studentCancelled,
+		},
 		{
 			name:             "should fail because an unexpected error occurred",
 			authHeader:       hsfixtures.ValidAuthHeader,
@@ -189,27 +272,30 @@ func TestAuthenticationHandler_VerifyAuthentication(t *testing.T) {
 			logger := zap.NewNop()
 
 			useCase := idmocks.AuthenticationUseCasesMock{
-				VerifyAuthFunc: func(ctx context.Context, hash string) error {
-					return tc.expectedUCErr
+				VerifyAuthFunc: func(ctx context.Context, hash string, audience string) (entities.Token, error) {
+					if tc.expectedUCErr != nil {
+						return entities.Token{}, tc.expectedUCErr
+					}
+					return verifiedToken, nil
 				},
 			}
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(
 				http.MethodPost,
-				"/v1/identities/students/verify-auth",
+				"/v1/identities/students/verify-auth?audience="+url.QueryEscape(tc.audience),
 				nil)
 
 			r.Header.Add("authorization", tc.authHeader)
 
-			h := NewAuthenticationHandler(logger, &useCase)
+			h := NewAuthenticationHandler(logger, &useCase, ClientIPs{})
 
 			// test
 			h.VerifyAuthentication(w, r)
 
 			// assert
 			if tc.expectedResponse != "" {
-				expectedResponse, err := json.Marshal(tc.expectedResponse)
+				expectedResponse, err := json.Marshal(expectedPayload(tc.expectedResponse, tc.expectedStatus))
 				require.NoError(t, err)
 
 				assert.Equal(t, string(expectedResponse), strings.TrimSpace(w.Body.String()))
@@ -217,6 +303,9 @@ func TestAuthenticationHandler_VerifyAuthentication(t *testing.T) {
 				assert.Empty(t, strings.TrimSpace(w.Body.String()))
 			}
 			assert.Equal(t, tc.expectedStatus, w.Code)
+			for _, call := range useCase.VerifyAuthCalls() {
+				assert.Equal(t, tc.audience, call.Audience)
+			}
 		})
 	}
 }
@@ -0,0 +1,88 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+var ErrInvalidTrustedProxy = errors.New("invalid trusted proxy")
+
+// ClientIPs tells the IP of the client behind a request. Only the requests coming from a trusted proxy have
+// their X-Forwarded-For header read, as anyone else could forge it. The header is read from its right, the
+// addresses appended by the trusted proxies skipped, since the ones at its left are sent by the client.
+type ClientIPs struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewClientIPs takes the trusted proxies as IP addresses or CIDR ranges, without any the remote address of the
+// requests is their client IP.
+func NewClientIPs(trustedProxies []string) (ClientIPs, error) {
+	prefixes := make([]netip.Prefix, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return ClientIPs{}, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return ClientIPs{}, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return ClientIPs{
+		trustedProxies: prefixes,
+	}, nil
+}
+
+func (c ClientIPs) ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if !c.trusted(ip) {
+		return ip
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if hop == "" {
+			continue
+		}
+		if _, err := netip.ParseAddr(hop); err != nil {
+			// a malformed hop was not appended by a trusted proxy, the client IP is not known past it
+			break
+		}
+		ip = hop
+		if !c.trusted(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (c ClientIPs) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, prefix := range c.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPs_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string
+		expectedIP     string
+	}{
+		{
+			name:       "should take the remote address without trusted proxies",
+			remoteAddr: "200.20.10.5:52431",
+			expectedIP: "200.20.10.5",
+		},
+		{
+			name:         "should ignore forwarded ips sent by anyone but a trusted proxy",
+			remoteAddr:   "200.20.10.5:52431",
+			forwardedFor: []string{"10.0.0.1"},
+			expectedIP:   "200.20.10.5",
+		},
+		{
+			name:           "should take the ip forwarded by a trusted proxy",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.7:52431",
+			forwardedFor:   []string{"200.20.10.5"},
+			expectedIP:     "200.20.10.5",
+		},
+		{
+			name:           "should skip the trusted proxies and the ips forged by the client",
+			trustedProxies: []string{"10.0.0.7", "10.1.0.0/16"},
+			remoteAddr:     "10.0.0.7:52431",
+			forwardedFor:   []string{"1.1.1.1, 200.20.10.5", "10.1.0.3"},
+			expectedIP:     "200.20.10.5",
+		},
+		{
+			name:           "should stop at a malformed forwarded ip",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.7:52431",
+			forwardedFor:   []string{"200.20.10.5, unknown, 10.0.0.3"},
+			expectedIP:     "10.0.0.3",
+		},
+		{
+			name:           "should take the remote address of a trusted proxy that forwarded nothing",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.7:52431",
+			expectedIP:     "10.0.0.7",
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			clientIPs, err := NewClientIPs(tc.trustedProxies)
+			require.NoError(t, err)
+
+			r := httptest.NewRequest(http.MethodPost, "/v1/identities/students/login", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for _, forwarded := range tc.forwardedFor {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			// test
+			got := clientIPs.ClientIP(r)
+
+			// assert
+			assert.Equal(t, tc.expectedIP, got)
+		})
+	}
+}
+
+func TestNewClientIPs(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClientIPs([]string{"10.0.0.0/33"})
+	assert.ErrorIs(t, err, ErrInvalidTrustedProxy)
+
+	_, err = NewClientIPs([]string{"gateway"})
+	assert.ErrorIs(t, err, ErrInvalidTrustedProxy)
+}
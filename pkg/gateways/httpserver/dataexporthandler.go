@@ -0,0 +1,102 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/dataexport"
+)
+
+type DataExportHandler struct {
+	logger  *zap.Logger
+	useCase identities.DataExportUseCases
+}
+
+func NewDataExportHandler(logger *zap.Logger, useCase identities.DataExportUseCases) DataExportHandler {
+	return DataExportHandler{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+// ExportStudentData ...
+// ShowEntity godoc
+// @Summary Export every personal data of a student
+// @Description LGPD data subject access export, with the student data, courses, active sessions, login history
+// @Description and the events published about the student. Every export is audited.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.students.export scope"
+// @Param id path string true "Student ID"
+// @Param format query string false "Export format" Enums(json, zip) default(json)
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,application/zip,application/problem+json
+// @Success 200 {object} dataexport.Document
+// @Failure 400 {object} HTTPError
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 404 {object} HTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/students/{id}/data-export [get]
+func (h DataExportHandler) ExportStudentData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = dataexport.FormatJSON
+	}
+
+	contentType, err := dataexport.ContentType(format)
+	if err != nil {
+		h.logger.Error("invalid export format received", zap.Error(err))
+		err = sendProblem(w, r, http.StatusBadRequest, invalidExportFormat)
+		if err != nil {
+			h.logger.Error("failed to send error response", zap.Error(err))
+		}
+		return
+	}
+
+	export, err := h.useCase.ExportStudentData(ctx, identities.ExportStudentDataInput{
+		StudentID: chi.URLParam(r, "id"),
+		Actor:     AdminActor(ctx),
+	})
+	if err != nil {
+		h.logger.Error("unable to export student data", zap.Error(err))
+
+		var (
+			errorPayload problem
+			statusCode   int
+		)
+		switch {
+		case errors.Is(err, identities.ErrEmptyStudentID):
+			statusCode = http.StatusBadRequest
+			errorPayload = emptyStudentID
+		case errors.Is(err, identities.ErrStudentNotFound):
+			statusCode = http.StatusNotFound
+			errorPayload = studentNotFound
+		default:
+			statusCode = http.StatusInternalServerError
+			errorPayload = unexpectedError
+		}
+
+		err = sendProblem(w, r, statusCode, errorPayload)
+		if err != nil {
+			h.logger.Error("failed to send error json response", zap.Error(err))
+		}
+		return
+	}
+
+	w.Header().Add("content-type", contentType)
+	w.Header().Add("content-disposition", fmt.Sprintf("attachment; filename=%q", dataexport.FileName(export, format)))
+	w.Header().Add("cache-control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	err = dataexport.Write(w, export, format)
+	if err != nil {
+		h.logger.Error("failed to send student data export", zap.Error(err))
+	}
+}
@@ -0,0 +1,110 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+)
+
+func TestDataExportHandler_ExportStudentData(t *testing.T) {
+	t.Parallel()
+
+	export := identities.StudentDataExport{
+		GeneratedAt: time.Date(2023, 7, 16, 10, 0, 0, 0, time.UTC),
+		Student:     entities.Student{ID: "123451271", Name: "John Doe"},
+	}
+
+	tt := []struct {
+		name                string
+		query               string
+		expectedUCErr       error
+		expectedResponse    any
+		expectedStatus      int
+		expectedContentType string
+		expectedDisposition string
+	}{
+		{
+			name:                "should send json export by default",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedDisposition: `attachment; filename="student-123451271-data-20230716T100000Z.json"`,
+		},
+		{
+			name:                "should send zip export",
+			query:               "?format=zip",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/zip",
+			expectedDisposition: `attachment; filename="student-123451271-data-20230716T100000Z.zip"`,
+		},
+		{
+			name:                "should fail due to unknown format",
+			query:               "?format=xml",
+			expectedResponse:    invalidExportFormat,
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: problemContentType,
+		},
+		{
+			name:                "should fail because student does not exist",
+			expectedUCErr:       identities.ErrStudentNotFound,
+			expectedResponse:    studentNotFound,
+			expectedStatus:      http.StatusNotFound,
+			expectedContentType: problemContentType,
+		},
+		{
+			name:                "should fail due to unexpected error from use case",
+			expectedUCErr:       errors.New("unexpected"),
+			expectedResponse:    unexpectedError,
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: problemContentType,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			useCase := idmocks.DataExportUseCasesMock{
+				ExportStudentDataFunc: func(ctx context.Context, input identities.ExportStudentDataInput) (identities.StudentDataExport, error) {
+					assert.Equal(t, "123451271", input.StudentID)
+					assert.Equal(t, keyFingerprint("export-key"), input.Actor)
+					return export, tc.expectedUCErr
+				},
+			}
+
+			h := NewDataExportHandler(zap.NewNop(), &useCase)
+			a := NewAdminAuthorizer(zap.NewNop(), map[string]string{"export-key": ScopeStudentsExport})
+			router := chi.NewRouter()
+			router.With(a.RequireScope(ScopeStudentsExport)).Get("/v1/identities/students/{id}/data-export", h.ExportStudentData)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/identities/students/123451271/data-export"+tc.query, nil)
+			r.Header.Set(adminKeyHeader, "export-key")
+
+			// test
+			router.ServeHTTP(w, r)
+
+			// assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedContentType, w.Header().Get("content-type"))
+			assert.Equal(t, tc.expectedDisposition, w.Header().Get("content-disposition"))
+			if tc.expectedResponse != nil {
+				assert.Equal(t, mustMarshal(t, expectedPayload(tc.expectedResponse, tc.expectedStatus)), strings.TrimSpace(w.Body.String()))
+			} else {
+				assert.NotEmpty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
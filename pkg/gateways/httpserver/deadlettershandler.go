@@ -0,0 +1,228 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type DeadLetterHeaderResponse struct {
+	Key   string `json:"key" swaggertype:"string" example:"ce_type"`
+	Value string `json:"value" swaggertype:"string" example:"student_registered"`
+}
+
+type DeadLetterResponse struct {
+	ID        string                     `json:"id" swaggertype:"string" format:"uuidv4" example:"0b7f2c8e-6a55-4d0e-9a57-3f0c1e9d2a41"`
+	Topic     string                     `json:"topic" swaggertype:"string" example:"identity.cdc.students.0"`
+	Key       string                     `json:"key" swaggertype:"string" example:"201210204310"`
+	EventID   string                     `json:"event_id" swaggertype:"string" format:"uuidv4" example:"4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f"`
+	EventType string                     `json:"event_type" swaggertype:"string" example:"student_registered"`
+	Headers   []DeadLetterHeaderResponse `json:"headers"`
+	Value     []byte                     `json:"value" swaggertype:"string" format:"base64" example:"eyJzdHVkZW50X2lkIjoiMjAxMjEwMjA0MzEwIn0="`
+	Error     string                     `json:"error" swaggertype:"string" example:"context deadline exceeded"`
+	Attempts  int                        `json:"attempts" swaggertype:"integer" example:"3"`
+	FailedAt  string                     `json:"failed_at" swaggertype:"string" format:"datetime" example:"2023-08-06T10:00:00Z"`
+}
+
+type DeadLettersPageResponse struct {
+	DeadLetters []DeadLetterResponse `json:"dead_letters"`
+	NextCursor  string               `json:"next_cursor,omitempty" swaggertype:"string" example:"MjAyMy0wOC0wNlQxMDowMDowMFp8MGI3ZjJjOGU"`
+}
+
+type DeadLettersHandler struct {
+	logger  *zap.Logger
+	useCase identities.DeadLettersUseCases
+}
+
+func NewDeadLettersHandler(logger *zap.Logger, useCase identities.DeadLettersUseCases) DeadLettersHandler {
+	return DeadLettersHandler{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+// ListDeadLetters ...
+// ShowEntity godoc
+// @Summary List the dead-lettered events
+// @Description Events that could not be published after every retry, from the oldest failure. The value is the
+// @Description record value as it was going to be published, base64 encoded.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.events.admin scope"
+// @Param topic query string false "Topic the events were going to be published to"
+// @Param cursor query string false "Cursor sent in the previous page"
+// @Param limit query int false "Page size, up to 100" default(20)
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,application/problem+json
+// @Success 200 {object} DeadLettersPageResponse
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 422 {object} ValidationHTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/dead-letters [get]
+func (h DeadLettersHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	input := identities.ListDeadLettersInput{
+		Topic:  query.Get("topic"),
+		Cursor: query.Get("cursor"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		input.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			var validationErr entities.ValidationError
+			validationErr.Add("limit", identities.ErrInvalidPageLimit)
+			err = sendValidationProblem(w, r, validationErr, searchFieldProblem)
+			if err != nil {
+				h.logger.Error("failed to send error json response", zap.Error(err))
+			}
+			return
+		}
+	}
+
+	page, err := h.useCase.ListDeadLetters(ctx, input)
+	if err != nil {
+		h.logger.Error("unable to list dead letters", zap.Error(err))
+
+		var validationErr entities.ValidationError
+		if errors.As(err, &validationErr) {
+			err = sendValidationProblem(w, r, validationErr, searchFieldProblem)
+		} else {
+			err = sendProblem(w, r, http.StatusInternalServerError, unexpectedError)
+		}
+		if err != nil {
+			h.logger.Error("failed to send error json response", zap.Error(err))
+		}
+		return
+	}
+
+	response := DeadLettersPageResponse{
+		DeadLetters: make([]DeadLetterResponse, 0, len(page.DeadLetters)),
+		NextCursor:  page.NextCursor,
+	}
+	for _, deadLetter := range page.DeadLetters {
+		headers := make([]DeadLetterHeaderResponse, 0, len(deadLetter.Headers))
+		for _, header := range deadLetter.Headers {
+			headers = append(headers, DeadLetterHeaderResponse(header))
+		}
+
+		response.DeadLetters = append(response.DeadLetters, DeadLetterResponse{
+			ID:        deadLetter.ID,
+			Topic:     deadLetter.Topic,
+			Key:       deadLetter.Key,
+			EventID:   deadLetter.EventID,
+			EventType: deadLetter.EventType,
+			Headers:   headers,
+			Value:     deadLetter.Value,
+			Error:     deadLetter.Error,
+			Attempts:  deadLetter.Attempts,
+			FailedAt:  deadLetter.FailedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	err = sendJSON(w, http.StatusOK, response)
+	if err != nil {
+		h.logger.Error("failed to send json response", zap.Error(err))
+	}
+}
+
+// RetryDeadLetter ...
+// ShowEntity godoc
+// @Summary Publish a dead-lettered event again
+// @Description The event is published as it was stored and deleted once published, a failure is recorded in it
+// @Description and it is kept. The retry is audited. Only the oldest dead letter of a key is published, and not
+// @Description after a later event of the key was published, so the events of each student stay in order.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.events.admin scope"
+// @Param id path string true "Dead letter ID"
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce application/problem+json
+// @Success 204
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 404 {object} HTTPError
+// @Failure 409 {object} HTTPError
+// @Failure 500 {object} HTTPError
+// @Failure 502 {object} HTTPError
+// @Router /v1/identities/dead-letters/{id}/retry [post]
+func (h DeadLettersHandler) RetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.useCase.RetryDeadLetter(ctx, identities.DeadLetterInput{
+		ID:    chi.URLParam(r, "id"),
+		Actor: AdminActor(ctx),
+	})
+	if err != nil {
+		h.logger.Error("unable to retry dead letter", zap.Error(err))
+		h.sendDeadLetterProblem(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DiscardDeadLetter ...
+// ShowEntity godoc
+// @Summary Discard a dead-lettered event
+// @Description The event is deleted without being published, consumers never receive it. The discard is audited.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.events.admin scope"
+// @Param id path string true "Dead letter ID"
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce application/problem+json
+// @Success 204
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 404 {object} HTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/dead-letters/{id} [delete]
+func (h DeadLettersHandler) DiscardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.useCase.DiscardDeadLetter(ctx, identities.DeadLetterInput{
+		ID:    chi.URLParam(r, "id"),
+		Actor: AdminActor(ctx),
+	})
+	if err != nil {
+		h.logger.Error("unable to discard dead letter", zap.Error(err))
+		h.sendDeadLetterProblem(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h DeadLettersHandler) sendDeadLetterProblem(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		errorPayload problem
+		statusCode   int
+	)
+	switch {
+	case errors.Is(err, identities.ErrDeadLetterNotFound), errors.Is(err, identities.ErrEmptyDeadLetterID):
+		statusCode = http.StatusNotFound
+		errorPayload = deadLetterNotFound
+	case errors.Is(err, identities.ErrDeadLetterNotPublished):
+		statusCode = http.StatusBadGateway
+		errorPayload = deadLetterNotPublished
+	case errors.Is(err, identities.ErrDeadLetterOutOfOrder):
+		statusCode = http.StatusConflict
+		errorPayload = deadLetterOutOfOrder
+	default:
+		statusCode = http.StatusInternalServerError
+		errorPayload = unexpectedError
+	}
+
+	err = sendProblem(w, r, statusCode, errorPayload)
+	if err != nil {
+		h.logger.Error("failed to send error json response", zap.Error(err))
+	}
+}
@@ -0,0 +1,232 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+)
+
+func TestDeadLettersHandler_ListDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	deadLetter := entities.DeadLetter{
+		ID:        "0b7f2c8e-6a55-4d0e-9a57-3f0c1e9d2a41",
+		Topic:     "identity.cdc.students.0",
+		Key:       "201210204310",
+		EventID:   "4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f",
+		EventType: "student_erased",
+		Headers:   []entities.EventHeader{{Key: "ce_type", Value: "student_erased"}},
+		Value:     []byte(`{"student_id":"201210204310"}`),
+		Error:     "context deadline exceeded",
+		Attempts:  3,
+		FailedAt:  time.Date(2023, 8, 6, 10, 0, 0, 0, time.UTC),
+	}
+
+	validationErr := func(field string, err error) error {
+		var validationErr entities.ValidationError
+		validationErr.Add(field, err)
+		return validationErr
+	}
+
+	tt := []struct {
+		name             string
+		query            string
+		expectedInput    identities.ListDeadLettersInput
+		expectedUCErr    error
+		expectedResponse any
+		expectedStatus   int
+	}{
+		{
+			name:          "should list dead letters of the topic",
+			query:         "?topic=identity.cdc.students.0&limit=1&cursor=abc",
+			expectedInput: identities.ListDeadLettersInput{Topic: "identity.cdc.students.0", Cursor: "abc", Limit: 1},
+			expectedResponse: DeadLettersPageResponse{
+				DeadLetters: []DeadLetterResponse{
+					{
+						ID:        deadLetter.ID,
+						Topic:     deadLetter.Topic,
+						Key:       deadLetter.Key,
+						EventID:   deadLetter.EventID,
+						EventType: deadLetter.EventType,
+						Headers:   []DeadLetterHeaderResponse{{Key: "ce_type", Value: "student_erased"}},
+						Value:     deadLetter.Value,
+						Error:     deadLetter.Error,
+						Attempts:  3,
+						FailedAt:  "2023-08-06T10:00:00Z",
+					},
+				},
+				NextCursor: "next",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should fail because limit is not a number",
+			query:          "?limit=abc",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "should fail because use case refused the limit",
+			query:          "?limit=1000",
+			expectedInput:  identities.ListDeadLettersInput{Limit: 1000},
+			expectedUCErr:  validationErr("limit", identities.ErrInvalidPageLimit),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:             "should fail due to unexpected error from use case",
+			expectedUCErr:    errors.New("unexpected"),
+			expectedResponse: unexpectedError,
+			expectedStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			useCase := idmocks.DeadLettersUseCasesMock{
+				ListDeadLettersFunc: func(ctx context.Context, input identities.ListDeadLettersInput) (identities.DeadLettersPage, error) {
+					assert.Equal(t, tc.expectedInput, input)
+					if tc.expectedUCErr != nil {
+						return identities.DeadLettersPage{}, tc.expectedUCErr
+					}
+					return identities.DeadLettersPage{DeadLetters: []entities.DeadLetter{deadLetter}, NextCursor: "next"}, nil
+				},
+			}
+
+			h := NewDeadLettersHandler(zap.NewNop(), &useCase)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/v1/identities/dead-letters"+tc.query, nil)
+
+			// test
+			h.ListDeadLetters(w, r)
+
+			// assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedResponse != nil {
+				assert.Equal(t, mustMarshal(t, expectedPayload(tc.expectedResponse, tc.expectedStatus)), strings.TrimSpace(w.Body.String()))
+			}
+			if tc.expectedStatus == http.StatusUnprocessableEntity {
+				assert.Contains(t, w.Body.String(), string(invalidPageLimit))
+			}
+		})
+	}
+}
+
+func TestDeadLettersHandler_RetryAndDiscard(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name             string
+		method           string
+		path             string
+		expectedUCErr    error
+		expectedResponse any
+		expectedStatus   int
+	}{
+		{
+			name:           "should retry dead letter",
+			method:         http.MethodPost,
+			path:           "/v1/identities/dead-letters/%s/retry",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:             "should fail to retry because dead letter does not exist",
+			method:           http.MethodPost,
+			path:             "/v1/identities/dead-letters/%s/retry",
+			expectedUCErr:    identities.ErrDeadLetterNotFound,
+			expectedResponse: deadLetterNotFound,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "should fail to retry because event was not published",
+			method:           http.MethodPost,
+			path:             "/v1/identities/dead-letters/%s/retry",
+			expectedUCErr:    fmt.Errorf("%w: broker unreachable", identities.ErrDeadLetterNotPublished),
+			expectedResponse: deadLetterNotPublished,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		{
+			name:             "should fail to retry because event would be published out of order",
+			method:           http.MethodPost,
+			path:             "/v1/identities/dead-letters/%s/retry",
+			expectedUCErr:    fmt.Errorf("%w: a later event of the same key was published", identities.ErrDeadLetterOutOfOrder),
+			expectedResponse: deadLetterOutOfOrder,
+			expectedStatus:   http.StatusConflict,
+		},
+		{
+			name:           "should discard dead letter",
+			method:         http.MethodDelete,
+			path:           "/v1/identities/dead-letters/%s",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:             "should fail to discard because dead letter does not exist",
+			method:           http.MethodDelete,
+			path:             "/v1/identities/dead-letters/%s",
+			expectedUCErr:    identities.ErrDeadLetterNotFound,
+			expectedResponse: deadLetterNotFound,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "should fail to discard due to unexpected error from use case",
+			method:           http.MethodDelete,
+			path:             "/v1/identities/dead-letters/%s",
+			expectedUCErr:    errors.New("unexpected"),
+			expectedResponse: unexpectedError,
+			expectedStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			const id = "0b7f2c8e-6a55-4d0e-9a57-3f0c1e9d2a41"
+			handle := func(ctx context.Context, input identities.DeadLetterInput) error {
+				assert.Equal(t, id, input.ID)
+				assert.Equal(t, keyFingerprint("events-key"), input.Actor)
+				return tc.expectedUCErr
+			}
+			useCase := idmocks.DeadLettersUseCasesMock{
+				RetryDeadLetterFunc:   handle,
+				DiscardDeadLetterFunc: handle,
+			}
+
+			h := NewDeadLettersHandler(zap.NewNop(), &useCase)
+			a := NewAdminAuthorizer(zap.NewNop(), map[string]string{"events-key": ScopeEventsAdmin})
+			router := chi.NewRouter()
+			router.With(a.RequireScope(ScopeEventsAdmin)).Post("/v1/identities/dead-letters/{id}/retry", h.RetryDeadLetter)
+			router.With(a.RequireScope(ScopeEventsAdmin)).Delete("/v1/identities/dead-letters/{id}", h.DiscardDeadLetter)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, fmt.Sprintf(tc.path, id), nil)
+			r.Header.Set(adminKeyHeader, "events-key")
+
+			// test
+			router.ServeHTTP(w, r)
+
+			// assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedResponse != nil {
+				assert.Equal(t, mustMarshal(t, expectedPayload(tc.expectedResponse, tc.expectedStatus)), strings.TrimSpace(w.Body.String()))
+			} else {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
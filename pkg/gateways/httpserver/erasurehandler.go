@@ -0,0 +1,91 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type StudentErasureResponse struct {
+	StudentID       string `json:"student_id" swaggertype:"string" example:"201210204310"`
+	ErasedAt        string `json:"erased_at" swaggertype:"string" format:"datetime" example:"2023-07-23T09:00:00Z"`
+	RevokedSessions int    `json:"revoked_sessions" swaggertype:"integer" example:"1"`
+}
+
+type StudentErasureHandler struct {
+	logger  *zap.Logger
+	useCase identities.StudentErasureUseCases
+}
+
+func NewStudentErasureHandler(logger *zap.Logger, useCase identities.StudentErasureUseCases) StudentErasureHandler {
+	return StudentErasureHandler{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+// EraseStudent ...
+// ShowEntity godoc
+// @Summary Erase every personal data of a student
+// @Description LGPD deletion, the student is pseudonymized and cancelled, its login history and sessions are deleted,
+// @Description the key its events were encrypted with is destroyed and a student_erased event is published.
+// @Description Erasing an already erased student completes any step left behind and returns the first erasure date.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.students.erase scope"
+// @Param id path string true "Student ID"
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,application/problem+json
+// @Success 200 {object} StudentErasureResponse
+// @Failure 400 {object} HTTPError
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 404 {object} HTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/students/{id}/erasure [post]
+func (h StudentErasureHandler) EraseStudent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	report, err := h.useCase.EraseStudent(ctx, identities.EraseStudentInput{
+		StudentID: chi.URLParam(r, "id"),
+		Actor:     AdminActor(ctx),
+	})
+	if err != nil {
+		h.logger.Error("unable to erase student", zap.Error(err))
+
+		var (
+			errorPayload problem
+			statusCode   int
+		)
+		switch {
+		case errors.Is(err, identities.ErrEmptyStudentID):
+			statusCode = http.StatusBadRequest
+			errorPayload = emptyStudentID
+		case errors.Is(err, identities.ErrStudentNotFound):
+			statusCode = http.StatusNotFound
+			errorPayload = studentNotFound
+		default:
+			statusCode = http.StatusInternalServerError
+			errorPayload = unexpectedError
+		}
+
+		err = sendProblem(w, r, statusCode, errorPayload)
+		if err != nil {
+			h.logger.Error("failed to send error json response", zap.Error(err))
+		}
+		return
+	}
+
+	err = sendJSON(w, http.StatusOK, StudentErasureResponse{
+		StudentID:       report.Erasure.StudentID,
+		ErasedAt:        report.Erasure.ErasedAt.UTC().Format(time.RFC3339),
+		RevokedSessions: report.RevokedSessions,
+	})
+	if err != nil {
+		h.logger.Error("failed to send json response", zap.Error(err))
+	}
+}
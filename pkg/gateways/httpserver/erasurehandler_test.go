@@ -0,0 +1,94 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+)
+
+func TestStudentErasureHandler_EraseStudent(t *testing.T) {
+	t.Parallel()
+
+	report := identities.StudentErasureReport{
+		Erasure: entities.StudentErasure{
+			StudentID: "123451271",
+			ErasedAt:  time.Date(2023, 7, 23, 9, 0, 0, 0, time.UTC),
+		},
+		RevokedSessions: 2,
+	}
+
+	tt := []struct {
+		name             string
+		expectedUCErr    error
+		expectedResponse any
+		expectedStatus   int
+	}{
+		{
+			name: "should erase student",
+			expectedResponse: StudentErasureResponse{
+				StudentID:       "123451271",
+				ErasedAt:        "2023-07-23T09:00:00Z",
+				RevokedSessions: 2,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "should fail because student does not exist",
+			expectedUCErr:    identities.ErrStudentNotFound,
+			expectedResponse: studentNotFound,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "should fail due to unexpected error from use case",
+			expectedUCErr:    errors.New("unexpected"),
+			expectedResponse: unexpectedError,
+			expectedStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			useCase := idmocks.StudentErasureUseCasesMock{
+				EraseStudentFunc: func(ctx context.Context, input identities.EraseStudentInput) (identities.StudentErasureReport, error) {
+					assert.Equal(t, "123451271", input.StudentID)
+					assert.Equal(t, keyFingerprint("erase-key"), input.Actor)
+					if tc.expectedUCErr != nil {
+						return identities.StudentErasureReport{}, tc.expectedUCErr
+					}
+					return report, nil
+				},
+			}
+
+			h := NewStudentErasureHandler(zap.NewNop(), &useCase)
+			a := NewAdminAuthorizer(zap.NewNop(), map[string]string{"erase-key": ScopeStudentsErase})
+			router := chi.NewRouter()
+			router.With(a.RequireScope(ScopeStudentsErase)).Post("/v1/identities/students/{id}/erasure", h.EraseStudent)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/identities/students/123451271/erasure", nil)
+			r.Header.Set(adminKeyHeader, "erase-key")
+
+			// test
+			router.ServeHTTP(w, r)
+
+			// assert
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, mustMarshal(t, expectedPayload(tc.expectedResponse, tc.expectedStatus)), strings.TrimSpace(w.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	maxIdempotentBodySize   = 1 << 20
+)
+
+// IdempotencyStore keeps the idempotent requests, values are stored as they are. Release only frees the key
+// while it still holds the reserved value, a reservation that expired may have been taken by a retry.
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+	Complete(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Release(ctx context.Context, key string, reserved []byte) error
+}
+
+type IdempotencyConfig interface {
+	IdempotencyTTL() time.Duration
+	IdempotencyLockTTL() time.Duration
+	IdempotencyWait() time.Duration
+}
+
+// idempotentRequest is what gets stored for each idempotency key. The response is only set once the
+// first request finishes. Owner tells the reservations of requests with the same fingerprint apart.
+type idempotentRequest struct {
+	Fingerprint string              `json:"fingerprint"`
+	Owner       string              `json:"owner,omitempty"`
+	Response    *idempotentResponse `json:"response,omitempty"`
+}
+
+type idempotentResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    []byte            `json:"body"`
+}
+
+// Idempotency makes requests sent with an Idempotency-Key header safe to retry. The first response is
+// stored along with a fingerprint of the request; retries with the same body get it replayed, retries with
+// another body are refused and retries made while the first request runs wait for it or get a conflict.
+type Idempotency struct {
+	logger  *zap.Logger
+	store   IdempotencyStore
+	ttl     time.Duration
+	lockTTL time.Duration
+	wait    time.Duration
+}
+
+func NewIdempotency(logger *zap.Logger, store IdempotencyStore, config IdempotencyConfig) Idempotency {
+	return Idempotency{
+		logger:  logger,
+		store:   store,
+		ttl:     config.IdempotencyTTL(),
+		lockTTL: config.IdempotencyLockTTL(),
+		wait:    config.IdempotencyWait(),
+	}
+}
+
+func (i Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		logger := i.logger.With(zap.String("idempotency_key", key))
+
+		if len(key) > maxIdempotencyKeyLength {
+			i.sendProblem(w, r, logger, http.StatusBadRequest, invalidIdempotencyKey)
+			return
+		}
+
+		// a byte past the limit is read to tell a body of the limit size from a bigger one
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			logger.Error("unable to read request body", zap.Error(err))
+			i.sendProblem(w, r, logger, http.StatusBadRequest, invalidJSON)
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			i.sendProblem(w, r, logger, http.StatusRequestEntityTooLarge, requestTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := requestFingerprint(r, body)
+		reservation, err := json.Marshal(idempotentRequest{Fingerprint: fingerprint, Owner: uuid.NewString()})
+		if err != nil {
+			logger.Error("unable to encode idempotent request", zap.Error(err))
+			i.sendProblem(w, r, logger, http.StatusInternalServerError, unexpectedError)
+			return
+		}
+
+		stored, reserved, err := i.store.Reserve(ctx, key, reservation, i.lockTTL)
+		if err != nil {
+			logger.Error("unable to reserve idempotency key", zap.Error(err))
+			i.sendProblem(w, r, logger, http.StatusInternalServerError, unexpectedError)
+			return
+		}
+
+		if !reserved {
+			i.replay(w, r, logger, key, fingerprint, stored)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		// a context detached from the request, so the outcome is stored even if the client went away
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if recorder.status >= http.StatusInternalServerError {
+			err = i.store.Release(storeCtx, key, reservation)
+			if err != nil {
+				logger.Error("unable to release idempotency key", zap.Error(err))
+			}
+			return
+		}
+
+		completed, err := json.Marshal(idempotentRequest{
+			Fingerprint: fingerprint,
+			Response:    recorder.response(),
+		})
+		if err == nil {
+			err = i.store.Complete(storeCtx, key, completed, i.ttl)
+		}
+		if err != nil {
+			logger.Error("unable to store idempotent response", zap.Error(err))
+		}
+	})
+}
+
+// replay answers a retry with the stored response, waiting for it while the first request is still running.
+func (i Idempotency) replay(w http.ResponseWriter, r *http.Request, logger *zap.Logger, key string, fingerprint string, stored []byte) {
+	deadline := time.Now().Add(i.wait)
+	for {
+		var previous idempotentRequest
+		err := json.Unmarshal(stored, &previous)
+		if err != nil {
+			logger.Error("unable to decode idempotent request", zap.Error(err))
+			i.sendProblem(w, r, logger, http.StatusInternalServerError, unexpectedError)
+			return
+		}
+
+		if previous.Fingerprint != fingerprint {
+			i.sendProblem(w, r, logger, http.StatusUnprocessableEntity, idempotencyKeyReused)
+			return
+		}
+
+		if previous.Response != nil {
+			for k, v := range previous.Response.Headers {
+				w.Header().Set(k, v)
+			}
+			w.Header().Set(idempotencyReplayedHeader, "true")
+			w.WriteHeader(previous.Response.Status)
+			_, err = w.Write(previous.Response.Body)
+			if err != nil {
+				logger.Error("failed to replay idempotent response", zap.Error(err))
+			}
+			return
+		}
+
+		if time.Now().After(deadline) {
+			i.sendProblem(w, r, logger, http.StatusConflict, requestInProgress)
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		stored, err = i.store.Get(r.Context(), key)
+		if err != nil {
+			logger.Error("unable to fetch idempotent request", zap.Error(err))
+			i.sendProblem(w, r, logger, http.StatusInternalServerError, unexpectedError)
+			return
+		}
+		if stored == nil {
+			// the first request failed and released the key, so this one may be retried
+			i.sendProblem(w, r, logger, http.StatusConflict, requestInProgress)
+			return
+		}
+	}
+}
+
+func (i Idempotency) sendProblem(w http.ResponseWriter, r *http.Request, logger *zap.Logger, status int, p problem) {
+	err := sendProblem(w, r, status, p)
+	if err != nil {
+		logger.Error("failed to send error json response", zap.Error(err))
+	}
+}
+
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder copies what is written to the client so the response can be replayed.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) response() *idempotentResponse {
+	headers := make(map[string]string)
+	for _, h := range []string{"Content-Type", "Content-Language"} {
+		if v := r.Header().Get(h); v != "" {
+			headers[h] = v
+		}
+	}
+
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return &idempotentResponse{
+		Status:  status,
+		Headers: headers,
+		Body:    r.body.Bytes(),
+	}
+}
@@ -0,0 +1,285 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/gateways/httpserver/hsfixtures"
+)
+
+type idempotencyConfig struct {
+	wait time.Duration
+}
+
+func (c idempotencyConfig) IdempotencyTTL() time.Duration {
+	return time.Hour
+}
+
+func (c idempotencyConfig) IdempotencyLockTTL() time.Duration {
+	return time.Minute
+}
+
+func (c idempotencyConfig) IdempotencyWait() time.Duration {
+	return c.wait
+}
+
+// memoryIdempotencyStore is an IdempotencyStore fake, expiration is not handled.
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{values: make(map[string][]byte)}
+}
+
+func (m *memoryIdempotencyStore) Reserve(_ context.Context, key string, value []byte, _ time.Duration) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stored, ok := m.values[key]; ok {
+		return stored, false, nil
+	}
+	m.values[key] = value
+	return nil, true, nil
+}
+
+func (m *memoryIdempotencyStore) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.values[key], nil
+}
+
+func (m *memoryIdempotencyStore) Complete(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(_ context.Context, key string, reserved []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if bytes.Equal(m.values[key], reserved) {
+		delete(m.values, key)
+	}
+	return nil
+}
+
+func TestIdempotency_Handler(t *testing.T) {
+	t.Parallel()
+
+	newRequest := func(key string, body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/v1/identities/students", bytes.NewReader([]byte(body)))
+		if key != "" {
+			r.Header.Set(idempotencyKeyHeader, key)
+		}
+		return r
+	}
+
+	t.Run("should replay the first response to retries with the same body", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		var calls atomic.Int32
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				_ = sendJSON(w, http.StatusCreated, StudentRegisterResponse{ID: "123451271"})
+			}))
+
+		first := httptest.NewRecorder()
+		retry := httptest.NewRecorder()
+
+		// test
+		handler.ServeHTTP(first, newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+		handler.ServeHTTP(retry, newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+
+		// assert
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get(idempotencyReplayedHeader))
+	})
+
+	t.Run("should refuse retries with a different body", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = sendJSON(w, http.StatusCreated, StudentRegisterResponse{ID: "123451271"})
+			}))
+
+		retry := httptest.NewRecorder()
+
+		// test
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+		handler.ServeHTTP(retry, newRequest("key-1", hsfixtures.InvalidCPFRequestBody))
+
+		// assert
+		assert.Equal(t, http.StatusUnprocessableEntity, retry.Code)
+		assert.Contains(t, retry.Body.String(), string(idempotencyKeyReused))
+	})
+
+	t.Run("should answer conflict while the first request is running", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		release := make(chan struct{})
+		started := make(chan struct{})
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{wait: 200 * time.Millisecond}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-release
+				_ = sendJSON(w, http.StatusCreated, StudentRegisterResponse{ID: "123451271"})
+			}))
+
+		go handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+		<-started
+		defer close(release)
+
+		concurrent := httptest.NewRecorder()
+
+		// test
+		handler.ServeHTTP(concurrent, newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+
+		// assert
+		assert.Equal(t, http.StatusConflict, concurrent.Code)
+		assert.Contains(t, concurrent.Body.String(), string(requestInProgress))
+	})
+
+	t.Run("should wait for the first request to finish", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		started := make(chan struct{})
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{wait: 5 * time.Second}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				time.Sleep(300 * time.Millisecond)
+				_ = sendJSON(w, http.StatusCreated, StudentRegisterResponse{ID: "123451271"})
+			}))
+
+		go handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+		<-started
+
+		concurrent := httptest.NewRecorder()
+
+		// test
+		handler.ServeHTTP(concurrent, newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+
+		// assert
+		assert.Equal(t, http.StatusCreated, concurrent.Code)
+		assert.Equal(t, "true", concurrent.Header().Get(idempotencyReplayedHeader))
+	})
+
+	t.Run("should let retries run again after an unexpected error", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		var calls atomic.Int32
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) == 1 {
+					_ = sendProblem(w, r, http.StatusInternalServerError, unexpectedError)
+					return
+				}
+				_ = sendJSON(w, http.StatusCreated, StudentRegisterResponse{ID: "123451271"})
+			}))
+
+		retry := httptest.NewRecorder()
+
+		// test
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+		handler.ServeHTTP(retry, newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+
+		// assert
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, http.StatusCreated, retry.Code)
+	})
+
+	t.Run("should not release the reservation of a retry", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		store := newMemoryIdempotencyStore()
+		handler := NewIdempotency(zap.NewNop(), store, idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// the reservation expired and a retry took the key while this request ran
+				store.mu.Lock()
+				store.values["key-1"] = []byte(`{"fingerprint":"retry"}`)
+				store.mu.Unlock()
+				_ = sendProblem(w, r, http.StatusInternalServerError, unexpectedError)
+			}))
+
+		// test
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest("key-1", hsfixtures.ValidStudentRequestBody))
+
+		// assert
+		stored, err := store.Get(context.Background(), "key-1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(`{"fingerprint":"retry"}`), stored)
+	})
+
+	t.Run("should refuse bodies over the size limit", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("request must not reach the handler")
+			}))
+
+		w := httptest.NewRecorder()
+
+		// test
+		handler.ServeHTTP(w, newRequest("key-1", strings.Repeat("a", maxIdempotentBodySize+1)))
+
+		// assert
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), string(requestTooLarge))
+	})
+
+	t.Run("should refuse too long keys", func(t *testing.T) {
+		t.Parallel()
+
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("request must not reach the handler")
+			}))
+
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, newRequest(strings.Repeat("k", 256), hsfixtures.ValidStudentRequestBody))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), string(invalidIdempotencyKey))
+	})
+
+	t.Run("should not touch requests without key", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		handler := NewIdempotency(zap.NewNop(), newMemoryIdempotencyStore(), idempotencyConfig{}).
+			Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(http.StatusCreated)
+			}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest("", hsfixtures.ValidStudentRequestBody))
+		handler.ServeHTTP(httptest.NewRecorder(), newRequest("", hsfixtures.ValidStudentRequestBody))
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
@@ -0,0 +1,141 @@
+package httpserver
+
+import (
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+type message struct {
+	title  string
+	detail string
+}
+
+// supportedLanguages lists the catalog languages, the first one is used when Accept-Language matches none.
+var supportedLanguages = []language.Tag{language.English, language.BrazilianPortuguese}
+
+var languageMatcher = language.NewMatcher(supportedLanguages)
+
+var messages = map[language.Tag]map[problem]message{
+	language.English: {
+		invalidJSON:     {"Invalid JSON", "Invalid JSON was sent"},
+		unexpectedError: {"Unexpected error", "Unexpected error, try again later"},
+
+		invalidStudentID:       {"Invalid student ID", "Invalid Student ID was sent"},
+		invalidCPF:             {"Invalid CPF", "Invalid CPF was sent"},
+		invalidEmail:           {"Invalid email", "Invalid email was sent"},
+		invalidBirthDate:       {"Invalid birth date", "Invalid birth date was sent"},
+		invalidName:            {"Invalid name", "Invalid name was sent"},
+		invalidSecret:          {"Invalid secret", "Invalid secret was sent"},
+		invalidFields:          {"Invalid fields", "Invalid fields were sent"},
+		invalidCourseID:        {"Invalid course", "Invalid or unavailable course id was sent"},
+		cpfAlreadyRegistered:   {"CPF already registered", "CPF is already registered to another student"},
+		emailAlreadyRegistered: {"Email already registered", "Email is already registered to another student"},
+
+		invalidCredentials: {"Invalid credentials", "Invalid credentials were sent"},
+		emptyStudentID:     {"Empty student ID", "Empty student id was sent"},
+		emptySecret:        {"Empty secret", "Empty secret was sent"},
+		accessForbidden:    {"Access forbidden", "Access forbidden, do not try again"},
+		accessUnauthorized: {"Access unauthorized", "Access unauthorized"},
+		unknownAudience:    {"Unknown audience", "Audience is not a registered service"},
+
+		studentSuspended:        {"Student suspended", "Student is suspended"},
+		studentCancelled:        {"Student cancelled", "Student enrollment is cancelled"},
+		studentNotFound:         {"Student not found", "Student not found"},
+		invalidStudentStatus:    {"Invalid student status", "Invalid student status was sent"},
+		invalidStatusTransition: {"Invalid status transition", "Student can not move from its current status to the one sent"},
+		studentStatusChanged:    {"Student status changed", "Student status was changed by another request, try again"},
+
+		invalidIdempotencyKey: {"Invalid idempotency key", "Idempotency key must have at most 255 characters"},
+		idempotencyKeyReused:  {"Idempotency key reused", "Idempotency key was already used by a different request"},
+		requestInProgress:     {"Request in progress", "A request with the same idempotency key is still being processed, try again"},
+		requestTooLarge:       {"Request too large", "Requests with an idempotency key must have a body of at most 1MB"},
+
+		invalidImportFile:    {"Invalid import file", "Import file format must be csv or jsonl and a CSV header must have every student column"},
+		invalidDryRun:        {"Invalid dry run", "Dry run must be true or false"},
+		malformedImportRow:   {"Malformed row", "Row could not be read from the import file"},
+		duplicatedImportRow:  {"Repeated student", "Student ID, CPF or email is repeated in a previous row of the import file"},
+		studentAlreadyExists: {"Student already exists", "Student ID is already registered"},
+		eventNotPublished:    {"Event not published", "Student was stored but its registration event could not be published"},
+
+		invalidPageCursor:       {"Invalid cursor", "Cursor must be the one sent in a previous page"},
+		invalidPageLimit:        {"Invalid limit", "Limit must be between 1 and 100"},
+		invalidRegistrationDate: {"Invalid registration date", "Registration dates must be in the yyyy-mm-dd format and the range can not end before it starts"},
+
+		invalidExportFormat: {"Invalid export format", "Export format must be json or zip"},
+
+		deadLetterNotFound:     {"Dead letter not found", "Dead letter not found, it may have been retried or discarded already"},
+		deadLetterNotPublished: {"Dead letter not published", "Event could not be published again, it was kept for another retry"},
+		deadLetterOutOfOrder:   {"Dead letter out of order", "Event would be published out of order, an older dead letter of the student must be retried or discarded first, or a later event of the student was already published"},
+	},
+	language.BrazilianPortuguese: {
+		invalidJSON:     {"JSON inválido", "Foi enviado um JSON inválido"},
+		unexpectedError: {"Erro inesperado", "Erro inesperado, tente novamente mais tarde"},
+
+		invalidStudentID:       {"Matrícula inválida", "Foi enviada uma matrícula inválida"},
+		invalidCPF:             {"CPF inválido", "Foi enviado um CPF inválido"},
+		invalidEmail:           {"E-mail inválido", "Foi enviado um e-mail inválido"},
+		invalidBirthDate:       {"Data de nascimento inválida", "Foi enviada uma data de nascimento inválida"},
+		invalidName:            {"Nome inválido", "Foi enviado um nome inválido"},
+		invalidSecret:          {"Senha inválida", "Foi enviada uma senha inválida"},
+		invalidFields:          {"Campos inválidos", "Foram enviados campos inválidos"},
+		invalidCourseID:        {"Curso inválido", "Foi enviado um curso inválido ou indisponível"},
+		cpfAlreadyRegistered:   {"CPF já cadastrado", "O CPF já está cadastrado para outro aluno"},
+		emailAlreadyRegistered: {"E-mail já cadastrado", "O e-mail já está cadastrado para outro aluno"},
+
+		invalidCredentials: {"Credenciais inválidas", "Foram enviadas credenciais inválidas"},
+		emptyStudentID:     {"Matrícula vazia", "A matrícula não foi informada"},
+		emptySecret:        {"Senha vazia", "A senha não foi informada"},
+		accessForbidden:    {"Acesso proibido", "Acesso proibido, não tente novamente"},
+		accessUnauthorized: {"Acesso não autorizado", "Acesso não autorizado"},
+		unknownAudience:    {"Audiência desconhecida", "A audiência não é um serviço cadastrado"},
+
+		studentSuspended:        {"Aluno suspenso", "O aluno está suspenso"},
+		studentCancelled:        {"Aluno cancelado", "A matrícula do aluno está cancelada"},
+		studentNotFound:         {"Aluno não encontrado", "Aluno não encontrado"},
+		invalidStudentStatus:    {"Situação inválida", "Foi enviada uma situação de aluno inválida"},
+		invalidStatusTransition: {"Mudança de situação inválida", "O aluno não pode passar da situação atual para a enviada"},
+		studentStatusChanged:    {"Situação alterada", "A situação do aluno foi alterada por outra requisição, tente novamente"},
+
+		invalidIdempotencyKey: {"Chave de idempotência inválida", "A chave de idempotência deve ter no máximo 255 caracteres"},
+		idempotencyKeyReused:  {"Chave de idempotência reutilizada", "A chave de idempotência já foi usada por uma requisição diferente"},
+		requestInProgress:     {"Requisição em andamento", "Uma requisição com a mesma chave de idempotência ainda está em processamento, tente novamente"},
+		requestTooLarge:       {"Requisição muito grande", "Requisições com chave de idempotência devem ter um corpo de no máximo 1MB"},
+
+		invalidImportFile:    {"Arquivo de importação inválido", "O arquivo de importação deve ser csv ou jsonl e o cabeçalho CSV deve ter todas as colunas do aluno"},
+		invalidDryRun:        {"Simulação inválida", "A simulação deve ser true ou false"},
+		malformedImportRow:   {"Linha malformada", "Não foi possível ler a linha do arquivo de importação"},
+		duplicatedImportRow:  {"Aluno repetido", "Matrícula, CPF ou e-mail repetido em uma linha anterior do arquivo de importação"},
+		studentAlreadyExists: {"Aluno já cadastrado", "A matrícula já está cadastrada"},
+		eventNotPublished:    {"Evento não publicado", "O aluno foi armazenado mas não foi possível publicar seu evento de cadastro"},
+
+		invalidPageCursor:       {"Cursor inválido", "O cursor deve ser o enviado em uma página anterior"},
+		invalidPageLimit:        {"Limite inválido", "O limite deve estar entre 1 e 100"},
+		invalidRegistrationDate: {"Data de cadastro inválida", "As datas de cadastro devem estar no formato aaaa-mm-dd e o intervalo não pode terminar antes de começar"},
+
+		invalidExportFormat: {"Formato de exportação inválido", "O formato de exportação deve ser json ou zip"},
+
+		deadLetterNotFound:     {"Evento não encontrado", "Evento não encontrado entre os que falharam, ele pode já ter sido reenviado ou descartado"},
+		deadLetterNotPublished: {"Evento não publicado", "Não foi possível publicar o evento novamente, ele foi mantido para outra tentativa"},
+		deadLetterOutOfOrder:   {"Evento fora de ordem", "O evento seria publicado fora de ordem, um evento mais antigo do aluno deve ser reenviado ou descartado antes, ou um evento mais recente do aluno já foi publicado"},
+	},
+}
+
+// requestLanguage picks the catalog language that best matches the request Accept-Language header.
+func requestLanguage(r *http.Request) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return supportedLanguages[0]
+	}
+
+	_, index, _ := languageMatcher.Match(tags...)
+	return supportedLanguages[index]
+}
+
+// localize falls back to the default language whenever a message is missing from the catalog.
+func localize(lang language.Tag, p problem) message {
+	if msg, ok := messages[lang][p]; ok {
+		return msg
+	}
+	return messages[supportedLanguages[0]][p]
+}
@@ -3,72 +3,152 @@ package httpserver
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"golang.org/x/text/language"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
 )
 
+const problemContentType = "application/problem+json"
+
+// problemTypeNamespace is the URN namespace of the problem types, RFC 8141 does not allow underscores in it.
+const problemTypeNamespace = "urn:identity-service:"
+
+// HTTPError is an RFC 7807 problem details document. Its title and detail are localized according to
+// the request Accept-Language, err_code is kept for clients built before problem details were adopted.
 type HTTPError struct {
-	Code    string `json:"err_code"`
-	Message string `json:"message"`
+	Type     string `json:"type" swaggertype:"string" example:"urn:identity-service:error:invalid_cpf"`
+	Title    string `json:"title" swaggertype:"string" example:"Invalid CPF"`
+	Status   int    `json:"status" swaggertype:"integer" example:"400"`
+	Detail   string `json:"detail" swaggertype:"string" example:"Invalid CPF was sent"`
+	Instance string `json:"instance,omitempty" swaggertype:"string" example:"identity-service/JhG6t0Vb2L-000001"`
+	Code     string `json:"err_code" swaggertype:"string" example:"identity_service.error.invalid_cpf"`
 }
 
-var (
-	invalidJSON = HTTPError{
-		Code:    "identity_service.error.invalid_json",
-		Message: "invalid JSON was sent",
-	}
-	unexpectedError = HTTPError{
-		Code:    "identity_service.error.unexpected",
-		Message: "Unexpected Error",
-	}
+// ValidationHTTPError lists every invalid field of a request along with the error of each one.
+type ValidationHTTPError struct {
+	HTTPError
+	Fields []FieldHTTPError `json:"fields"`
+}
 
-	invalidStudentID = HTTPError{
-		Code:    "identity_service.error.invalid_student_id",
-		Message: "Invalid Student ID was sent",
-	}
-	invalidCPF = HTTPError{
-		Code:    "identity_service.error.invalid_cpf",
-		Message: "Invalid CPF was sent",
-	}
-	invalidEmail = HTTPError{
-		Code:    "identity_service.error.invalid_email",
-		Message: "Invalid email was sent",
-	}
-	invalidBirthDate = HTTPError{
-		Code:    "identity_service.error.invalid_birth_date",
-		Message: "Invalid birth date was sent",
-	}
-	invalidCourseID = HTTPError{
-		Code:    "identity_service.error.invalid_course_id",
-		Message: "Invalid course id format was sent",
-	}
+type FieldHTTPError struct {
+	Field  string `json:"field" swaggertype:"string" example:"cpf"`
+	Code   string `json:"err_code" swaggertype:"string" example:"identity_service.error.invalid_cpf"`
+	Detail string `json:"detail" swaggertype:"string" example:"Invalid CPF was sent"`
+}
 
-	invalidCredentials = HTTPError{
-		Code:    "identity_service.error.invalid_credentials",
-		Message: "Invalid credentials were sent",
-	}
+// problem identifies an error the API may answer with by its err_code, its texts live in the messages catalog.
+type problem string
+
+const (
+	invalidJSON     problem = "identity_service.error.invalid_json"
+	unexpectedError problem = "identity_service.error.unexpected"
+
+	invalidStudentID       problem = "identity_service.error.invalid_student_id"
+	invalidCPF             problem = "identity_service.error.invalid_cpf"
+	invalidEmail           problem = "identity_service.error.invalid_email"
+	invalidBirthDate       problem = "identity_service.error.invalid_birth_date"
+	invalidName            problem = "identity_service.error.invalid_name"
+	invalidSecret          problem = "identity_service.error.invalid_secret"
+	invalidFields          problem = "identity_service.error.invalid_fields"
+	invalidCourseID        problem = "identity_service.error.invalid_course_id"
+	cpfAlreadyRegistered   problem = "identity_service.error.cpf_already_registered"
+	emailAlreadyRegistered problem = "identity_service.error.email_already_registered"
+
+	invalidCredentials problem = "identity_service.error.invalid_credentials"
+	emptyStudentID     problem = "identity_service.error.empty_student_id"
+	emptySecret        problem = "identity_service.error.empty_secret"
+	accessForbidden    problem = "identity_service.error.forbidden"
+	accessUnauthorized problem = "identity_service.error.unauthorized"
+	unknownAudience    problem = "identity_service.error.unknown_audience"
+
+	studentSuspended        problem = "identity_service.error.student_suspended"
+	studentCancelled        problem = "identity_service.error.student_cancelled"
+	studentNotFound         problem = "identity_service.error.student_not_found"
+	invalidStudentStatus    problem = "identity_service.error.invalid_student_status"
+	invalidStatusTransition problem = "identity_service.error.invalid_status_transition"
+	studentStatusChanged    problem = "identity_service.error.student_status_changed"
+
+	invalidIdempotencyKey problem = "identity_service.error.invalid_idempotency_key"
+	idempotencyKeyReused  problem = "identity_service.error.idempotency_key_reused"
+	requestInProgress     problem = "identity_service.error.request_in_progress"
+	requestTooLarge       problem = "identity_service.error.request_too_large"
+
+	invalidImportFile    problem = "identity_service.error.invalid_import_file"
+	invalidDryRun        problem = "identity_service.error.invalid_dry_run"
+	malformedImportRow   problem = "identity_service.error.malformed_import_row"
+	duplicatedImportRow  problem = "identity_service.error.duplicated_import_row"
+	studentAlreadyExists problem = "identity_service.error.student_already_exists"
+	eventNotPublished    problem = "identity_service.error.event_not_published"
+
+	invalidPageCursor       problem = "identity_service.error.invalid_page_cursor"
+	invalidPageLimit        problem = "identity_service.error.invalid_page_limit"
+	invalidRegistrationDate problem = "identity_service.error.invalid_registration_date"
 
-	emptyStudentID = HTTPError{
-		Code:    "identity_service.error.empty_student_id",
-		Message: "Empty student id was sent",
+	invalidExportFormat problem = "identity_service.error.invalid_export_format"
+
+	deadLetterNotFound     problem = "identity_service.error.dead_letter_not_found"
+	deadLetterNotPublished problem = "identity_service.error.dead_letter_not_published"
+	deadLetterOutOfOrder   problem = "identity_service.error.dead_letter_out_of_order"
+)
+
+// httpError builds the problem document in the informed language.
+func (p problem) httpError(lang language.Tag, status int, instance string) HTTPError {
+	msg := localize(lang, p)
+	return HTTPError{
+		Type:     problemTypeNamespace + strings.ReplaceAll(strings.TrimPrefix(string(p), "identity_service."), ".", ":"),
+		Title:    msg.title,
+		Status:   status,
+		Detail:   msg.detail,
+		Instance: instance,
+		Code:     string(p),
 	}
+}
 
-	emptySecret = HTTPError{
-		Code:    "identity_service.error.empty_secret",
-		Message: "Empty secret was sent",
+func sendJSON(w http.ResponseWriter, status int, payload any) error {
+	w.Header().Add("content-type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(payload)
+	if err != nil {
+		return err
 	}
+	return nil
+}
+
+// sendProblem answers with the problem localized to the request language.
+func sendProblem(w http.ResponseWriter, r *http.Request, status int, p problem) error {
+	lang := requestLanguage(r)
+	return writeProblem(w, lang, status, p.httpError(lang, status, middleware.GetReqID(r.Context())))
+}
+
+// sendValidationProblem answers with every invalid field of the validation error.
+func sendValidationProblem(w http.ResponseWriter, r *http.Request, validationErr entities.ValidationError, fieldProblem func(error) problem) error {
+	const status = http.StatusUnprocessableEntity
 
-	accessForbidden = HTTPError{
-		Code:    "identity_service.error.forbidden",
-		Message: "Access forbidden, do not try again",
+	lang := requestLanguage(r)
+	payload := ValidationHTTPError{
+		HTTPError: invalidFields.httpError(lang, status, middleware.GetReqID(r.Context())),
+		Fields:    make([]FieldHTTPError, 0, len(validationErr.Fields)),
 	}
 
-	accessUnauthorized = HTTPError{
-		Code:    "identity_service.error.unauthorized",
-		Message: "Access unauthorized",
+	for _, field := range validationErr.Fields {
+		p := fieldProblem(field.Err)
+		payload.Fields = append(payload.Fields, FieldHTTPError{
+			Field:  field.Field,
+			Code:   string(p),
+			Detail: localize(lang, p).detail,
+		})
 	}
-)
 
-func sendJSON(w http.ResponseWriter, status int, payload any) error {
-	w.Header().Add("content-type", "application/json")
+	return writeProblem(w, lang, status, payload)
+}
+
+func writeProblem(w http.ResponseWriter, lang language.Tag, status int, payload any) error {
+	w.Header().Add("content-type", problemContentType)
+	w.Header().Add("content-language", lang.String())
+	w.Header().Add("vary", "Accept-Language")
 	w.WriteHeader(status)
 	err := json.NewEncoder(w).Encode(payload)
 	if err != nil {
@@ -0,0 +1,128 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestSendProblem(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name             string
+		acceptLanguage   string
+		expectedLanguage string
+		expectedResponse HTTPError
+	}{
+		{
+			name:             "should answer in english when no language is accepted",
+			expectedLanguage: "en",
+			expectedResponse: HTTPError{
+				Type:   "urn:identity-service:error:invalid_cpf",
+				Title:  "Invalid CPF",
+				Status: http.StatusBadRequest,
+				Detail: "Invalid CPF was sent",
+				Code:   "identity_service.error.invalid_cpf",
+			},
+		},
+		{
+			name:             "should answer in brazilian portuguese",
+			acceptLanguage:   "pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7",
+			expectedLanguage: "pt-BR",
+			expectedResponse: HTTPError{
+				Type:   "urn:identity-service:error:invalid_cpf",
+				Title:  "CPF inválido",
+				Status: http.StatusBadRequest,
+				Detail: "Foi enviado um CPF inválido",
+				Code:   "identity_service.error.invalid_cpf",
+			},
+		},
+		{
+			name:             "should answer in brazilian portuguese to any portuguese",
+			acceptLanguage:   "pt",
+			expectedLanguage: "pt-BR",
+			expectedResponse: HTTPError{
+				Type:   "urn:identity-service:error:invalid_cpf",
+				Title:  "CPF inválido",
+				Status: http.StatusBadRequest,
+				Detail: "Foi enviado um CPF inválido",
+				Code:   "identity_service.error.invalid_cpf",
+			},
+		},
+		{
+			name:             "should fall back to english when accepted language is not supported",
+			acceptLanguage:   "fr-CH, fr;q=0.9",
+			expectedLanguage: "en",
+			expectedResponse: HTTPError{
+				Type:   "urn:identity-service:error:invalid_cpf",
+				Title:  "Invalid CPF",
+				Status: http.StatusBadRequest,
+				Detail: "Invalid CPF was sent",
+				Code:   "identity_service.error.invalid_cpf",
+			},
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/v1/identities/students", nil)
+			if tc.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, sendProblem(w, r, http.StatusBadRequest, invalidCPF))
+			})
+			handler = middleware.RequestID(handler)
+
+			// test
+			handler.ServeHTTP(w, r)
+
+			// assert
+			var got HTTPError
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.NotEmpty(t, got.Instance)
+
+			got.Instance = ""
+			assert.Equal(t, tc.expectedResponse, got)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expectedLanguage, w.Header().Get("Content-Language"))
+		})
+	}
+}
+
+func TestMessagesCatalog(t *testing.T) {
+	t.Parallel()
+
+	english := messages[language.English]
+	for _, lang := range supportedLanguages {
+		assert.Len(t, messages[lang], len(english), "catalog of %s is incomplete", lang)
+		for p := range english {
+			msg, ok := messages[lang][p]
+			if assert.True(t, ok, "%s has no %s message", p, lang) {
+				assert.NotEmpty(t, msg.title)
+				assert.NotEmpty(t, msg.detail)
+			}
+		}
+	}
+}
+
+// expectedPayload renders problems the way they are sent to requests without Accept-Language and request ID.
+func expectedPayload(response any, status int) any {
+	if p, ok := response.(problem); ok {
+		return p.httpError(language.English, status, "")
+	}
+	return response
+}
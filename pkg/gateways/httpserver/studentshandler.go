@@ -42,10 +42,15 @@ func NewStudentsHandler(useCase identities.RegisterUseCases, logger *zap.Logger)
 // @Summary Register a student
 // @Tags Registration
 // @Param request body StudentRegisterRequest true "Student creation information"
+// @Param Idempotency-Key header string false "Retries sent with the same key and body get the first response replayed"
 // @Accept json
-// @Produce json
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,application/problem+json
 // @Success 201 {object} StudentRegisterResponse
 // @Failure 400 {object} HTTPError
+// @Failure 409 {object} HTTPError
+// @Failure 413 {object} HTTPError
+// @Failure 422 {object} ValidationHTTPError
 // @Failure 500 {object} HTTPError
 // @Router /v1/identities/students [post]
 func (h StudentsHandler) RegisterStudent(w http.ResponseWriter, r *http.Request) {
@@ -55,7 +60,7 @@ func (h StudentsHandler) RegisterStudent(w http.ResponseWriter, r *http.Request)
 	err := json.NewDecoder(r.Body).Decode(&reqBody)
 	if err != nil {
 		h.logger.Error("invalid json received", zap.Error(err))
-		err = sendJSON(w, http.StatusBadRequest, invalidJSON)
+		err = sendProblem(w, r, http.StatusBadRequest, invalidJSON)
 		if err != nil {
 			h.logger.Error("failed to send error response", zap.Error(err))
 		}
@@ -66,32 +71,35 @@ func (h StudentsHandler) RegisterStudent(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		h.logger.Error("unable to register user", zap.Error(err))
 
+		var validationErr entities.ValidationError
+		if errors.As(err, &validationErr) {
+			err = sendValidationProblem(w, r, validationErr, fieldProblem)
+			if err != nil {
+				h.logger.Error("failed to send error json response", zap.Error(err))
+			}
+			return
+		}
+
 		var (
-			errorPayload HTTPError
+			errorPayload problem
 			statusCode   int
 		)
 		switch {
-		case errors.Is(err, entities.ErrInvalidStudentID), errors.Is(err, identities.ErrStudentAlreadyExists):
+		case errors.Is(err, identities.ErrStudentAlreadyExists):
 			statusCode = http.StatusBadRequest
 			errorPayload = invalidStudentID
-		case errors.Is(err, entities.ErrInvalidCPF):
-			statusCode = http.StatusBadRequest
-			errorPayload = invalidCPF
-		case errors.Is(err, entities.ErrInvalidEmail):
-			statusCode = http.StatusBadRequest
-			errorPayload = invalidEmail
-		case errors.Is(err, entities.ErrInvalidBirthDate):
-			statusCode = http.StatusBadRequest
-			errorPayload = invalidBirthDate
-		case errors.Is(err, identities.ErrInvalidCourseID):
-			statusCode = http.StatusBadRequest
-			errorPayload = invalidCourseID
+		case errors.Is(err, identities.ErrCPFAlreadyRegistered):
+			statusCode = http.StatusConflict
+			errorPayload = cpfAlreadyRegistered
+		case errors.Is(err, identities.ErrEmailAlreadyRegistered):
+			statusCode = http.StatusConflict
+			errorPayload = emailAlreadyRegistered
 		default:
 			statusCode = http.StatusInternalServerError
 			errorPayload = unexpectedError
 		}
 
-		err = sendJSON(w, statusCode, errorPayload)
+		err = sendProblem(w, r, statusCode, errorPayload)
 		if err != nil {
 			h.logger.Error("failed to send error json response", zap.Error(err))
 		}
@@ -103,3 +111,24 @@ func (h StudentsHandler) RegisterStudent(w http.ResponseWriter, r *http.Request)
 		h.logger.Error("failed to send json response", zap.Error(err))
 	}
 }
+
+func fieldProblem(err error) problem {
+	switch {
+	case errors.Is(err, entities.ErrInvalidStudentID):
+		return invalidStudentID
+	case errors.Is(err, entities.ErrInvalidCPF):
+		return invalidCPF
+	case errors.Is(err, entities.ErrInvalidEmail):
+		return invalidEmail
+	case errors.Is(err, entities.ErrInvalidBirthDate):
+		return invalidBirthDate
+	case errors.Is(err, entities.ErrInvalidName):
+		return invalidName
+	case errors.Is(err, entities.ErrInvalidSecret):
+		return invalidSecret
+	case errors.Is(err, identities.ErrInvalidCourseID):
+		return invalidCourseID
+	default:
+		return unexpectedError
+	}
+}
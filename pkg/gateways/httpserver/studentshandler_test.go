@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"golang.org/x/text/language"
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
 	"github.com/tccav/identity-service/pkg/domain/identities"
@@ -29,7 +30,7 @@ func TestStudentsHandler_RegisterStudent(t *testing.T) {
 		expectedUC       string
 		expectedUCErr    error
 		expectedResponse any
-		expectedStatus   any
+		expectedStatus   int
 	}{
 		{
 			name:             "should successfully create student",
@@ -47,38 +48,82 @@ func TestStudentsHandler_RegisterStudent(t *testing.T) {
 		{
 			name:             "should fail due to invalid student id",
 			requestBody:      hsfixtures.InvalidIDRequestBody,
-			expectedUCErr:    entities.ErrInvalidStudentID,
-			expectedResponse: invalidStudentID,
-			expectedStatus:   http.StatusBadRequest,
+			expectedUCErr:    newValidationError(entities.FieldError{Field: "id", Err: entities.ErrInvalidStudentID}),
+			expectedResponse: newValidationPayload(fieldPayload("id", invalidStudentID)),
+			expectedStatus:   http.StatusUnprocessableEntity,
 		},
 		{
 			name:             "should fail due to invalid course id",
 			requestBody:      hsfixtures.InvalidCourseIDRequestBody,
-			expectedUCErr:    identities.ErrInvalidCourseID,
-			expectedResponse: invalidCourseID,
-			expectedStatus:   http.StatusBadRequest,
+			expectedUCErr:    newValidationError(entities.FieldError{Field: "course_id", Err: identities.ErrInvalidCourseID}),
+			expectedResponse: newValidationPayload(fieldPayload("course_id", invalidCourseID)),
+			expectedStatus:   http.StatusUnprocessableEntity,
 		},
 		{
 			name:             "should fail due to invalid cpf",
 			requestBody:      hsfixtures.InvalidCPFRequestBody,
-			expectedUCErr:    entities.ErrInvalidCPF,
-			expectedResponse: invalidCPF,
-			expectedStatus:   http.StatusBadRequest,
+			expectedUCErr:    newValidationError(entities.FieldError{Field: "cpf", Err: entities.ErrInvalidCPF}),
+			expectedResponse: newValidationPayload(fieldPayload("cpf", invalidCPF)),
+			expectedStatus:   http.StatusUnprocessableEntity,
 		},
 		{
 			name:             "should fail due to invalid email",
 			requestBody:      hsfixtures.InvalidEmailRequestBody,
-			expectedUCErr:    entities.ErrInvalidEmail,
-			expectedResponse: invalidEmail,
-			expectedStatus:   http.StatusBadRequest,
+			expectedUCErr:    newValidationError(entities.FieldError{Field: "email", Err: entities.ErrInvalidEmail}),
+			expectedResponse: newValidationPayload(fieldPayload("email", invalidEmail)),
+			expectedStatus:   http.StatusUnprocessableEntity,
 		},
 		{
 			name:             "should fail due to invalid birth date",
 			requestBody:      hsfixtures.InvalidBirthDateRequestBody,
-			expectedUCErr:    entities.ErrInvalidBirthDate,
-			expectedResponse: invalidBirthDate,
+			expectedUCErr:    newValidationError(entities.FieldError{Field: "birth_date", Err: entities.ErrInvalidBirthDate}),
+			expectedResponse: newValidationPayload(fieldPayload("birth_date", invalidBirthDate)),
+			expectedStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "should report every invalid field at once",
+			requestBody: hsfixtures.InvalidFieldsRequestBody,
+			expectedUCErr: newValidationError(
+				entities.FieldError{Field: "id", Err: entities.ErrInvalidStudentID},
+				entities.FieldError{Field: "name", Err: entities.ErrInvalidName},
+				entities.FieldError{Field: "secret", Err: entities.ErrInvalidSecret},
+				entities.FieldError{Field: "cpf", Err: entities.ErrInvalidCPF},
+				entities.FieldError{Field: "email", Err: entities.ErrInvalidEmail},
+				entities.FieldError{Field: "birth_date", Err: entities.ErrInvalidBirthDate},
+				entities.FieldError{Field: "course_id", Err: identities.ErrInvalidCourseID},
+			),
+			expectedResponse: newValidationPayload(
+				fieldPayload("id", invalidStudentID),
+				fieldPayload("name", invalidName),
+				fieldPayload("secret", invalidSecret),
+				fieldPayload("cpf", invalidCPF),
+				fieldPayload("email", invalidEmail),
+				fieldPayload("birth_date", invalidBirthDate),
+				fieldPayload("course_id", invalidCourseID),
+			),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:             "should fail because student already exists",
+			requestBody:      hsfixtures.ValidStudentRequestBody,
+			expectedUCErr:    identities.ErrStudentAlreadyExists,
+			expectedResponse: invalidStudentID,
 			expectedStatus:   http.StatusBadRequest,
 		},
+		{
+			name:             "should fail because cpf is already registered",
+			requestBody:      hsfixtures.ValidStudentRequestBody,
+			expectedUCErr:    identities.ErrCPFAlreadyRegistered,
+			expectedResponse: cpfAlreadyRegistered,
+			expectedStatus:   http.StatusConflict,
+		},
+		{
+			name:             "should fail because email is already registered",
+			requestBody:      hsfixtures.ValidStudentRequestBody,
+			expectedUCErr:    identities.ErrEmailAlreadyRegistered,
+			expectedResponse: emailAlreadyRegistered,
+			expectedStatus:   http.StatusConflict,
+		},
 		{
 			name:             "should fail due to unexpected error from use case",
 			requestBody:      hsfixtures.ValidStudentRequestBody,
@@ -98,7 +143,7 @@ func TestStudentsHandler_RegisterStudent(t *testing.T) {
 				return tc.expectedUC, tc.expectedUCErr
 			}}
 
-			expectedResponse, err := json.Marshal(tc.expectedResponse)
+			expectedResponse, err := json.Marshal(expectedPayload(tc.expectedResponse, tc.expectedStatus))
 			require.NoError(t, err)
 
 			w := httptest.NewRecorder()
@@ -118,3 +163,18 @@ func TestStudentsHandler_RegisterStudent(t *testing.T) {
 		})
 	}
 }
+
+func newValidationError(fields ...entities.FieldError) error {
+	return entities.ValidationError{Fields: fields}
+}
+
+func newValidationPayload(fields ...FieldHTTPError) ValidationHTTPError {
+	return ValidationHTTPError{
+		HTTPError: invalidFields.httpError(language.English, http.StatusUnprocessableEntity, ""),
+		Fields:    fields,
+	}
+}
+
+func fieldPayload(field string, p problem) FieldHTTPError {
+	return FieldHTTPError{Field: field, Code: string(p), Detail: localize(language.English, p).detail}
+}
@@ -0,0 +1,279 @@
+package httpserver
+
+import (
+	"encoding/csv"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/importfile"
+)
+
+const csvContentType = "text/csv"
+
+type StudentsImportResponse struct {
+	DryRun      bool                `json:"dry_run" swaggertype:"boolean" example:"true"`
+	Total       int                 `json:"total" swaggertype:"integer" example:"2"`
+	Valid       int                 `json:"valid" swaggertype:"integer" example:"1"`
+	Imported    int                 `json:"imported" swaggertype:"integer" example:"0"`
+	EventFailed int                 `json:"event_failed" swaggertype:"integer" example:"0"`
+	Failed      int                 `json:"failed" swaggertype:"integer" example:"1"`
+	Rows        []ImportRowResponse `json:"rows"`
+}
+
+type ImportRowResponse struct {
+	Line      int              `json:"line" swaggertype:"integer" example:"2"`
+	StudentID string           `json:"student_id" swaggertype:"string" example:"201210204310"`
+	Status    string           `json:"status" swaggertype:"string" enums:"valid,imported,event_failed,failed" example:"failed"`
+	Errors    []FieldHTTPError `json:"errors,omitempty"`
+}
+
+// StudentsImportConfig tells how long an import request may take, which is usually longer than the server
+// timeouts meant for the other requests.
+type StudentsImportConfig interface {
+	ImportTimeout() time.Duration
+}
+
+type StudentsImportHandler struct {
+	logger  *zap.Logger
+	useCase identities.ImportStudentsUseCases
+	config  StudentsImportConfig
+}
+
+func NewStudentsImportHandler(logger *zap.Logger, useCase identities.ImportStudentsUseCases, config StudentsImportConfig) StudentsImportHandler {
+	return StudentsImportHandler{
+		logger:  logger,
+		useCase: useCase,
+		config:  config,
+	}
+}
+
+// ImportStudents ...
+// ShowEntity godoc
+// @Summary Import students from a CSV or JSONL file
+// @Description The file is sent as the request body, a CSV must have a header with the registration request fields.
+// @Description Every row is validated as a registration, valid ones are stored in batches and have their events published.
+// @Description The report lists every row, send Accept text/csv to download it as a CSV file.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.students.write scope"
+// @Param dry_run query bool false "Only validate the file, nothing is stored"
+// @Param format query string false "File format, taken from the Content-Type when not informed" Enums(csv, jsonl)
+// @Param request body string true "Import file"
+// @Accept text/csv,application/x-ndjson
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,text/csv,application/problem+json
+// @Success 200 {object} StudentsImportResponse
+// @Failure 400 {object} HTTPError
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 422 {object} ValidationHTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/students/imports [post]
+func (h StudentsImportHandler) ImportStudents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// the server deadlines are not suited for big files, the error is ignored when the writer does not support them
+	controller := http.NewResponseController(w)
+	deadline := time.Now().Add(h.config.ImportTimeout())
+	_ = controller.SetReadDeadline(deadline)
+	_ = controller.SetWriteDeadline(deadline)
+
+	// only a missing dry_run is taken as false, a real import must not run on a misspelled one
+	var dryRun bool
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			h.logger.Error("invalid dry_run received", zap.Error(err))
+
+			var validationErr entities.ValidationError
+			validationErr.Add("dry_run", err)
+			err = sendValidationProblem(w, r, validationErr, func(error) problem { return invalidDryRun })
+			if err != nil {
+				h.logger.Error("failed to send error json response", zap.Error(err))
+			}
+			return
+		}
+	}
+
+	reader, err := importfile.NewReader(importFormat(r), r.Body)
+	if err != nil {
+		h.logger.Error("invalid import file received", zap.Error(err))
+		err = sendProblem(w, r, http.StatusBadRequest, invalidImportFile)
+		if err != nil {
+			h.logger.Error("failed to send error response", zap.Error(err))
+		}
+		return
+	}
+
+	report, err := h.useCase.ImportStudents(ctx, identities.ImportStudentsInput{
+		Rows:   reader,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		h.logger.Error("unable to import students", zap.Error(err))
+		err = sendProblem(w, r, http.StatusInternalServerError, unexpectedError)
+		if err != nil {
+			h.logger.Error("failed to send error json response", zap.Error(err))
+		}
+		return
+	}
+
+	response := newStudentsImportResponse(report, requestLanguage(r))
+	h.logger.Info("students import finished",
+		zap.Bool("dry_run", response.DryRun),
+		zap.Int("total", response.Total),
+		zap.Int("failed", response.Failed),
+	)
+
+	if acceptsCSV(r) {
+		err = sendImportReportCSV(w, response)
+	} else {
+		err = sendJSON(w, http.StatusOK, response)
+	}
+	if err != nil {
+		h.logger.Error("failed to send import report", zap.Error(err))
+	}
+}
+
+// importFormat takes the format query parameter, falling back to the request content type.
+func importFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+	switch mediaType {
+	case csvContentType:
+		return importfile.FormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return importfile.FormatJSONL
+	default:
+		return mediaType
+	}
+}
+
+func acceptsCSV(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("accept"), ",") {
+		mediaType, _, _ := mime.ParseMediaType(accepted)
+		if mediaType == csvContentType {
+			return true
+		}
+	}
+	return false
+}
+
+func newStudentsImportResponse(report identities.ImportReport, lang language.Tag) StudentsImportResponse {
+	response := StudentsImportResponse{
+		DryRun:      report.DryRun,
+		Total:       len(report.Rows),
+		Valid:       report.Count(identities.ImportRowValid),
+		Imported:    report.Count(identities.ImportRowImported),
+		EventFailed: report.Count(identities.ImportRowEventFailed),
+		Failed:      report.Count(identities.ImportRowFailed),
+		Rows:        make([]ImportRowResponse, 0, len(report.Rows)),
+	}
+
+	for _, row := range report.Rows {
+		response.Rows = append(response.Rows, ImportRowResponse{
+			Line:      row.Line,
+			StudentID: row.StudentID,
+			Status:    string(row.Status),
+			Errors:    importRowErrors(row, lang),
+		})
+	}
+
+	return response
+}
+
+// importRowErrors reports a row error the same way the registration does, one entry per invalid field.
+// Errors that are not about a single field are reported without one.
+func importRowErrors(row identities.ImportRowResult, lang language.Tag) []FieldHTTPError {
+	if row.Err == nil {
+		return nil
+	}
+
+	var validationErr entities.ValidationError
+	if errors.As(row.Err, &validationErr) {
+		fields := make([]FieldHTTPError, 0, len(validationErr.Fields))
+		for _, field := range validationErr.Fields {
+			p := fieldProblem(field.Err)
+			fields = append(fields, FieldHTTPError{
+				Field:  field.Field,
+				Code:   string(p),
+				Detail: localize(lang, p).detail,
+			})
+		}
+		return fields
+	}
+
+	var (
+		p     problem
+		field string
+	)
+	switch {
+	case row.Status == identities.ImportRowEventFailed:
+		p = eventNotPublished
+	case errors.Is(row.Err, identities.ErrMalformedImportRow):
+		p = malformedImportRow
+	case errors.Is(row.Err, identities.ErrDuplicatedImportRow):
+		p = duplicatedImportRow
+	case errors.Is(row.Err, identities.ErrStudentAlreadyExists):
+		p, field = studentAlreadyExists, "id"
+	case errors.Is(row.Err, identities.ErrCPFAlreadyRegistered):
+		p, field = cpfAlreadyRegistered, "cpf"
+	case errors.Is(row.Err, identities.ErrEmailAlreadyRegistered):
+		p, field = emailAlreadyRegistered, "email"
+	default:
+		p = unexpectedError
+	}
+
+	return []FieldHTTPError{{
+		Field:  field,
+		Code:   string(p),
+		Detail: localize(lang, p).detail,
+	}}
+}
+
+// sendImportReportCSV sends the report as a downloadable file with one line per row error, rows without
+// errors get a single line.
+func sendImportReportCSV(w http.ResponseWriter, response StudentsImportResponse) error {
+	w.Header().Add("content-type", csvContentType+"; charset=utf-8")
+	w.Header().Add("content-disposition", `attachment; filename="students-import-report.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"line", "student_id", "status", "field", "err_code", "detail"})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range response.Rows {
+		line := strconv.Itoa(row.Line)
+		if len(row.Errors) == 0 {
+			err = writer.Write([]string{line, row.StudentID, row.Status, "", "", ""})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, rowErr := range row.Errors {
+			err = writer.Write([]string{line, row.StudentID, row.Status, rowErr.Field, rowErr.Code, rowErr.Detail})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,171 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+)
+
+type importConfig time.Duration
+
+func (c importConfig) ImportTimeout() time.Duration {
+	return time.Duration(c)
+}
+
+const importCSV = "id,name,secret,cpf,email,birth_date,course_id\n" +
+	"201320509911,Pedro Lopes,secret_password,11111111030,plopes@ol.com,1994-03-19,1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10\n"
+
+func TestStudentsImportHandler_ImportStudents(t *testing.T) {
+	t.Parallel()
+
+	var cpfErr entities.ValidationError
+	cpfErr.Add("cpf", entities.ErrInvalidCPF)
+
+	report := identities.ImportReport{
+		Rows: []identities.ImportRowResult{
+			{Line: 2, StudentID: "201320509911", Status: identities.ImportRowImported},
+			{Line: 3, StudentID: "201320509912", Status: identities.ImportRowFailed, Err: cpfErr},
+			{Line: 4, StudentID: "201320509913", Status: identities.ImportRowFailed, Err: identities.ErrEmailAlreadyRegistered},
+		},
+	}
+
+	tt := []struct {
+		name             string
+		target           string
+		contentType      string
+		accept           string
+		requestBody      string
+		expectedDryRun   bool
+		expectedUCErr    error
+		expectedResponse string
+		expectedStatus   int
+	}{
+		{
+			name:        "should import students and report every row",
+			target:      "/v1/identities/students/imports",
+			contentType: "text/csv",
+			requestBody: importCSV,
+			expectedResponse: mustMarshal(t, StudentsImportResponse{
+				Total:    3,
+				Imported: 1,
+				Failed:   2,
+				Rows: []ImportRowResponse{
+					{Line: 2, StudentID: "201320509911", Status: "imported"},
+					{Line: 3, StudentID: "201320509912", Status: "failed", Errors: []FieldHTTPError{
+						{Field: "cpf", Code: string(invalidCPF), Detail: "Invalid CPF was sent"},
+					}},
+					{Line: 4, StudentID: "201320509913", Status: "failed", Errors: []FieldHTTPError{
+						{Field: "email", Code: string(emailAlreadyRegistered), Detail: "Email is already registered to another student"},
+					}},
+				},
+			}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "should send report as csv",
+			target:         "/v1/identities/students/imports?dry_run=true&format=jsonl",
+			accept:         "text/csv",
+			requestBody:    `{"id":"201320509911"}`,
+			expectedDryRun: true,
+			expectedResponse: "line,student_id,status,field,err_code,detail\n" +
+				"2,201320509911,imported,,,\n" +
+				"3,201320509912,failed,cpf,identity_service.error.invalid_cpf,Invalid CPF was sent\n" +
+				"4,201320509913,failed,email,identity_service.error.email_already_registered,Email is already registered to another student",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "should fail due to unknown format",
+			target:           "/v1/identities/students/imports",
+			contentType:      "application/json",
+			requestBody:      importCSV,
+			expectedResponse: mustMarshal(t, expectedPayload(invalidImportFile, http.StatusBadRequest)),
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "should fail due to invalid dry run",
+			target:           "/v1/identities/students/imports?dry_run=yes",
+			contentType:      "text/csv",
+			requestBody:      importCSV,
+			expectedResponse: mustMarshal(t, newValidationPayload(fieldPayload("dry_run", invalidDryRun))),
+			expectedStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:             "should fail due to csv missing columns",
+			target:           "/v1/identities/students/imports?format=csv",
+			requestBody:      "id,name\n",
+			expectedResponse: mustMarshal(t, expectedPayload(invalidImportFile, http.StatusBadRequest)),
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "should fail due to unexpected error from use case",
+			target:           "/v1/identities/students/imports",
+			contentType:      "text/csv",
+			requestBody:      importCSV,
+			expectedUCErr:    errors.New("unexpected"),
+			expectedResponse: mustMarshal(t, expectedPayload(unexpectedError, http.StatusInternalServerError)),
+			expectedStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			logger := zap.NewNop()
+			useCase := idmocks.ImportStudentsUseCasesMock{
+				ImportStudentsFunc: func(ctx context.Context, input identities.ImportStudentsInput) (identities.ImportReport, error) {
+					assert.Equal(t, tc.expectedDryRun, input.DryRun)
+					_, err := input.Rows.Next()
+					assert.NoError(t, err)
+
+					got := report
+					got.DryRun = input.DryRun
+					return got, tc.expectedUCErr
+				},
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.requestBody))
+			r.Header.Set("content-type", tc.contentType)
+			r.Header.Set("accept", tc.accept)
+
+			h := NewStudentsImportHandler(logger, &useCase, importConfig(time.Minute))
+
+			// test
+			h.ImportStudents(w, r)
+
+			// assert
+			body, err := io.ReadAll(w.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResponse, strings.TrimSpace(string(body)))
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.accept == "text/csv" {
+				assert.Equal(t, `attachment; filename="students-import-report.csv"`, w.Header().Get("content-disposition"))
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, payload any) string {
+	t.Helper()
+
+	b, err := json.Marshal(payload)
+	require.NoError(t, err, fmt.Sprintf("marshaling %T", payload))
+	return string(b)
+}
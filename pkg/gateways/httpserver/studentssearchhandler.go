@@ -0,0 +1,150 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type StudentResponse struct {
+	ID           string `json:"id" swaggertype:"string" example:"201210204310"`
+	Name         string `json:"name" swaggertype:"string" example:"John Doe"`
+	CPF          string `json:"cpf" swaggertype:"string" example:"11111111030"`
+	Email        string `json:"email" swaggertype:"string" format:"email" example:"jdoe@ol.com"`
+	BirthDate    string `json:"birth_date" swaggertype:"string" format:"date" example:"1990-10-18"`
+	Status       string `json:"status" swaggertype:"string" enums:"pending,active,suspended,graduated,cancelled" example:"active"`
+	CourseID     string `json:"course_id,omitempty" swaggertype:"string" format:"uuidv4" example:"1f6a4d3a-38c7-43fe-9790-2408fe595c93"`
+	RegisteredAt string `json:"registered_at" swaggertype:"string" format:"datetime" example:"2023-07-09T12:00:00Z"`
+}
+
+type StudentsPageResponse struct {
+	Students   []StudentResponse `json:"students"`
+	NextCursor string            `json:"next_cursor,omitempty" swaggertype:"string" example:"MjAyMy0wNy0wOVQxMjowMDowMFp8MjAxMjEwMjA0MzEw"`
+}
+
+type StudentsSearchHandler struct {
+	logger  *zap.Logger
+	useCase identities.StudentsSearchUseCases
+}
+
+func NewStudentsSearchHandler(logger *zap.Logger, useCase identities.StudentsSearchUseCases) StudentsSearchHandler {
+	return StudentsSearchHandler{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+// SearchStudents ...
+// ShowEntity godoc
+// @Summary Search students
+// @Description Students are listed from the latest registered one, every informed filter must match.
+// @Description The name is matched ignoring accents, by any of its words, a part of it or a similar name.
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.students.read scope"
+// @Param name query string false "Student name"
+// @Param email query string false "Student email"
+// @Param cpf query string false "Student CPF, with or without punctuation"
+// @Param status query string false "Student status" Enums(pending, active, suspended, graduated, cancelled)
+// @Param course_id query string false "Course ID"
+// @Param registered_from query string false "First registration day, in the yyyy-mm-dd format"
+// @Param registered_to query string false "Last registration day, in the yyyy-mm-dd format"
+// @Param cursor query string false "Cursor sent in the previous page"
+// @Param limit query int false "Page size, up to 100" default(20)
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,application/problem+json
+// @Success 200 {object} StudentsPageResponse
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 422 {object} ValidationHTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/students [get]
+func (h StudentsSearchHandler) SearchStudents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	input := identities.SearchStudentsInput{
+		Name:           query.Get("name"),
+		Email:          query.Get("email"),
+		CPF:            query.Get("cpf"),
+		Status:         query.Get("status"),
+		CourseID:       query.Get("course_id"),
+		RegisteredFrom: query.Get("registered_from"),
+		RegisteredTo:   query.Get("registered_to"),
+		Cursor:         query.Get("cursor"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		input.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			var validationErr entities.ValidationError
+			validationErr.Add("limit", identities.ErrInvalidPageLimit)
+			err = sendValidationProblem(w, r, validationErr, searchFieldProblem)
+			if err != nil {
+				h.logger.Error("failed to send error json response", zap.Error(err))
+			}
+			return
+		}
+	}
+
+	page, err := h.useCase.SearchStudents(ctx, input)
+	if err != nil {
+		h.logger.Error("unable to search students", zap.Error(err))
+
+		var validationErr entities.ValidationError
+		if errors.As(err, &validationErr) {
+			err = sendValidationProblem(w, r, validationErr, searchFieldProblem)
+		} else {
+			err = sendProblem(w, r, http.StatusInternalServerError, unexpectedError)
+		}
+		if err != nil {
+			h.logger.Error("failed to send error json response", zap.Error(err))
+		}
+		return
+	}
+
+	response := StudentsPageResponse{
+		Students:   make([]StudentResponse, 0, len(page.Students)),
+		NextCursor: page.NextCursor,
+	}
+	for _, student := range page.Students {
+		response.Students = append(response.Students, StudentResponse{
+			ID:           student.ID,
+			Name:         student.Name,
+			CPF:          student.CPF,
+			Email:        student.Email,
+			BirthDate:    student.BirthDate.Format(time.DateOnly),
+			Status:       string(student.Status),
+			CourseID:     student.CourseID,
+			RegisteredAt: student.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	err = sendJSON(w, http.StatusOK, response)
+	if err != nil {
+		h.logger.Error("failed to send json response", zap.Error(err))
+	}
+}
+
+func searchFieldProblem(err error) problem {
+	switch {
+	case errors.Is(err, entities.ErrInvalidStudentStatus):
+		return invalidStudentStatus
+	case errors.Is(err, identities.ErrInvalidCourseID):
+		return invalidCourseID
+	case errors.Is(err, identities.ErrInvalidRegistrationDate):
+		return invalidRegistrationDate
+	case errors.Is(err, identities.ErrInvalidPageCursor):
+		return invalidPageCursor
+	case errors.Is(err, identities.ErrInvalidPageLimit):
+		return invalidPageLimit
+	default:
+		return unexpectedError
+	}
+}
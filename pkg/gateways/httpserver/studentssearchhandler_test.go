@@ -0,0 +1,139 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+)
+
+func TestStudentsSearchHandler_SearchStudents(t *testing.T) {
+	t.Parallel()
+
+	student := entities.Student{
+		ID:        "201116548712",
+		Name:      "João da Silva",
+		CPF:       "11111111030",
+		Email:     "jsilva@ol.com",
+		BirthDate: time.Date(1994, 3, 19, 0, 0, 0, 0, time.UTC),
+		Status:    entities.StudentStatusActive,
+		CourseID:  "1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10",
+		CreatedAt: time.Date(2023, 7, 9, 12, 0, 0, 0, time.UTC),
+	}
+
+	var statusErr entities.ValidationError
+	statusErr.Add("status", entities.ErrInvalidStudentStatus)
+	statusErr.Add("cursor", identities.ErrInvalidPageCursor)
+
+	var limitErr entities.ValidationError
+	limitErr.Add("limit", identities.ErrInvalidPageLimit)
+
+	tt := []struct {
+		name             string
+		query            string
+		expectedInput    identities.SearchStudentsInput
+		expectedUC       identities.StudentsPage
+		expectedUCErr    error
+		expectedResponse any
+		expectedStatus   int
+	}{
+		{
+			name:  "should list students page",
+			query: "?name=joao&status=active&registered_from=2023-07-01&limit=1",
+			expectedInput: identities.SearchStudentsInput{
+				Name:           "joao",
+				Status:         "active",
+				RegisteredFrom: "2023-07-01",
+				Limit:          1,
+			},
+			expectedUC: identities.StudentsPage{Students: []entities.Student{student}, NextCursor: "next"},
+			expectedResponse: StudentsPageResponse{
+				Students: []StudentResponse{{
+					ID:           student.ID,
+					Name:         student.Name,
+					CPF:          student.CPF,
+					Email:        student.Email,
+					BirthDate:    "1994-03-19",
+					Status:       "active",
+					CourseID:     student.CourseID,
+					RegisteredAt: "2023-07-09T12:00:00Z",
+				}},
+				NextCursor: "next",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "should answer empty list when nothing is found",
+			expectedResponse: StudentsPageResponse{Students: []StudentResponse{}},
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:             "should fail due to non numeric limit",
+			query:            "?limit=ten",
+			expectedResponse: limitErr,
+			expectedStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:             "should report every invalid filter",
+			query:            "?status=expelled&cursor=invalid",
+			expectedInput:    identities.SearchStudentsInput{Status: "expelled", Cursor: "invalid"},
+			expectedUCErr:    statusErr,
+			expectedResponse: statusErr,
+			expectedStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:             "should fail due to unexpected error from use case",
+			expectedUCErr:    errors.New("unexpected"),
+			expectedResponse: unexpectedError,
+			expectedStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			logger := zap.NewNop()
+			useCase := idmocks.StudentsSearchUseCasesMock{
+				SearchStudentsFunc: func(ctx context.Context, input identities.SearchStudentsInput) (identities.StudentsPage, error) {
+					assert.Equal(t, tc.expectedInput, input)
+					return tc.expectedUC, tc.expectedUCErr
+				},
+			}
+
+			var expectedResponse string
+			if validationErr, ok := tc.expectedResponse.(entities.ValidationError); ok {
+				w := httptest.NewRecorder()
+				require.NoError(t, sendValidationProblem(w, httptest.NewRequest(http.MethodGet, "/", nil), validationErr, searchFieldProblem))
+				expectedResponse = strings.TrimSpace(w.Body.String())
+			} else {
+				expectedResponse = mustMarshal(t, expectedPayload(tc.expectedResponse, tc.expectedStatus))
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/identities/students%s", tc.query), nil)
+
+			h := NewStudentsSearchHandler(logger, &useCase)
+
+			// test
+			h.SearchStudents(w, r)
+
+			// assert
+			assert.Equal(t, expectedResponse, strings.TrimSpace(w.Body.String()))
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
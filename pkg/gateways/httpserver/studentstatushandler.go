@@ -0,0 +1,122 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type ChangeStudentStatusRequest struct {
+	Status string `json:"status" swaggertype:"string" enums:"pending,active,suspended,graduated,cancelled" example:"suspended"`
+	Reason string `json:"reason" swaggertype:"string" example:"Disciplinary suspension"`
+}
+
+type ChangeStudentStatusResponse struct {
+	StudentID      string `json:"student_id" swaggertype:"string" example:"201210204310"`
+	PreviousStatus string `json:"previous_status" swaggertype:"string" example:"active"`
+	Status         string `json:"status" swaggertype:"string" example:"suspended"`
+	ChangedAt      string `json:"changed_at" swaggertype:"string" format:"datetime" example:"2023-10-18T19:32:00.000Z"`
+}
+
+type StudentStatusHandler struct {
+	logger  *zap.Logger
+	useCase identities.StudentStatusUseCases
+}
+
+func NewStudentStatusHandler(logger *zap.Logger, useCase identities.StudentStatusUseCases) StudentStatusHandler {
+	return StudentStatusHandler{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+// ChangeStudentStatus ...
+// ShowEntity godoc
+// @Summary Change a student status
+// @Tags Administration
+// @Param X-API-Key header string true "Admin API key with the identity.students.write scope"
+// @Param id path string true "Student ID"
+// @Param request body ChangeStudentStatusRequest true "Target status"
+// @Accept json
+// @Param Accept-Language header string false "Language of the error messages, en or pt-BR" default(en)
+// @Produce json,application/problem+json
+// @Success 200 {object} ChangeStudentStatusResponse
+// @Failure 400 {object} HTTPError
+// @Failure 401 {object} HTTPError
+// @Failure 403 {object} HTTPError
+// @Failure 404 {object} HTTPError
+// @Failure 409 {object} HTTPError
+// @Failure 422 {object} HTTPError
+// @Failure 500 {object} HTTPError
+// @Router /v1/identities/students/{id}/status [post]
+func (h StudentStatusHandler) ChangeStudentStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var reqBody ChangeStudentStatusRequest
+	err := json.NewDecoder(r.Body).Decode(&reqBody)
+	if err != nil {
+		h.logger.Error("invalid json received", zap.Error(err))
+		err = sendProblem(w, r, http.StatusBadRequest, invalidJSON)
+		if err != nil {
+			h.logger.Error("failed to send error response", zap.Error(err))
+		}
+		return
+	}
+
+	transition, err := h.useCase.ChangeStudentStatus(ctx, identities.ChangeStudentStatusInput{
+		StudentID: chi.URLParam(r, "id"),
+		Status:    reqBody.Status,
+		Reason:    reqBody.Reason,
+	})
+	if err != nil {
+		h.logger.Error("unable to change student status", zap.Error(err))
+
+		var (
+			errorPayload problem
+			statusCode   int
+		)
+		switch {
+		case errors.Is(err, identities.ErrEmptyStudentID):
+			statusCode = http.StatusBadRequest
+			errorPayload = emptyStudentID
+		case errors.Is(err, entities.ErrInvalidStudentStatus):
+			statusCode = http.StatusBadRequest
+			errorPayload = invalidStudentStatus
+		case errors.Is(err, identities.ErrStudentNotFound):
+			statusCode = http.StatusNotFound
+			errorPayload = studentNotFound
+		case errors.Is(err, identities.ErrStudentStatusChanged):
+			statusCode = http.StatusConflict
+			errorPayload = studentStatusChanged
+		case errors.Is(err, entities.ErrInvalidStatusTransition):
+			statusCode = http.StatusUnprocessableEntity
+			errorPayload = invalidStatusTransition
+		default:
+			statusCode = http.StatusInternalServerError
+			errorPayload = unexpectedError
+		}
+
+		err = sendProblem(w, r, statusCode, errorPayload)
+		if err != nil {
+			h.logger.Error("failed to send error json response", zap.Error(err))
+		}
+		return
+	}
+
+	err = sendJSON(w, http.StatusOK, ChangeStudentStatusResponse{
+		StudentID:      transition.StudentID,
+		PreviousStatus: string(transition.From),
+		Status:         string(transition.To),
+		ChangedAt:      transition.ChangedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		h.logger.Error("failed to send json response", zap.Error(err))
+	}
+}
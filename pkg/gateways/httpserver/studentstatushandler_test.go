@@ -0,0 +1,135 @@
+package httpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+	"github.com/tccav/identity-service/pkg/gateways/httpserver/hsfixtures"
+)
+
+func TestStudentStatusHandler_ChangeStudentStatus(t *testing.T) {
+	t.Parallel()
+
+	validTransition := entities.StudentStatusTransition{
+		StudentID: "123451271",
+		From:      entities.StudentStatusActive,
+		To:        entities.StudentStatusSuspended,
+		ChangedAt: time.Now().UTC(),
+	}
+
+	tt := []struct {
+		name             string
+		requestBody      string
+		expectedUC       entities.StudentStatusTransition
+		expectedUCErr    error
+		expectedResponse any
+		expectedStatus   int
+	}{
+		{
+			name:        "should successfully change student status",
+			requestBody: hsfixtures.ValidChangeStudentStatusRequestBody,
+			expectedUC:  validTransition,
+			expectedResponse: ChangeStudentStatusResponse{
+				StudentID:      validTransition.StudentID,
+				PreviousStatus: "active",
+				Status:         "suspended",
+				ChangedAt:      validTransition.ChangedAt.Format(time.RFC3339),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:             "should fail due to invalid json",
+			requestBody:      hsfixtures.InvalidJSONRequestBody,
+			expectedResponse: invalidJSON,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "should fail due to invalid status",
+			requestBody:      `{"status": "expelled"}`,
+			expectedUCErr:    entities.ErrInvalidStudentStatus,
+			expectedResponse: invalidStudentStatus,
+			expectedStatus:   http.StatusBadRequest,
+		},
+		{
+			name:             "should fail because student does not exist",
+			requestBody:      hsfixtures.ValidChangeStudentStatusRequestBody,
+			expectedUCErr:    identities.ErrStudentNotFound,
+			expectedResponse: studentNotFound,
+			expectedStatus:   http.StatusNotFound,
+		},
+		{
+			name:             "should fail because transition is not allowed",
+			requestBody:      hsfixtures.ValidChangeStudentStatusRequestBody,
+			expectedUCErr:    entities.ErrInvalidStatusTransition,
+			expectedResponse: invalidStatusTransition,
+			expectedStatus:   http.StatusUnprocessableEntity,
+		},
+		{
+			name:             "should fail because status was changed concurrently",
+			requestBody:      hsfixtures.ValidChangeStudentStatusRequestBody,
+			expectedUCErr:    identities.ErrStudentStatusChanged,
+			expectedResponse: studentStatusChanged,
+			expectedStatus:   http.StatusConflict,
+		},
+		{
+			name:             "should fail due to unexpected error from use case",
+			requestBody:      hsfixtures.ValidChangeStudentStatusRequestBody,
+			expectedUCErr:    errors.New("unexpected"),
+			expectedResponse: unexpectedError,
+			expectedStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			logger := zap.NewNop()
+			useCase := idmocks.StudentStatusUseCasesMock{
+				ChangeStudentStatusFunc: func(
+					ctx context.Context,
+					input identities.ChangeStudentStatusInput,
+				) (entities.StudentStatusTransition, error) {
+					assert.Equal(t, "123451271", input.StudentID)
+					return tc.expectedUC, tc.expectedUCErr
+				},
+			}
+
+			expectedResponse, err := json.Marshal(expectedPayload(tc.expectedResponse, tc.expectedStatus))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(
+				http.MethodPost,
+				"/v1/identities/students/123451271/status",
+				bytes.NewReader([]byte(tc.requestBody)))
+
+			h := NewStudentStatusHandler(logger, &useCase)
+			router := chi.NewRouter()
+			router.Post("/v1/identities/students/{id}/status", h.ChangeStudentStatus)
+
+			// test
+			router.ServeHTTP(w, r)
+
+			// assert
+			assert.Equal(t, string(expectedResponse), strings.TrimSpace(w.Body.String()))
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
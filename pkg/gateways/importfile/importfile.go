@@ -0,0 +1,169 @@
+// Package importfile reads the students of a bulk import file, either a CSV with a header line or a
+// JSONL with one student object per line.
+package importfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown import file format")
+	ErrInvalidHeader = errors.New("invalid import file header")
+)
+
+// columns are the fields every import file must inform, named as in the registration request.
+var columns = []string{"id", "name", "secret", "cpf", "email", "birth_date", "course_id"}
+
+// NewReader returns the reader of the informed format.
+func NewReader(format string, r io.Reader) (identities.StudentsImportReader, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return NewCSVReader(r)
+	case FormatJSONL:
+		return NewJSONLReader(r), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+type CSVReader struct {
+	reader  *csv.Reader
+	indexes map[string]int
+}
+
+// NewCSVReader reads the header line right away, so a file missing any of the columns is refused before
+// any student is read. Columns can be in any order and unknown ones are ignored.
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHeader, err)
+	}
+
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		indexes[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	for _, column := range columns {
+		if _, ok := indexes[column]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidHeader, column)
+		}
+	}
+
+	return &CSVReader{
+		reader:  reader,
+		indexes: indexes,
+	}, nil
+}
+
+func (c *CSVReader) Next() (identities.StudentImportRow, error) {
+	record, err := c.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return identities.StudentImportRow{Line: parseErr.StartLine}, fmt.Errorf("%w: %s", identities.ErrMalformedImportRow, err)
+		}
+		return identities.StudentImportRow{}, err
+	}
+
+	// FieldPos only holds positions of the record read without errors
+	line, _ := c.reader.FieldPos(0)
+	if len(record) < len(c.indexes) {
+		return identities.StudentImportRow{Line: line}, fmt.Errorf("%w: expected %d fields, got %d", identities.ErrMalformedImportRow, len(c.indexes), len(record))
+	}
+
+	field := func(name string) string {
+		return strings.TrimSpace(record[c.indexes[name]])
+	}
+
+	return identities.StudentImportRow{
+		Line: line,
+		Input: identities.RegisterStudentInput{
+			ID:        field("id"),
+			Name:      field("name"),
+			Secret:    record[c.indexes["secret"]],
+			CPF:       field("cpf"),
+			Email:     field("email"),
+			BirthDate: field("birth_date"),
+			CourseID:  field("course_id"),
+		},
+	}, nil
+}
+
+// jsonlRow has the same fields as the registration request.
+type jsonlRow struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Secret    string `json:"secret"`
+	CPF       string `json:"cpf"`
+	Email     string `json:"email"`
+	BirthDate string `json:"birth_date"`
+	CourseID  string `json:"course_id"`
+}
+
+type JSONLReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{
+		scanner: bufio.NewScanner(r),
+	}
+}
+
+// Next skips blank lines, so files ending with a line break or with lines separating groups of students are
+// read without errors.
+func (j *JSONLReader) Next() (identities.StudentImportRow, error) {
+	for j.scanner.Scan() {
+		j.line++
+
+		content := bytes.TrimSpace(j.scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		var row jsonlRow
+		err := json.Unmarshal(content, &row)
+		if err != nil {
+			return identities.StudentImportRow{Line: j.line}, fmt.Errorf("%w: %s", identities.ErrMalformedImportRow, err)
+		}
+
+		return identities.StudentImportRow{
+			Line: j.line,
+			Input: identities.RegisterStudentInput{
+				ID:        row.ID,
+				Name:      row.Name,
+				Secret:    row.Secret,
+				CPF:       row.CPF,
+				Email:     row.Email,
+				BirthDate: row.BirthDate,
+				CourseID:  row.CourseID,
+			},
+		}, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		return identities.StudentImportRow{}, err
+	}
+	return identities.StudentImportRow{}, io.EOF
+}
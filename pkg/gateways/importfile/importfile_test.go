@@ -0,0 +1,135 @@
+package importfile
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+var pedro = identities.RegisterStudentInput{
+	ID:        "201320509911",
+	Name:      "Pedro Lopes",
+	Secret:    "secret_password",
+	CPF:       "11111111030",
+	Email:     "plopes@ol.com",
+	BirthDate: "1994-03-19",
+	CourseID:  "1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10",
+}
+
+type readResult struct {
+	line  int
+	input identities.RegisterStudentInput
+	err   error
+}
+
+func readAll(t *testing.T, reader identities.StudentsImportReader) []readResult {
+	t.Helper()
+
+	var results []readResult
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return results
+		}
+		if err != nil && !errors.Is(err, identities.ErrMalformedImportRow) {
+			require.NoError(t, err)
+		}
+		results = append(results, readResult{line: row.Line, input: row.Input, err: err})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		format  string
+		content string
+		want    []readResult
+		wantErr error
+	}{
+		{
+			name:   "should read csv with columns in any order",
+			format: "CSV",
+			content: "\ufeffcourse_id,id,name,secret,cpf,email,birth_date,notes\n" +
+				"1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10,201320509911,Pedro Lopes,secret_password,11111111030,plopes@ol.com,1994-03-19,ignored\n",
+			want: []readResult{{line: 2, input: pedro}},
+		},
+		{
+			name:   "should report malformed csv rows and keep reading",
+			format: FormatCSV,
+			content: "id,name,secret,cpf,email,birth_date,course_id\n" +
+				"201320509911,Pedro Lopes\n" +
+				"201320509911,Pedro Lopes,secret_password,11111111030,plopes@ol.com,1994-03-19,1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10\n",
+			want: []readResult{
+				{line: 2, err: identities.ErrMalformedImportRow},
+				{line: 3, input: pedro},
+			},
+		},
+		{
+			name:   "should report csv rows with malformed quotes and keep reading",
+			format: FormatCSV,
+			content: "id,name,secret,cpf,email,birth_date,course_id\n" +
+				"201320509911,Pedro \"Lopes,secret_password,11111111030,plopes@ol.com,1994-03-19,1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10\n" +
+				"201320509911,Pedro Lopes,secret_password,11111111030,plopes@ol.com,1994-03-19,1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10\n" +
+				"201320509911,\"Pedro Lopes,secret_password\n",
+			want: []readResult{
+				{line: 2, err: identities.ErrMalformedImportRow},
+				{line: 3, input: pedro},
+				{line: 4, err: identities.ErrMalformedImportRow},
+			},
+		},
+		{
+			name:    "should refuse csv missing a column",
+			format:  FormatCSV,
+			content: "id,name,secret,cpf,email,birth_date\n",
+			wantErr: ErrInvalidHeader,
+		},
+		{
+			name:   "should read jsonl skipping blank lines",
+			format: FormatJSONL,
+			content: `{"id":"201320509911","name":"Pedro Lopes","secret":"secret_password","cpf":"11111111030","email":"plopes@ol.com","birth_date":"1994-03-19","course_id":"1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10"}` +
+				"\n\n{invalid\n",
+			want: []readResult{
+				{line: 1, input: pedro},
+				{line: 3, err: identities.ErrMalformedImportRow},
+			},
+		},
+		{
+			name:    "should refuse unknown format",
+			format:  "xlsx",
+			wantErr: ErrUnknownFormat,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			// test
+			reader, err := NewReader(tc.format, strings.NewReader(tc.content))
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got := readAll(t, reader)
+			require.Len(t, got, len(tc.want))
+			for i, want := range tc.want {
+				assert.Equal(t, want.line, got[i].line)
+				assert.ErrorIs(t, got[i].err, want.err)
+				if want.err == nil {
+					assert.Equal(t, want.input, got[i].input)
+				}
+			}
+		})
+	}
+}
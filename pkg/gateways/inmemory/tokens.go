@@ -0,0 +1,142 @@
+package inmemory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+// TokensRepository keeps the tokens in the process memory, so it is only meant for single replica deployments
+// and tests. Expired tokens are ignored right away, they are only evicted by RunEviction or when their student
+// sessions are listed.
+type TokensRepository struct {
+	mu       *sync.RWMutex
+	tokens   map[string]entities.Token
+	students map[string]map[string]struct{}
+}
+
+func NewTokensRepository() TokensRepository {
+	return TokensRepository{
+		mu:       &sync.RWMutex{},
+		tokens:   map[string]entities.Token{},
+		students: map[string]map[string]struct{}{},
+	}
+}
+
+func (t TokensRepository) Register(_ context.Context, token entities.Token) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if stored, ok := t.tokens[token.ID]; ok {
+		if !expired(stored, time.Now()) {
+			return nil
+		}
+		t.delete(stored)
+	}
+
+	// the signed token is never kept, only its digest
+	token.Hash = ""
+	t.tokens[token.ID] = token
+	if t.students[token.UserID] == nil {
+		t.students[token.UserID] = map[string]struct{}{}
+	}
+	t.students[token.UserID][token.ID] = struct{}{}
+
+	return nil
+}
+
+func (t TokensRepository) GetDigest(_ context.Context, id string) (string, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	token, ok := t.tokens[id]
+	if !ok || expired(token, time.Now()) {
+		return "", identities.ErrTokenNotEmitted
+	}
+
+	return token.Digest, nil
+}
+
+func (t TokensRepository) ListStudentSessions(_ context.Context, studentID string) ([]entities.Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	tokens := make([]entities.Token, 0, len(t.students[studentID]))
+	for id := range t.students[studentID] {
+		token := t.tokens[id]
+		if expired(token, now) {
+			t.delete(token)
+			continue
+		}
+
+		tokens = append(tokens, entities.Token{
+			ID:             token.ID,
+			UserID:         token.UserID,
+			ExpirationDate: token.ExpirationDate.UTC(),
+		})
+	}
+
+	return tokens, nil
+}
+
+func (t TokensRepository) RevokeStudentSessions(_ context.Context, studentID string) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var revoked int
+	for id := range t.students[studentID] {
+		token := t.tokens[id]
+		if !expired(token, now) {
+			revoked++
+		}
+		t.delete(token)
+	}
+
+	return revoked, nil
+}
+
+// RunEviction deletes the expired tokens every interval until the context is done.
+func (t TokensRepository) RunEviction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			t.evict(now)
+		}
+	}
+}
+
+func (t TokensRepository) evict(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, token := range t.tokens {
+		if expired(token, now) {
+			t.delete(token)
+		}
+	}
+}
+
+// delete must be called with the lock held.
+func (t TokensRepository) delete(token entities.Token) {
+	delete(t.tokens, token.ID)
+
+	sessions := t.students[token.UserID]
+	delete(sessions, token.ID)
+	if len(sessions) == 0 {
+		delete(t.students, token.UserID)
+	}
+}
+
+func expired(token entities.Token, now time.Time) bool {
+	return !token.ExpirationDate.After(now)
+}
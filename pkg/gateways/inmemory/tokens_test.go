@@ -0,0 +1,86 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idcontracts"
+)
+
+func TestTokensRepository(t *testing.T) {
+	t.Parallel()
+
+	idcontracts.TokensRepository(t, func(t *testing.T) identities.TokensRepository {
+		return NewTokensRepository()
+	})
+}
+
+func TestTokensRepository_RunEviction(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	repository := NewTokensRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	studentID := uuid.NewString()
+	expiring := entities.NewToken(studentID, time.Now().Add(50*time.Millisecond))
+	active := entities.NewToken(studentID, time.Now().Add(time.Hour))
+	for _, token := range []entities.Token{expiring, active} {
+		require.NoError(t, repository.Register(ctx, token))
+	}
+
+	// test
+	go repository.RunEviction(ctx, 10*time.Millisecond)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		repository.mu.RLock()
+		defer repository.mu.RUnlock()
+		_, ok := repository.tokens[expiring.ID]
+		return !ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	repository.mu.RLock()
+	defer repository.mu.RUnlock()
+	assert.Contains(t, repository.tokens, active.ID)
+	assert.Equal(t, map[string]struct{}{active.ID: {}}, repository.students[studentID])
+}
+
+func TestTokensRepository_concurrency(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	repository := NewTokensRepository()
+	studentID := uuid.NewString()
+
+	// test
+	done := make(chan struct{})
+	for i := 0; i < 50; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			token := entities.NewToken(studentID, time.Now().Add(time.Hour))
+			assert.NoError(t, repository.Register(ctx, token))
+			_, err := repository.GetDigest(ctx, token.ID)
+			assert.NoError(t, err)
+			_, err = repository.ListStudentSessions(ctx, studentID)
+			assert.NoError(t, err)
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		<-done
+	}
+
+	// assert
+	revoked, err := repository.RevokeStudentSessions(ctx, studentID)
+	require.NoError(t, err)
+	assert.Equal(t, 50, revoked)
+}
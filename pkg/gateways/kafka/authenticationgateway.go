@@ -0,0 +1,134 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/events/v1"
+)
+
+// ErrActivityQueueFull is the failure of the activities dropped while the queue waits on a slow producer.
+var ErrActivityQueueFull = errors.New("authentication activity queue is full")
+
+// activityQueueSize bounds the activities waiting to be published, logins are not held back when it fills.
+const activityQueueSize = 1024
+
+type queuedActivity struct {
+	ctx   context.Context
+	input produceInput
+}
+
+// AuthenticationGateway publishes the authentication activity asynchronously, so the logins do not wait for
+// the brokers nor the schema registry: activities are queued and published by Run, in the order they were
+// queued. Activities that could not be published are dead-lettered by a resilient producer, see
+// Producer.Resilient, otherwise they are logged and dropped.
+type AuthenticationGateway struct {
+	producer Producer
+	logger   *zap.Logger
+	queue    chan queuedActivity
+}
+
+func NewAuthenticationProducer(producer Producer, logger *zap.Logger) AuthenticationGateway {
+	return AuthenticationGateway{
+		producer: producer,
+		logger:   logger,
+		queue:    make(chan queuedActivity, activityQueueSize),
+	}
+}
+
+// Run publishes the queued activities until the context is done, the ones still queued then are published
+// before it returns.
+func (g AuthenticationGateway) Run(ctx context.Context) {
+	for {
+		select {
+		case activity := <-g.queue:
+			g.publish(activity)
+		case <-ctx.Done():
+			for {
+				select {
+				case activity := <-g.queue:
+					g.publish(activity)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// authenticationTopic is apart from the students one, it is far busier and of no use to the consumers of
+// the students data. Records are keyed by the student ID.
+const authenticationTopic = "identity.auth.students.0"
+
+func (g AuthenticationGateway) ProduceStudentLoggedIn(ctx context.Context, activity entities.AuthenticationActivity) error {
+	return g.produce(ctx, events.TypeStudentLoggedIn, activity, events.StudentLoggedIn{
+		StudentID:  activity.StudentID,
+		TokenID:    activity.TokenID,
+		Outcome:    activity.Outcome,
+		IP:         activity.IP,
+		UserAgent:  activity.UserAgent,
+		OccurredAt: activity.OccurredAt.Format(time.RFC3339),
+	})
+}
+
+func (g AuthenticationGateway) ProduceLoginFailed(ctx context.Context, activity entities.AuthenticationActivity) error {
+	return g.produce(ctx, events.TypeLoginFailed, activity, events.LoginFailed{
+		StudentID:  activity.StudentID,
+		Outcome:    activity.Outcome,
+		IP:         activity.IP,
+		UserAgent:  activity.UserAgent,
+		OccurredAt: activity.OccurredAt.Format(time.RFC3339),
+	})
+}
+
+func (g AuthenticationGateway) ProduceTokenRevoked(ctx context.Context, activity entities.AuthenticationActivity) error {
+	return g.produce(ctx, events.TypeTokenRevoked, activity, events.TokenRevoked{
+		StudentID:  activity.StudentID,
+		TokenID:    activity.TokenID,
+		Outcome:    activity.Outcome,
+		IP:         activity.IP,
+		UserAgent:  activity.UserAgent,
+		OccurredAt: activity.OccurredAt.Format(time.RFC3339),
+	})
+}
+
+func (g AuthenticationGateway) produce(ctx context.Context, eventType string, activity entities.AuthenticationActivity, data any) error {
+	evt := event{
+		ID:      uuid.NewString(),
+		Type:    eventType,
+		Subject: activity.StudentID,
+		Time:    activity.OccurredAt,
+		Data:    data,
+	}
+
+	// the activity outlives the request it was produced for, only the trace is kept
+	queued := queuedActivity{
+		ctx:   detach(ctx),
+		input: produceInput{topic: authenticationTopic, key: activity.StudentID, event: evt},
+	}
+	select {
+	case g.queue <- queued:
+		return nil
+	default:
+		return ErrActivityQueueFull
+	}
+}
+
+func (g AuthenticationGateway) publish(activity queuedActivity) {
+	onFailure := func(err error) {
+		g.logger.Error("failed to publish authentication activity",
+			zap.String("event_id", activity.input.event.ID),
+			zap.String("event_type", activity.input.event.Type),
+			zap.Error(err),
+		)
+	}
+
+	if err := g.producer.produceAsync(activity.ctx, activity.input, onFailure); err != nil {
+		onFailure(err)
+	}
+}
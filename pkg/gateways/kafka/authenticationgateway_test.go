@@ -0,0 +1,79 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/events/v1"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+)
+
+func TestAuthenticationGateway_ProduceStudentLoggedIn(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := kfixtures.NewKafkaClient(t)
+	gateway := NewAuthenticationProducer(NewProducer(client, JSONSerializer{}), zap.NewNop())
+	go gateway.Run(ctx)
+	consumerClient := kfixtures.NewConsumerClient(t, authenticationTopic)
+
+	activity := entities.NewAuthenticationActivity(strconv.FormatInt(time.Now().UnixNano(), 10), entities.AuthOutcomeSucceeded,
+		entities.AuthenticationClient{IP: "203.0.113.7", UserAgent: "test-agent"})
+	activity.TokenID = "token-id"
+
+	// test
+	err := gateway.ProduceStudentLoggedIn(ctx, activity)
+	require.NoError(t, err)
+
+	// assert
+	for {
+		fetches := consumerClient.PollFetches(ctx)
+		require.NoError(t, ctx.Err(), "the event was not consumed")
+
+		for iter := fetches.RecordIter(); !iter.Done(); {
+			record := iter.Next()
+			if string(record.Key) != activity.StudentID {
+				continue
+			}
+
+			var payload events.StudentLoggedIn
+			require.NoError(t, json.Unmarshal(record.Value, &payload))
+			assert.Equal(t, activity.TokenID, payload.TokenID)
+			assert.Equal(t, activity.Outcome, payload.Outcome)
+			assert.Equal(t, activity.IP, payload.IP)
+			assert.Equal(t, activity.UserAgent, payload.UserAgent)
+			return
+		}
+	}
+}
+
+func TestAuthenticationGateway_queueFull(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	gateway := NewAuthenticationProducer(Producer{}, zap.NewNop())
+	activity := entities.NewAuthenticationActivity("201116548712", entities.AuthOutcomeStudentNotFound, entities.AuthenticationClient{})
+
+	for i := 0; i < activityQueueSize; i++ {
+		require.NoError(t, gateway.ProduceLoginFailed(ctx, activity))
+	}
+
+	// test
+	err := gateway.ProduceLoginFailed(ctx, activity)
+
+	// assert
+	assert.ErrorIs(t, err, ErrActivityQueueFull)
+	assert.Len(t, gateway.queue, activityQueueSize)
+}
@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/tccav/identity-service/pkg/events/v1"
+)
+
+var ErrUnsupportedAvroSchema = errors.New("unsupported avro schema")
+
+// avroSchema is the codec of the schema of an event type payload, along with the name its subject is after.
+type avroSchema struct {
+	fullName string
+	codec    *goavro.Codec
+}
+
+// AvroSerializer publishes the payloads in the Avro binary encoding of their schema, see the events avro
+// directory. Payloads are converted through their JSON form, which uses the field names of the schemas, with
+// unions written as plain JSON values.
+type AvroSerializer struct {
+	registry SchemaRegistry
+	schemas  map[string]avroSchema
+
+	// writers are the codecs of the schemas values were written with, by their ID.
+	mu      *sync.Mutex
+	writers map[int]*goavro.Codec
+}
+
+func NewAvroSerializer(registry SchemaRegistry) (AvroSerializer, error) {
+	entries, err := events.AvroSchemas.ReadDir("avro")
+	if err != nil {
+		return AvroSerializer{}, err
+	}
+
+	s := AvroSerializer{
+		registry: registry,
+		schemas:  make(map[string]avroSchema, len(entries)),
+		mu:       &sync.Mutex{},
+		writers:  map[int]*goavro.Codec{},
+	}
+	for _, entry := range entries {
+		content, err := fs.ReadFile(events.AvroSchemas, "avro/"+entry.Name())
+		if err != nil {
+			return AvroSerializer{}, err
+		}
+
+		schema, err := newAvroSchema(string(content))
+		if err != nil {
+			return AvroSerializer{}, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		s.schemas[strings.TrimSuffix(entry.Name(), ".avsc")] = schema
+	}
+
+	return s, nil
+}
+
+func newAvroSchema(content string) (avroSchema, error) {
+	var record struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+	if err := json.Unmarshal([]byte(content), &record); err != nil {
+		return avroSchema{}, fmt.Errorf("%w: %s", ErrUnsupportedAvroSchema, err)
+	}
+
+	codec, err := goavro.NewCodecForStandardJSONFull(content)
+	if err != nil {
+		return avroSchema{}, fmt.Errorf("%w: %s", ErrUnsupportedAvroSchema, err)
+	}
+
+	fullName := record.Name
+	if record.Namespace != "" {
+		fullName = record.Namespace + "." + record.Name
+	}
+	return avroSchema{fullName: fullName, codec: codec}, nil
+}
+
+func (AvroSerializer) ContentType() string {
+	return events.AvroDataContentType
+}
+
+func (AvroSerializer) DataSchema(eventType string) string {
+	return events.AvroDataSchema(eventType)
+}
+
+func (s AvroSerializer) Serialize(ctx context.Context, topic, eventType string, data any) ([]byte, error) {
+	schema, ok := s.schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: no schema for event type %q", ErrUnsupportedAvroSchema, eventType)
+	}
+
+	id, err := s.registry.RegisterSchema(ctx, subjectName(topic, schema.fullName), Schema{Type: SchemaTypeAvro, Schema: schema.codec.Schema()})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	native, _, err := schema.codec.NativeFromTextual(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAvroSchema, err)
+	}
+
+	return schema.codec.BinaryFromNative(appendWireHeader(make([]byte, 0, wireHeaderSize+len(content)), id), native)
+}
+
+// Deserialize decodes the value with the schema it was written with, fields the payload does not have are
+// skipped.
+func (s AvroSerializer) Deserialize(ctx context.Context, value []byte, data any) error {
+	id, payload, err := readWireHeader(value)
+	if err != nil {
+		return err
+	}
+
+	codec, err := s.writer(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	native, _, err := codec.NativeFromBinary(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformedRecordValue, err)
+	}
+
+	content, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, data)
+}
+
+// writer returns the codec of the schema with the ID, the schemas are never changed once registered.
+func (s AvroSerializer) writer(ctx context.Context, id int) (*goavro.Codec, error) {
+	s.mu.Lock()
+	codec, ok := s.writers[id]
+	s.mu.Unlock()
+	if ok {
+		return codec, nil
+	}
+
+	schema, err := s.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type != SchemaTypeAvro {
+		return nil, fmt.Errorf("%w: schema %d is %s", ErrUnsupportedAvroSchema, id, schema.Type)
+	}
+
+	codec, err = goavro.NewCodecForStandardJSONFull(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAvroSchema, err)
+	}
+
+	s.mu.Lock()
+	s.writers[id] = codec
+	s.mu.Unlock()
+	return codec, nil
+}
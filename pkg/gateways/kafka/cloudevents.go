@@ -0,0 +1,82 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/tccav/identity-service/pkg/events/v1"
+)
+
+// CloudEvents Kafka binding headers, in binary mode every attribute is a ce_ prefixed header and the record
+// value is the event data.
+const (
+	headerSpecVersion = "ce_specversion"
+	headerID          = "ce_id"
+	headerSource      = "ce_source"
+	headerType        = "ce_type"
+	headerSubject     = "ce_subject"
+	headerTime        = "ce_time"
+	headerDataSchema  = "ce_dataschema"
+	headerContentType = "content-type"
+	// headerReplay is the replay extension attribute, see StudentsGateway.Replaying.
+	headerReplay = "ce_replay"
+)
+
+// cloudEventHeaders builds the record headers with the event attributes, always in the same order. The content
+// type and data schema are the ones of the serializer the value is encoded with.
+func cloudEventHeaders(evt event, serializer Serializer) []kgo.RecordHeader {
+	attributes := []struct {
+		key   string
+		value string
+	}{
+		{headerSpecVersion, events.SpecVersion},
+		{headerID, evt.ID},
+		{headerSource, events.Source},
+		{headerType, evt.Type},
+		{headerSubject, evt.Subject},
+		{headerTime, evt.Time.UTC().Format(time.RFC3339Nano)},
+		{headerDataSchema, serializer.DataSchema(evt.Type)},
+		{headerContentType, serializer.ContentType()},
+	}
+
+	headers := make([]kgo.RecordHeader, 0, len(attributes))
+	for _, attribute := range attributes {
+		headers = append(headers, kgo.RecordHeader{Key: attribute.key, Value: []byte(attribute.value)})
+	}
+	return headers
+}
+
+// cloudEventAttributes reads the attributes of a record published in binary mode, records without the spec
+// version header are not CloudEvents.
+func cloudEventAttributes(record *kgo.Record) (events.Attributes, bool) {
+	var (
+		attributes events.Attributes
+		found      bool
+	)
+	for _, header := range record.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case headerSpecVersion:
+			attributes.SpecVersion = value
+			found = true
+		case headerID:
+			attributes.ID = value
+		case headerSource:
+			attributes.Source = value
+		case headerType:
+			attributes.Type = value
+		case headerSubject:
+			attributes.Subject = value
+		case headerTime:
+			attributes.Time, _ = time.Parse(time.RFC3339Nano, value)
+		case headerDataSchema:
+			attributes.DataSchema = value
+		case headerContentType:
+			attributes.DataContentType = value
+		case headerReplay:
+			attributes.Replay = value
+		}
+	}
+	return attributes, found
+}
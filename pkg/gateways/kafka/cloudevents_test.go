@@ -0,0 +1,150 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/events/v1"
+)
+
+func TestCloudEventHeaders(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	evt := event{
+		ID:      "4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f",
+		Type:    events.TypeStudentErased,
+		Subject: "201320509911",
+		Time:    time.Date(2023, 7, 23, 9, 0, 0, 0, time.UTC),
+	}
+
+	// test
+	headers := cloudEventHeaders(evt, JSONSerializer{})
+	got, ok := cloudEventAttributes(&kgo.Record{Headers: headers})
+
+	// assert
+	assert.Equal(t, []kgo.RecordHeader{
+		{Key: "ce_specversion", Value: []byte("1.0")},
+		{Key: "ce_id", Value: []byte(evt.ID)},
+		{Key: "ce_source", Value: []byte("identity-service")},
+		{Key: "ce_type", Value: []byte("student_erased")},
+		{Key: "ce_subject", Value: []byte("201320509911")},
+		{Key: "ce_time", Value: []byte("2023-07-23T09:00:00Z")},
+		{Key: "ce_dataschema", Value: []byte("https://github.com/tccav/identity-service/blob/main/pkg/events/v1/schemas/student_erased.json")},
+		{Key: "content-type", Value: []byte("application/json")},
+	}, headers)
+	assert.True(t, ok)
+	assert.Equal(t, events.Attributes{
+		ID:              evt.ID,
+		Type:            evt.Type,
+		Source:          events.Source,
+		Subject:         evt.Subject,
+		Time:            evt.Time,
+		DataSchema:      events.DataSchema(evt.Type),
+		DataContentType: events.DataContentType,
+		SpecVersion:     events.SpecVersion,
+	}, got)
+}
+
+func TestCloudEventAttributes_replay(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	headers := cloudEventHeaders(event{
+		ID:      "4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f",
+		Type:    events.TypeStudentRegistered,
+		Subject: "201320509911",
+		Time:    time.Date(2023, 7, 23, 9, 0, 0, 0, time.UTC),
+	}, JSONSerializer{})
+	headers = append(headers, recordHeadersFromHeaders(NewStudentsProducer(Producer{}, nil).Replaying("replay-id").headers)...)
+
+	// test
+	got, ok := cloudEventAttributes(&kgo.Record{Headers: headers})
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "replay-id", got.Replay)
+	assert.Equal(t, kgo.RecordHeader{Key: "ce_replay", Value: []byte("replay-id")}, headers[len(headers)-1])
+}
+
+func TestDecodeStudentEvent(t *testing.T) {
+	t.Parallel()
+
+	publishedAt := time.Date(2023, 7, 23, 9, 0, 0, 0, time.UTC)
+	cloudEvent := &kgo.Record{
+		Topic: studentsTopic,
+		Headers: cloudEventHeaders(event{
+			ID:      "4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f",
+			Type:    events.TypeStudentErased,
+			Subject: "201320509911",
+			Time:    publishedAt,
+		}, JSONSerializer{}),
+		Value: []byte(`{"student_id":"201320509911","erased_at":"2023-07-23T09:00:00Z"}`),
+	}
+
+	tt := []struct {
+		name      string
+		record    *kgo.Record
+		studentID string
+		want      entities.StudentEvent
+		wantOk    bool
+	}{
+		{
+			name:      "should decode cloud event about the student",
+			record:    cloudEvent,
+			studentID: "201320509911",
+			want: entities.StudentEvent{
+				ID:          "4f1c2d7e-5a8b-4c3d-9e0f-1a2b3c4d5e6f",
+				Type:        events.TypeStudentErased,
+				Topic:       studentsTopic,
+				PublishedAt: publishedAt,
+				Payload:     cloudEvent.Value,
+			},
+			wantOk: true,
+		},
+		{
+			name:      "should skip cloud event about another student",
+			record:    cloudEvent,
+			studentID: "201320509912",
+		},
+		{
+			name: "should decode event published before the cloud events binding",
+			record: &kgo.Record{
+				Topic:     studentsTopic,
+				Timestamp: publishedAt,
+				Value:     []byte(`{"event_id":"1","event_type":"student_registered","payload":{"student_id":"201320509911"}}`),
+			},
+			studentID: "201320509911",
+			want: entities.StudentEvent{
+				ID:          "1",
+				Type:        events.TypeStudentRegistered,
+				Topic:       studentsTopic,
+				PublishedAt: publishedAt,
+				Payload:     []byte(`{"student_id":"201320509911"}`),
+			},
+			wantOk: true,
+		},
+		{
+			name:      "should skip record that is not an event",
+			record:    &kgo.Record{Topic: studentsTopic, Value: []byte("invalid_json")},
+			studentID: "201320509911",
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, ok := decodeStudentEvent(tc.record, tc.studentID)
+
+			// assert
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
+)
+
+var ErrInvalidConnection = errors.New("invalid kafka connection")
+
+// SASL mechanisms, as informed in the configs. They are matched regardless of case.
+const (
+	MechanismPlain       = "PLAIN"
+	MechanismScramSHA256 = "SCRAM-SHA-256"
+	MechanismScramSHA512 = "SCRAM-SHA-512"
+	MechanismOAuthBearer = "OAUTHBEARER"
+)
+
+// Connection is how the client reaches the brokers. Brokers are only the seeds, the client discovers the rest
+// of the cluster from them.
+type Connection struct {
+	Brokers []string
+	TLS     tlsconfig.Config
+	SASL    SASLConfig
+}
+
+// SASLConfig Mechanism defaults to PLAIN when a user is informed. OAUTHBEARER takes the token itself or the
+// file it is in, the file is read again on each authentication, so a token refreshed in place is picked up.
+type SASLConfig struct {
+	Mechanism      string
+	User           string
+	Password       string
+	OAuthToken     string
+	OAuthTokenFile string
+}
+
+// ConnectionOptions builds the client options of the seed brokers, the TLS config and the SASL mechanism of
+// the connection.
+func ConnectionOptions(conn Connection) ([]kgo.Opt, error) {
+	if len(conn.Brokers) == 0 {
+		return nil, fmt.Errorf("%w: no seed brokers", ErrInvalidConnection)
+	}
+
+	opts := []kgo.Opt{kgo.SeedBrokers(conn.Brokers...)}
+
+	if conn.TLS.Enabled {
+		tlsConfig, err := conn.TLS.Build()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConnection, err)
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	mechanism, err := conn.SASL.mechanism()
+	if err != nil {
+		return nil, err
+	}
+	if mechanism != nil {
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+
+	return opts, nil
+}
+
+// mechanism is nil when the connection does not authenticate.
+func (c SASLConfig) mechanism() (sasl.Mechanism, error) {
+	name := strings.ToUpper(c.Mechanism)
+	if name == "" {
+		if c.User == "" {
+			return nil, nil
+		}
+		name = MechanismPlain
+	}
+
+	switch name {
+	case MechanismPlain, MechanismScramSHA256, MechanismScramSHA512:
+		if c.User == "" {
+			return nil, fmt.Errorf("%w: %s requires a user", ErrInvalidConnection, name)
+		}
+	}
+
+	switch name {
+	case MechanismPlain:
+		return plain.Auth{User: c.User, Pass: c.Password}.AsMechanism(), nil
+	case MechanismScramSHA256:
+		return scram.Auth{User: c.User, Pass: c.Password}.AsSha256Mechanism(), nil
+	case MechanismScramSHA512:
+		return scram.Auth{User: c.User, Pass: c.Password}.AsSha512Mechanism(), nil
+	case MechanismOAuthBearer:
+		return c.oauthMechanism()
+	default:
+		return nil, fmt.Errorf("%w: unknown SASL mechanism %q", ErrInvalidConnection, c.Mechanism)
+	}
+}
+
+func (c SASLConfig) oauthMechanism() (sasl.Mechanism, error) {
+	if c.OAuthToken != "" {
+		return oauth.Auth{Token: c.OAuthToken}.AsMechanism(), nil
+	}
+	if c.OAuthTokenFile == "" {
+		return nil, fmt.Errorf("%w: %s requires a token or a token file", ErrInvalidConnection, MechanismOAuthBearer)
+	}
+
+	// a missing file fails right away rather than on the first connection
+	if _, err := readToken(c.OAuthTokenFile); err != nil {
+		return nil, err
+	}
+
+	file := c.OAuthTokenFile
+	return oauth.Oauth(func(context.Context) (oauth.Auth, error) {
+		token, err := readToken(file)
+		return oauth.Auth{Token: token}, err
+	}), nil
+}
+
+func readToken(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("%w: reading OAuth token: %s", ErrInvalidConnection, err)
+	}
+
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("%w: OAuth token file %s is empty", ErrInvalidConnection, file)
+	}
+	return token, nil
+}
@@ -0,0 +1,234 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
+)
+
+func TestConnectionOptions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tokenFile := writeFile(t, dir, "token", "file-token\n")
+	emptyFile := writeFile(t, dir, "empty", "")
+	brokers := []string{"broker-1:9093", "broker-2:9093"}
+
+	tt := []struct {
+		name          string
+		conn          Connection
+		wantMechanism string
+		wantTLS       bool
+		wantErr       error
+	}{
+		{
+			name: "should only connect to the seed brokers",
+			conn: Connection{Brokers: brokers},
+		},
+		{
+			name:          "should default to PLAIN when a user is informed",
+			conn:          Connection{Brokers: brokers, SASL: SASLConfig{User: "identity", Password: "secret"}},
+			wantMechanism: MechanismPlain,
+		},
+		{
+			name:          "should authenticate with SCRAM-SHA-256",
+			conn:          Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: "scram-sha-256", User: "identity", Password: "secret"}},
+			wantMechanism: MechanismScramSHA256,
+		},
+		{
+			name:          "should authenticate with SCRAM-SHA-512",
+			conn:          Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: MechanismScramSHA512, User: "identity", Password: "secret"}},
+			wantMechanism: MechanismScramSHA512,
+		},
+		{
+			name:          "should authenticate with an OAuth token",
+			conn:          Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: MechanismOAuthBearer, OAuthToken: "token"}},
+			wantMechanism: MechanismOAuthBearer,
+		},
+		{
+			name:          "should authenticate with an OAuth token file",
+			conn:          Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: MechanismOAuthBearer, OAuthTokenFile: tokenFile}},
+			wantMechanism: MechanismOAuthBearer,
+		},
+		{
+			name:    "should verify the brokers with the system roots",
+			conn:    Connection{Brokers: brokers, TLS: tlsconfig.Config{Enabled: true}},
+			wantTLS: true,
+		},
+		{
+			name: "should ignore the TLS files when TLS is disabled",
+			conn: Connection{Brokers: brokers, TLS: tlsconfig.Config{CAFile: "missing.pem"}},
+		},
+		{
+			name:    "should fail because there are no seed brokers",
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the CA file does not exist",
+			conn:    Connection{Brokers: brokers, TLS: tlsconfig.Config{Enabled: true, CAFile: filepath.Join(dir, "missing.pem")}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the CA file has no certificates",
+			conn:    Connection{Brokers: brokers, TLS: tlsconfig.Config{Enabled: true, CAFile: emptyFile}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the client certificate has no key",
+			conn:    Connection{Brokers: brokers, TLS: tlsconfig.Config{Enabled: true, CertFile: filepath.Join(dir, "client.pem")}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the mechanism is unknown",
+			conn:    Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: "GSSAPI", User: "identity"}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because SCRAM requires a user",
+			conn:    Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: MechanismScramSHA256}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because OAUTHBEARER requires a token",
+			conn:    Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: MechanismOAuthBearer}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the OAuth token file is empty",
+			conn:    Connection{Brokers: brokers, SASL: SASLConfig{Mechanism: MechanismOAuthBearer, OAuthTokenFile: emptyFile}},
+			wantErr: ErrInvalidConnection,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, err := ConnectionOptions(tc.conn)
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+
+			// the client only connects to the seed brokers on its first request
+			client, err := kgo.NewClient(got...)
+			require.NoError(t, err)
+			defer client.Close()
+
+			assert.Equal(t, tc.conn.Brokers, client.OptValue(kgo.SeedBrokers))
+
+			mechanisms := client.OptValue(kgo.SASL).([]sasl.Mechanism)
+			if tc.wantMechanism == "" {
+				assert.Empty(t, mechanisms)
+			} else {
+				require.Len(t, mechanisms, 1)
+				assert.Equal(t, tc.wantMechanism, mechanisms[0].Name())
+			}
+
+			tlsConfig := client.OptValue(kgo.DialTLSConfig).(*tls.Config)
+			if !tc.wantTLS {
+				assert.Nil(t, tlsConfig)
+				return
+			}
+			require.NotNil(t, tlsConfig)
+			assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		})
+	}
+}
+
+func TestConnectionOptions_oauthTokenFile(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	tokenFile := writeFile(t, t.TempDir(), "token", "first-token")
+	opts, err := ConnectionOptions(Connection{
+		Brokers: []string{"localhost:9094"},
+		SASL:    SASLConfig{Mechanism: MechanismOAuthBearer, OAuthTokenFile: tokenFile},
+	})
+	require.NoError(t, err)
+
+	client, err := kgo.NewClient(opts...)
+	require.NoError(t, err)
+	defer client.Close()
+	mechanism := client.OptValue(kgo.SASL).([]sasl.Mechanism)[0]
+
+	// test
+	writeFile(t, filepath.Dir(tokenFile), "token", "refreshed-token\n")
+	_, message, err := mechanism.Authenticate(context.Background(), "localhost:9094")
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "n,,\x01auth=Bearer refreshed-token\x01\x01", string(message))
+}
+
+func TestConnectionOptions_localBroker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should connect to the seed brokers", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		opts, err := ConnectionOptions(Connection{Brokers: []string{"localhost:9094"}})
+		require.NoError(t, err)
+
+		client, err := kgo.NewClient(opts...)
+		require.NoError(t, err)
+		defer client.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// test
+		err = client.Ping(ctx)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("should not connect over TLS to a plaintext listener", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		opts, err := ConnectionOptions(Connection{
+			Brokers: []string{"localhost:9094"},
+			TLS:     tlsconfig.Config{Enabled: true},
+		})
+		require.NoError(t, err)
+
+		client, err := kgo.NewClient(append(opts, kgo.RequestRetries(0))...)
+		require.NoError(t, err)
+		defer client.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// test
+		err = client.Ping(ctx)
+
+		// assert
+		assert.Error(t, err)
+	})
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
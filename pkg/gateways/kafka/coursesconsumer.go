@@ -0,0 +1,145 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const (
+	courseCreatedEvent = "course_created"
+	courseUpdatedEvent = "course_updated"
+	courseClosedEvent  = "course_closed"
+)
+
+// CoursesConsumer keeps the local course catalog in sync with the events published by the courses service.
+// The client must be created with the consumer group and the courses topic already configured.
+type CoursesConsumer struct {
+	client  *kgo.Client
+	useCase identities.CourseCatalogUseCases
+	logger  *zap.Logger
+}
+
+func NewCoursesConsumer(client *kgo.Client, useCase identities.CourseCatalogUseCases, logger *zap.Logger) CoursesConsumer {
+	return CoursesConsumer{
+		client:  client,
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// Backoff before handling a course event again after a failure, doubled on each failure in a row.
+const (
+	handleBackoff    = 100 * time.Millisecond
+	handleMaxBackoff = 10 * time.Second
+)
+
+// Run polls course events until ctx is done or the client is closed. Events failing to be handled are retried
+// with backoff rather than skipped, so the catalog follows the events in order. Offsets are only committed after
+// every record of a fetch is handled, the ones failing to be committed are committed along with the next fetch.
+func (c CoursesConsumer) Run(ctx context.Context) {
+	for {
+		fetches := c.client.PollFetches(ctx)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			return
+		}
+
+		fetches.EachError(func(topic string, partition int32, err error) {
+			c.logger.Error("failed to fetch course events",
+				zap.String("topic", topic),
+				zap.Int32("partition", partition),
+				zap.Error(err),
+			)
+		})
+
+		iter := fetches.RecordIter()
+		for !iter.Done() {
+			if !c.handleWithBackoff(ctx, iter.Next()) {
+				return
+			}
+		}
+
+		err := c.client.CommitUncommittedOffsets(ctx)
+		if err != nil {
+			c.logger.Error("failed to commit course events offsets", zap.Error(err))
+		}
+	}
+}
+
+// handleWithBackoff handles the record until it succeeds, it returns false when ctx is done first.
+func (c CoursesConsumer) handleWithBackoff(ctx context.Context, record *kgo.Record) bool {
+	backoff := handleBackoff
+	for {
+		err := c.handle(ctx, record)
+		if err == nil {
+			return true
+		}
+
+		c.logger.Error("failed to handle course event, retrying", zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > handleMaxBackoff {
+			backoff = handleMaxBackoff
+		}
+	}
+}
+
+func (c CoursesConsumer) handle(ctx context.Context, record *kgo.Record) error {
+	logger := c.logger.With(
+		zap.String("topic", record.Topic),
+		zap.Int32("partition", record.Partition),
+		zap.Int64("offset", record.Offset),
+	)
+
+	var e consumedEvent
+	err := json.Unmarshal(record.Value, &e)
+	if err != nil {
+		logger.Warn("discarding malformed course event", zap.Error(err))
+		return nil
+	}
+
+	var payload coursePayload
+	err = json.Unmarshal(e.Payload, &payload)
+	if err != nil {
+		logger.Warn("discarding course event with malformed payload", zap.String("event_id", e.ID), zap.Error(err))
+		return nil
+	}
+
+	logger = logger.With(zap.String("event_id", e.ID), zap.String("event_type", e.Type), zap.String("course_id", payload.CourseID))
+
+	switch e.Type {
+	case courseCreatedEvent, courseUpdatedEvent:
+		err = c.useCase.SaveCourse(ctx, identities.SaveCourseInput{
+			ID:   payload.CourseID,
+			Name: payload.Name,
+		})
+	case courseClosedEvent:
+		err = c.useCase.CloseCourse(ctx, payload.CourseID)
+	default:
+		logger.Debug("ignoring unknown course event")
+		return nil
+	}
+
+	switch {
+	case err == nil:
+		logger.Info("course event handled")
+	case errors.Is(err, identities.ErrInvalidCourseID), errors.Is(err, identities.ErrCourseNotFound):
+		logger.Warn("discarding course event", zap.Error(err))
+	default:
+		return fmt.Errorf("unable to handle course event %s: %w", e.ID, err)
+	}
+
+	return nil
+}
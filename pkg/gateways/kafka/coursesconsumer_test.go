@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idmocks"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+)
+
+func TestCoursesConsumer_Run(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topic := "courses.cdc.courses." + uuid.NewString()
+	consumerClient := kfixtures.NewConsumerClient(t, topic)
+	producer := NewProducer(kfixtures.NewKafkaClient(t), JSONSerializer{})
+
+	// the courses service publishes its events in the event_id, event_type and payload envelope
+	type courseEvent struct {
+		ID      string `json:"event_id"`
+		Type    string `json:"event_type"`
+		Payload any    `json:"payload"`
+	}
+
+	courseID := uuid.NewString()
+	for _, e := range []courseEvent{
+		{ID: uuid.NewString(), Type: courseCreatedEvent, Payload: coursePayload{CourseID: courseID, Name: "História"}},
+		{ID: uuid.NewString(), Type: "course_renamed", Payload: coursePayload{CourseID: courseID}},
+		{ID: uuid.NewString(), Type: courseUpdatedEvent, Payload: "not_a_course"},
+		{ID: uuid.NewString(), Type: courseClosedEvent, Payload: coursePayload{CourseID: courseID, Name: "História"}},
+	} {
+		value, err := json.Marshal(e)
+		require.NoError(t, err)
+		result := producer.client.ProduceSync(ctx, &kgo.Record{Topic: topic, Value: value})
+		require.NoError(t, result.FirstErr())
+	}
+	result := producer.client.ProduceSync(ctx, &kgo.Record{Topic: topic, Value: []byte("invalid_json")})
+	require.NoError(t, result.FirstErr())
+
+	// the first save fails, the event must be handled again rather than skipped
+	var saves atomic.Int32
+	useCase := &idmocks.CourseCatalogUseCasesMock{
+		SaveCourseFunc: func(ctx context.Context, input identities.SaveCourseInput) error {
+			if saves.Add(1) == 1 {
+				return errors.New("connection reset by peer")
+			}
+			return nil
+		},
+		CloseCourseFunc: func(ctx context.Context, id string) error {
+			return nil
+		},
+	}
+
+	c := NewCoursesConsumer(consumerClient, useCase, zap.NewNop())
+
+	// test
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return len(useCase.CloseCourseCalls()) == 1
+	}, 30*time.Second, 100*time.Millisecond)
+
+	cancel()
+	<-done
+
+	saveCalls := useCase.SaveCourseCalls()
+	require.Len(t, saveCalls, 2)
+	assert.Equal(t, identities.SaveCourseInput{ID: courseID, Name: "História"}, saveCalls[1].Input)
+	assert.Equal(t, courseID, useCase.CloseCourseCalls()[0].ID)
+}
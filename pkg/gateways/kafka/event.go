@@ -1,16 +1,30 @@
 package kafka
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// event is published following the CloudEvents Kafka binding, see cloudevents.go. Subject is the ID of the
+// student the event is about and Data its payload, one of the events package types.
 type event struct {
-	ID      string `json:"event_id"`
-	Type    string `json:"event_type"`
-	Payload any    `json:"payload"`
+	ID      string
+	Type    string
+	Subject string
+	Time    time.Time
+	Data    any
+}
+
+// consumedEvent is the envelope of events produced by other services, and of the student events published
+// before the CloudEvents binding that are still found in the topics. Its payload is decoded according to the
+// event type.
+type consumedEvent struct {
+	ID      string          `json:"event_id"`
+	Type    string          `json:"event_type"`
+	Payload json.RawMessage `json:"payload"`
 }
 
-type studentRegisteredPayload struct {
-	StudentID string `json:"student_id"`
-	Name      string `json:"name"`
-	CPF       string `json:"cpf"`
-	Email     string `json:"email"`
-	BirthDate string `json:"birth_date"`
-	CourseID  string `json:"course_id"`
+type coursePayload struct {
+	CourseID string `json:"course_id"`
+	Name     string `json:"name"`
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -14,12 +15,45 @@ var (
 	kafkaURL = "localhost:9094"
 )
 
+// studentsPartitions is more than one, so the tests notice events of a student spread across partitions.
+const studentsPartitions = 3
+
 func NewKafkaClient(t *testing.T) *kgo.Client {
 	t.Helper()
 
+	client := newClient(t)
+	createTopics(t, client, studentsPartitions, "identity.cdc.students.0")
+	createTopics(t, client, 1, "identity.auth.students.0")
+
+	return client
+}
+
+// NewConsumerClient creates the informed topics and returns a client consuming them from
+// the start under a consumer group exclusive to the test.
+func NewConsumerClient(t *testing.T, topics ...string) *kgo.Client {
+	t.Helper()
+
+	admClient := newClient(t)
+	createTopics(t, admClient, 1, topics...)
+	admClient.Close()
+
+	client := newClient(t,
+		kgo.ConsumerGroup(uuid.NewString()),
+		kgo.ConsumeTopics(topics...),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.DisableAutoCommit(),
+	)
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func newClient(t *testing.T, opts ...kgo.Opt) *kgo.Client {
+	t.Helper()
+
 	ctx := context.Background()
 
-	client, err := kgo.NewClient(kgo.SeedBrokers(kafkaURL))
+	client, err := kgo.NewClient(append([]kgo.Opt{kgo.SeedBrokers(kafkaURL)}, opts...)...)
 	require.NoError(t, err)
 
 	retryCount := 5
@@ -35,10 +69,14 @@ func NewKafkaClient(t *testing.T) *kgo.Client {
 
 	require.NoError(t, err)
 
+	return client
+}
+
+func createTopics(t *testing.T, client *kgo.Client, partitions int32, topics ...string) {
+	t.Helper()
+
 	admClient := kadm.NewClient(client)
 
-	_, err = admClient.CreateTopics(ctx, 1, 1, nil, "identity.cdc.students.0")
+	_, err := admClient.CreateTopics(context.Background(), partitions, 1, nil, topics...)
 	require.NoError(t, err)
-
-	return client
 }
@@ -0,0 +1,109 @@
+package kfixtures
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type registrySchema struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type registryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// schemaRegistry keeps the schemas in memory, the same schema gets the same ID whatever the subject, as in
+// the real registry.
+type schemaRegistry struct {
+	mu       sync.Mutex
+	schemas  []registrySchema
+	subjects map[string][]int
+}
+
+// NewSchemaRegistry starts an in-process fake of the schema registry REST API with the endpoints the
+// serializers use, and returns its URL. It is shut down when the test ends.
+func NewSchemaRegistry(t *testing.T) string {
+	t.Helper()
+
+	registry := &schemaRegistry{subjects: make(map[string][]int)}
+
+	router := chi.NewRouter()
+	router.Post("/subjects/{subject}/versions", registry.register)
+	router.Get("/schemas/ids/{id}", registry.schemaByID)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func (r *schemaRegistry) register(w http.ResponseWriter, req *http.Request) {
+	var schema registrySchema
+	if err := json.NewDecoder(req.Body).Decode(&schema); err != nil || schema.Schema == "" {
+		sendRegistryJSON(w, http.StatusUnprocessableEntity, registryError{ErrorCode: 42201, Message: "Invalid schema"})
+		return
+	}
+	if schema.SchemaType == "" {
+		schema.SchemaType = "AVRO"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := 0
+	for i, registered := range r.schemas {
+		if registered == schema {
+			id = i + 1
+			break
+		}
+	}
+	if id == 0 {
+		r.schemas = append(r.schemas, schema)
+		id = len(r.schemas)
+	}
+
+	subject := chi.URLParam(req, "subject")
+	versions := r.subjects[subject]
+	registered := false
+	for _, version := range versions {
+		registered = registered || version == id
+	}
+	if !registered {
+		r.subjects[subject] = append(versions, id)
+	}
+
+	sendRegistryJSON(w, http.StatusOK, map[string]int{"id": id})
+}
+
+func (r *schemaRegistry) schemaByID(w http.ResponseWriter, req *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(req, "id"))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil || id < 1 || id > len(r.schemas) {
+		sendRegistryJSON(w, http.StatusNotFound, registryError{ErrorCode: 40403, Message: "Schema not found"})
+		return
+	}
+
+	schema := r.schemas[id-1]
+	if schema.SchemaType == "AVRO" {
+		schema.SchemaType = ""
+	}
+	sendRegistryJSON(w, http.StatusOK, schema)
+}
+
+func sendRegistryJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
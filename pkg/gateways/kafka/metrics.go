@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+const meterName = "github.com/tccav/identity-service/pkg/gateways/kafka"
+
+// producerMetrics counts the records retried and dead-lettered by topic.
+type producerMetrics struct {
+	retries      metric.Int64Counter
+	deadLettered metric.Int64Counter
+}
+
+func newProducerMetrics() (producerMetrics, error) {
+	meter := otel.Meter(meterName)
+
+	retries, err := meter.Int64Counter("kafka.producer.retries",
+		metric.WithDescription("Records published again after a failure"),
+	)
+	if err != nil {
+		return producerMetrics{}, err
+	}
+
+	deadLettered, err := meter.Int64Counter("kafka.producer.dead_lettered",
+		metric.WithDescription("Records stored as dead letters after every retry failed"),
+	)
+	if err != nil {
+		return producerMetrics{}, err
+	}
+
+	return producerMetrics{
+		retries:      retries,
+		deadLettered: deadLettered,
+	}, nil
+}
+
+func (m producerMetrics) addRetries(ctx context.Context, topic string, retries int) {
+	if m.retries != nil && retries > 0 {
+		m.retries.Add(ctx, int64(retries), metric.WithAttributes(attribute.String("topic", topic)))
+	}
+}
+
+func (m producerMetrics) addDeadLettered(ctx context.Context, topic string) {
+	if m.deadLettered != nil {
+		m.deadLettered.Add(ctx, 1, metric.WithAttributes(attribute.String("topic", topic)))
+	}
+}
+
+// RegisterQueueMetrics reports the depth of both queues events wait in: the records buffered by the client
+// until the brokers acknowledge them, and the dead letters until they are retried or discarded.
+func RegisterQueueMetrics(client *kgo.Client, deadLetters identities.DeadLettersRepository) error {
+	meter := otel.Meter(meterName)
+
+	buffered, err := meter.Int64ObservableGauge("kafka.producer.buffered_records",
+		metric.WithDescription("Records waiting to be acknowledged by the brokers"),
+	)
+	if err != nil {
+		return err
+	}
+
+	stored, err := meter.Int64ObservableGauge("kafka.producer.dead_letters",
+		metric.WithDescription("Dead letters waiting to be retried or discarded"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		observer.ObserveInt64(buffered, client.BufferedProduceRecords())
+
+		count, err := deadLetters.CountDeadLetters(ctx)
+		if err != nil {
+			return err
+		}
+		observer.ObserveInt64(stored, int64(count))
+		return nil
+	}, buffered, stored)
+	return err
+}
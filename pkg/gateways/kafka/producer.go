@@ -2,45 +2,286 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+var ErrInvalidAcks = errors.New("invalid kafka producer acks")
+
+// ErrEventHeld is the failure of the records dead-lettered without being published, an earlier event of their
+// key waiting in the dead letters.
+var ErrEventHeld = errors.New("event held behind a dead-lettered event of its key")
+
+// Producer acks, as informed in the configs.
+const (
+	AcksAll    = "all"
+	AcksLeader = "leader"
+	AcksNone   = "none"
 )
 
 type Producer struct {
-	client *kgo.Client
+	client      *kgo.Client
+	serializer  Serializer
+	retry       RetryPolicy
+	deadLetters identities.DeadLettersRepository
+	logger      *zap.Logger
+	metrics     producerMetrics
 }
 
-func NewProducer(client *kgo.Client) Producer {
+// NewProducer publishes the event payloads encoded by the serializer, see NewSerializer.
+func NewProducer(client *kgo.Client, serializer Serializer) Producer {
 	return Producer{
-		client: client,
+		client:     client,
+		serializer: serializer,
+	}
+}
+
+// Resilient returns a copy of the producer retrying each record by the policy, the records that still fail are
+// stored in the dead letters repository rather than reported to the caller, see DeadLettersUseCase. While an
+// event waits there, the later events of its key are stored behind it, so they are not published before it.
+func (p Producer) Resilient(policy RetryPolicy, deadLetters identities.DeadLettersRepository, logger *zap.Logger) (Producer, error) {
+	metrics, err := newProducerMetrics()
+	if err != nil {
+		return Producer{}, err
+	}
+
+	p.retry = policy
+	p.deadLetters = deadLetters
+	p.logger = logger
+	p.metrics = metrics
+	return p, nil
+}
+
+// ProducerOptions builds the client options of the acks the brokers must send before a record is considered
+// written. Idempotent writes keep the records of a partition in order through retries, they require the acks
+// of all in-sync replicas.
+func ProducerOptions(acks string, idempotent bool) ([]kgo.Opt, error) {
+	var requiredAcks kgo.Acks
+	switch acks {
+	case AcksAll:
+		requiredAcks = kgo.AllISRAcks()
+	case AcksLeader:
+		requiredAcks = kgo.LeaderAck()
+	case AcksNone:
+		requiredAcks = kgo.NoAck()
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAcks, acks)
+	}
+
+	if idempotent && acks != AcksAll {
+		return nil, fmt.Errorf("%w: idempotent writes require %q acks, got %q", ErrInvalidAcks, AcksAll, acks)
 	}
+
+	opts := []kgo.Opt{kgo.RequiredAcks(requiredAcks)}
+	if !idempotent {
+		// a single request in flight per broker, the default without idempotency, keeps the order on retries
+		opts = append(opts, kgo.DisableIdempotentWrite())
+	}
+	return opts, nil
 }
 
+// produceInput key picks the partition of the record, events with the same key are consumed in the order
+// they were produced.
 type produceInput struct {
 	topic   string
+	key     string
 	headers map[string]string
 	event   event
 }
 
+// produce publishes the event data as the record value, its attributes go in the headers along with the
+// informed ones. A record dead-lettered after every retry failed, or held behind a dead letter, is not an error.
 func (p Producer) produce(ctx context.Context, input produceInput) error {
-	eventValue, err := json.Marshal(input.event)
+	record, err := p.record(ctx, input)
 	if err != nil {
 		return err
 	}
 
-	record := kgo.Record{
-		Headers: recordHeadersFromHeaders(input.headers),
-		Value:   eventValue,
-		Topic:   input.topic,
+	held, err := p.held(ctx, record)
+	if err != nil {
+		return err
+	}
+	if held {
+		return p.deadLetter(ctx, record, 0, ErrEventHeld)
+	}
+
+	attempts, err := p.publish(ctx, record)
+	if err != nil {
+		return p.deadLetter(ctx, record, attempts, err)
 	}
-	result := p.client.ProduceSync(ctx, &record)
-	if err = result.FirstErr(); err != nil {
+	p.published(ctx, record)
+	return nil
+}
+
+// produceAsync queues the event without waiting for the brokers, failing to publish it is only reported to
+// onFailure when it could not be dead-lettered either. The event is not waited for when the client buffer is
+// full, as it happens while the brokers are unreachable. It still serializes the event and looks up the dead
+// letters of its key, so it is called off the request path, see AuthenticationGateway.Run.
+func (p Producer) produceAsync(ctx context.Context, input produceInput, onFailure func(error)) error {
+	record, err := p.record(ctx, input)
+	if err != nil {
 		return err
 	}
+
+	held, err := p.held(ctx, record)
+	if err != nil {
+		return err
+	}
+	if held {
+		return p.deadLetter(ctx, record, 0, ErrEventHeld)
+	}
+
+	p.client.TryProduce(ctx, record, func(record *kgo.Record, err error) {
+		// the promise must not hold the client back while the store is written
+		go func() {
+			if err == nil {
+				p.published(ctx, record)
+				return
+			}
+			if err := p.deadLetter(ctx, record, 1, err); err != nil {
+				onFailure(err)
+			}
+		}()
+	})
 	return nil
 }
 
+// PublishDeadLetter publishes the dead letter as the record it was stored as, retrying by the policy. It is not
+// held behind itself, DeadLettersUseCase only retries the oldest dead letter of a key.
+func (p Producer) PublishDeadLetter(ctx context.Context, deadLetter entities.DeadLetter) error {
+	headers := make([]kgo.RecordHeader, 0, len(deadLetter.Headers))
+	for _, header := range deadLetter.Headers {
+		headers = append(headers, kgo.RecordHeader{Key: header.Key, Value: []byte(header.Value)})
+	}
+
+	record := &kgo.Record{
+		Headers: headers,
+		Key:     []byte(deadLetter.Key),
+		Value:   deadLetter.Value,
+		Topic:   deadLetter.Topic,
+	}
+	_, err := p.publish(ctx, record)
+	if err != nil {
+		return err
+	}
+	p.published(ctx, record)
+	return nil
+}
+
+func (p Producer) publish(ctx context.Context, record *kgo.Record) (int, error) {
+	attempts, err := p.retry.retry(ctx, func() error {
+		return p.client.ProduceSync(ctx, record).FirstErr()
+	})
+	p.metrics.addRetries(ctx, record.Topic, attempts-1)
+	return attempts, err
+}
+
+// deadLetterTimeout bounds storing a dead letter, which goes on even if the request it was for is cancelled.
+const deadLetterTimeout = 5 * time.Second
+
+// held tells whether an earlier event of the record key waits in the dead letters, the record must then wait
+// behind it for the events of the key to be published in order.
+func (p Producer) held(ctx context.Context, record *kgo.Record) (bool, error) {
+	if p.deadLetters == nil || len(record.Key) == 0 {
+		return false, nil
+	}
+
+	pending, err := p.deadLetters.ListDeadLetters(ctx, identities.DeadLettersFilter{
+		Topic: record.Topic,
+		Key:   string(record.Key),
+		Limit: 1,
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(pending) > 0, nil
+}
+
+// published records the time of the record event for its key, so the dead letters of earlier events are not
+// published after it. The record is already published, failing to record it is only logged.
+func (p Producer) published(ctx context.Context, record *kgo.Record) {
+	if p.deadLetters == nil || len(record.Key) == 0 {
+		return
+	}
+
+	attributes, _ := cloudEventAttributes(record)
+	storeCtx, cancel := context.WithTimeout(detach(ctx), deadLetterTimeout)
+	defer cancel()
+	err := p.deadLetters.SavePublishedEvent(storeCtx, record.Topic, string(record.Key), attributes.Time)
+	if err != nil {
+		p.logger.Warn("unable to record published event",
+			zap.String("topic", record.Topic),
+			zap.String("event_id", attributes.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// deadLetter stores the record that failed, the failure is returned as is without a dead letters repository.
+func (p Producer) deadLetter(ctx context.Context, record *kgo.Record, attempts int, publishErr error) error {
+	if p.deadLetters == nil {
+		return publishErr
+	}
+
+	var eventID, eventType string
+	headers := make([]entities.EventHeader, 0, len(record.Headers))
+	for _, header := range record.Headers {
+		switch header.Key {
+		case headerID:
+			eventID = string(header.Value)
+		case headerType:
+			eventType = string(header.Value)
+		}
+		headers = append(headers, entities.EventHeader{Key: header.Key, Value: string(header.Value)})
+	}
+	attributes, _ := cloudEventAttributes(record)
+	deadLetter := entities.NewDeadLetter(record.Topic, string(record.Key), eventID, eventType, attributes.Time, headers, record.Value, attempts, publishErr)
+
+	storeCtx, cancel := context.WithTimeout(detach(ctx), deadLetterTimeout)
+	defer cancel()
+	if err := p.deadLetters.SaveDeadLetter(storeCtx, deadLetter); err != nil {
+		return errors.Join(publishErr, err)
+	}
+	p.metrics.addDeadLettered(ctx, record.Topic)
+
+	p.logger.Warn("event dead-lettered",
+		zap.String("dead_letter_id", deadLetter.ID),
+		zap.String("topic", deadLetter.Topic),
+		zap.String("event_id", eventID),
+		zap.String("event_type", eventType),
+		zap.Int("attempts", attempts),
+		zap.Error(publishErr),
+	)
+	return nil
+}
+
+// detach keeps only the trace of the context, for work that outlives the request.
+func detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+func (p Producer) record(ctx context.Context, input produceInput) (*kgo.Record, error) {
+	eventValue, err := p.serializer.Serialize(ctx, input.topic, input.event.Type, input.event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kgo.Record{
+		Headers: append(cloudEventHeaders(input.event, p.serializer), recordHeadersFromHeaders(input.headers)...),
+		Key:     []byte(input.key),
+		Value:   eventValue,
+		Topic:   input.topic,
+	}, nil
+}
+
 func recordHeadersFromHeaders(headers map[string]string) []kgo.RecordHeader {
 	var recordHeaders []kgo.RecordHeader
 	for k, v := range headers {
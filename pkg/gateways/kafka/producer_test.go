@@ -0,0 +1,272 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idusecases"
+	"github.com/tccav/identity-service/pkg/events/v1"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+)
+
+func TestProducerOptions(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name           string
+		acks           string
+		idempotent     bool
+		wantAcks       kgo.Acks
+		wantIdempotent bool
+		wantErr        error
+	}{
+		{
+			name:           "should require all acks on idempotent writes",
+			acks:           AcksAll,
+			idempotent:     true,
+			wantAcks:       kgo.AllISRAcks(),
+			wantIdempotent: true,
+		},
+		{
+			name:     "should disable idempotent writes with leader acks",
+			acks:     AcksLeader,
+			wantAcks: kgo.LeaderAck(),
+		},
+		{
+			name:     "should disable idempotent writes without acks",
+			acks:     AcksNone,
+			wantAcks: kgo.NoAck(),
+		},
+		{
+			name:       "should fail because idempotent writes require all acks",
+			acks:       AcksLeader,
+			idempotent: true,
+			wantErr:    ErrInvalidAcks,
+		},
+		{
+			name:    "should fail because acks are unknown",
+			acks:    "2",
+			wantErr: ErrInvalidAcks,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, err := ProducerOptions(tc.acks, tc.idempotent)
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, got)
+				return
+			}
+			require.NoError(t, err)
+
+			// the client only connects to the seed brokers on its first request
+			client, err := kgo.NewClient(append(got, kgo.SeedBrokers("localhost:9094"))...)
+			require.NoError(t, err)
+			defer client.Close()
+
+			assert.Equal(t, tc.wantAcks, client.OptValue(kgo.RequiredAcks))
+			assert.Equal(t, !tc.wantIdempotent, client.OptValue(kgo.DisableIdempotentWrite))
+		})
+	}
+}
+
+func TestProducer_deadLettersOrder(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	store := newDeadLettersStore()
+	policy := RetryPolicy{Attempts: 1}
+
+	// a client without reachable brokers fails every record once its delivery timeout is reached
+	unreachable, err := kgo.NewClient(kgo.SeedBrokers("localhost:1"), kgo.RecordDeliveryTimeout(time.Second))
+	require.NoError(t, err)
+	defer unreachable.Close()
+	failing, err := NewProducer(unreachable, JSONSerializer{}).Resilient(policy, store, zap.NewNop())
+	require.NoError(t, err)
+	working, err := NewProducer(kfixtures.NewKafkaClient(t), JSONSerializer{}).Resilient(policy, store, zap.NewNop())
+	require.NoError(t, err)
+
+	useCase := idusecases.NewDeadLettersUseCase(store, working, store)
+	consumerClient := kfixtures.NewConsumerClient(t, studentsTopic)
+
+	// the student id is unique to the test, since the students topic is shared by the tests
+	studentID := strconv.FormatInt(time.Now().UnixNano(), 10)
+	produceInputOf := func(reason string, at time.Time) produceInput {
+		return produceInput{
+			topic: studentsTopic,
+			key:   studentID,
+			event: event{
+				ID:      uuid.NewString(),
+				Type:    events.TypeStudentStatusChanged,
+				Subject: studentID,
+				Time:    at,
+				Data:    events.StudentStatusChanged{StudentID: studentID, Reason: reason},
+			},
+		}
+	}
+	start := time.Now().UTC()
+
+	// test
+	require.NoError(t, failing.produce(ctx, produceInputOf("0", start)))
+	require.NoError(t, working.produce(ctx, produceInputOf("1", start.Add(time.Second))))
+
+	// assert
+	deadLetters := store.list(studentID)
+	require.Len(t, deadLetters, 2)
+	assert.Equal(t, 1, deadLetters[0].Attempts)
+	assert.Equal(t, 0, deadLetters[1].Attempts, "the later event was published before the dead-lettered one")
+	assert.Equal(t, ErrEventHeld.Error(), deadLetters[1].Error)
+
+	err = useCase.RetryDeadLetter(ctx, identities.DeadLetterInput{ID: deadLetters[1].ID})
+	assert.ErrorIs(t, err, identities.ErrDeadLetterOutOfOrder)
+	for _, deadLetter := range deadLetters {
+		require.NoError(t, useCase.RetryDeadLetter(ctx, identities.DeadLetterInput{ID: deadLetter.ID}))
+	}
+	assert.Empty(t, store.list(studentID))
+
+	// a later event published while an earlier one was being dead-lettered leaves the earlier one out of order
+	require.NoError(t, working.produce(ctx, produceInputOf("3", start.Add(3*time.Second))))
+	require.NoError(t, failing.produce(ctx, produceInputOf("2", start.Add(2*time.Second))))
+	deadLetters = store.list(studentID)
+	require.Len(t, deadLetters, 1)
+	err = useCase.RetryDeadLetter(ctx, identities.DeadLetterInput{ID: deadLetters[0].ID})
+	assert.ErrorIs(t, err, identities.ErrDeadLetterOutOfOrder)
+
+	var reasons []string
+	for len(reasons) < 3 {
+		fetches := consumerClient.PollFetches(ctx)
+		require.NoError(t, ctx.Err(), "not every event was consumed")
+
+		for iter := fetches.RecordIter(); !iter.Done(); {
+			record := iter.Next()
+			if string(record.Key) != studentID {
+				continue
+			}
+
+			var payload events.StudentStatusChanged
+			if err := json.Unmarshal(record.Value, &payload); err != nil {
+				continue
+			}
+			reasons = append(reasons, payload.Reason)
+		}
+	}
+	assert.Equal(t, []string{"0", "1", "3"}, reasons)
+}
+
+// deadLettersStore keeps the dead letters and published events in memory, and takes the audit entries.
+type deadLettersStore struct {
+	mu          sync.Mutex
+	deadLetters map[string]entities.DeadLetter
+	published   map[string]time.Time
+}
+
+func newDeadLettersStore() *deadLettersStore {
+	return &deadLettersStore{
+		deadLetters: map[string]entities.DeadLetter{},
+		published:   map[string]time.Time{},
+	}
+}
+
+func (s *deadLettersStore) list(key string) []entities.DeadLetter {
+	deadLetters, _ := s.ListDeadLetters(context.Background(), identities.DeadLettersFilter{Key: key})
+	return deadLetters
+}
+
+func (s *deadLettersStore) SaveDeadLetter(_ context.Context, deadLetter entities.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.deadLetters[deadLetter.ID]; ok {
+		deadLetter.FailedAt = stored.FailedAt
+	}
+	s.deadLetters[deadLetter.ID] = deadLetter
+	return nil
+}
+
+func (s *deadLettersStore) ListDeadLetters(_ context.Context, filter identities.DeadLettersFilter) ([]entities.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deadLetters []entities.DeadLetter
+	for _, deadLetter := range s.deadLetters {
+		if (filter.Topic == "" || deadLetter.Topic == filter.Topic) && (filter.Key == "" || deadLetter.Key == filter.Key) {
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].FailedAt.Before(deadLetters[j].FailedAt)
+	})
+	if filter.Limit > 0 && len(deadLetters) > filter.Limit {
+		deadLetters = deadLetters[:filter.Limit]
+	}
+	return deadLetters, nil
+}
+
+func (s *deadLettersStore) GetDeadLetter(_ context.Context, id string) (entities.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadLetter, ok := s.deadLetters[id]
+	if !ok {
+		return entities.DeadLetter{}, identities.ErrDeadLetterNotFound
+	}
+	return deadLetter, nil
+}
+
+func (s *deadLettersStore) DeleteDeadLetter(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.deadLetters, id)
+	return nil
+}
+
+func (s *deadLettersStore) CountDeadLetters(_ context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.deadLetters), nil
+}
+
+func (s *deadLettersStore) SavePublishedEvent(_ context.Context, topic, key string, eventTime time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if eventTime.After(s.published[topic+"/"+key]) {
+		s.published[topic+"/"+key] = eventTime
+	}
+	return nil
+}
+
+func (s *deadLettersStore) GetLastPublishedEvent(_ context.Context, topic, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.published[topic+"/"+key], nil
+}
+
+func (s *deadLettersStore) RecordAudit(context.Context, entities.AuditEntry) error {
+	return nil
+}
@@ -0,0 +1,166 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/tccav/identity-service/pkg/events/v1"
+	"github.com/tccav/identity-service/pkg/events/v1/eventspb"
+)
+
+var ErrUnsupportedProtobufSchema = errors.New("unsupported protobuf schema")
+
+type protobufMessage struct {
+	payload any
+	message proto.Message
+}
+
+// protobufMessages are the messages generated from the proto directory of the events, by the event type of
+// their payload.
+var protobufMessages = map[string]protobufMessage{
+	events.TypeStudentRegistered:    {payload: events.StudentRegistered{}, message: &eventspb.StudentRegistered{}},
+	events.TypeStudentStatusChanged: {payload: events.StudentStatusChanged{}, message: &eventspb.StudentStatusChanged{}},
+	events.TypeStudentErased:        {payload: events.StudentErased{}, message: &eventspb.StudentErased{}},
+	events.TypeStudentLoggedIn:      {payload: events.StudentLoggedIn{}, message: &eventspb.StudentLoggedIn{}},
+	events.TypeLoginFailed:          {payload: events.LoginFailed{}, message: &eventspb.LoginFailed{}},
+	events.TypeTokenRevoked:         {payload: events.TokenRevoked{}, message: &eventspb.TokenRevoked{}},
+}
+
+// protobufSchema is the source registered for the message, the registry needs it rather than the descriptor.
+type protobufSchema struct {
+	source  string
+	message protoreflect.MessageType
+}
+
+// ProtobufSerializer publishes the payloads as the Protobuf message of their schema, see the events proto
+// directory. Payloads are converted to their generated message through their JSON form, which uses the field
+// names of the messages.
+type ProtobufSerializer struct {
+	registry  SchemaRegistry
+	schemas   map[string]protobufSchema
+	byPayload map[reflect.Type]protoreflect.MessageType
+}
+
+func NewProtobufSerializer(registry SchemaRegistry) (ProtobufSerializer, error) {
+	s := ProtobufSerializer{
+		registry:  registry,
+		schemas:   make(map[string]protobufSchema, len(protobufMessages)),
+		byPayload: make(map[reflect.Type]protoreflect.MessageType, len(protobufMessages)),
+	}
+
+	for eventType, m := range protobufMessages {
+		source, err := events.ProtobufSchemas.ReadFile("proto/" + eventType + ".proto")
+		if err != nil {
+			return ProtobufSerializer{}, err
+		}
+
+		messageType := m.message.ProtoReflect().Type()
+		s.schemas[eventType] = protobufSchema{source: string(source), message: messageType}
+		s.byPayload[reflect.TypeOf(m.payload)] = messageType
+	}
+
+	return s, nil
+}
+
+func (ProtobufSerializer) ContentType() string {
+	return events.ProtobufDataContentType
+}
+
+func (ProtobufSerializer) DataSchema(eventType string) string {
+	return events.ProtobufDataSchema(eventType)
+}
+
+func (s ProtobufSerializer) Serialize(ctx context.Context, topic, eventType string, data any) ([]byte, error) {
+	schema, ok := s.schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("%w: no message for event type %q", ErrUnsupportedProtobufSchema, eventType)
+	}
+
+	recordName := string(schema.message.Descriptor().FullName())
+	id, err := s.registry.RegisterSchema(ctx, subjectName(topic, recordName), Schema{Type: SchemaTypeProtobuf, Schema: schema.source})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	message := schema.message.New().Interface()
+	if err = protojson.Unmarshal(content, message); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProtobufSchema, err)
+	}
+
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	// the message indexes of the first message of the file are written as a single zero
+	dst := binary.AppendVarint(appendWireHeader(make([]byte, 0, wireHeaderSize+1+len(payload)), id), 0)
+	return append(dst, payload...), nil
+}
+
+// Deserialize decodes the value with the message generated for the payload, fields of later versions of the
+// message are skipped.
+func (s ProtobufSerializer) Deserialize(_ context.Context, value []byte, data any) error {
+	_, payload, err := readWireHeader(value)
+	if err != nil {
+		return err
+	}
+
+	payload, err = readMessageIndexes(payload)
+	if err != nil {
+		return err
+	}
+
+	typ := reflect.TypeOf(data)
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	messageType, ok := s.byPayload[typ]
+	if !ok {
+		return fmt.Errorf("%w: no message for payload %T", ErrUnsupportedProtobufSchema, data)
+	}
+
+	message := messageType.New().Interface()
+	if err = proto.Unmarshal(payload, message); err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformedRecordValue, err)
+	}
+
+	content, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, data)
+}
+
+// readMessageIndexes skips the path to the message in the schema file, only the first message is supported.
+func readMessageIndexes(src []byte) ([]byte, error) {
+	count, n := binary.Varint(src)
+	if n <= 0 || count < 0 {
+		return nil, ErrMalformedRecordValue
+	}
+	src = src[n:]
+
+	for i := int64(0); i < count; i++ {
+		index, n := binary.Varint(src)
+		if n <= 0 {
+			return nil, ErrMalformedRecordValue
+		}
+		if index != 0 {
+			return nil, fmt.Errorf("%w: message index %d", ErrUnsupportedProtobufSchema, index)
+		}
+		src = src[n:]
+	}
+	return src, nil
+}
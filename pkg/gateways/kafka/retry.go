@@ -0,0 +1,55 @@
+package kafka
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy bounds the tries to publish a record before it is dead-lettered. The wait before each retry is
+// drawn at random up to Backoff doubled on every retry and capped by MaxBackoff, so producers failing together
+// do not retry together. Attempts lower than one are taken as one.
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// retry calls publish until it succeeds or the attempts run out, returning how many were made and the last error.
+// The context being done stops the retries.
+func (p RetryPolicy) retry(ctx context.Context, publish func() error) (int, error) {
+	attempts := p.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = publish()
+		if err == nil || attempt == attempts {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(p.backoff(attempt)):
+		}
+	}
+}
+
+// backoff is the jittered wait after the informed attempt.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.Backoff <= 0 {
+		return 0
+	}
+
+	ceiling := p.Backoff << (attempt - 1)
+	if ceiling <= 0 || (p.MaxBackoff > 0 && ceiling > p.MaxBackoff) {
+		ceiling = p.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
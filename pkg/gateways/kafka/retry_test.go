@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_retry(t *testing.T) {
+	t.Parallel()
+
+	errPublish := errors.New("broker unreachable")
+
+	tt := []struct {
+		name         string
+		policy       RetryPolicy
+		failures     int
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "should publish at the first attempt",
+			policy:       RetryPolicy{Attempts: 3, Backoff: time.Millisecond},
+			wantAttempts: 1,
+		},
+		{
+			name:         "should publish after retrying",
+			policy:       RetryPolicy{Attempts: 3, Backoff: time.Millisecond},
+			failures:     2,
+			wantAttempts: 3,
+		},
+		{
+			name:         "should give up once the attempts run out",
+			policy:       RetryPolicy{Attempts: 3, Backoff: time.Millisecond},
+			failures:     5,
+			wantAttempts: 3,
+			wantErr:      errPublish,
+		},
+		{
+			name:         "should try once without attempts",
+			failures:     5,
+			wantAttempts: 1,
+			wantErr:      errPublish,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			var calls int
+			publish := func() error {
+				calls++
+				if calls <= tc.failures {
+					return errPublish
+				}
+				return nil
+			}
+
+			// test
+			attempts, err := tc.policy.retry(context.Background(), publish)
+
+			// assert
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.wantAttempts, attempts)
+			assert.Equal(t, tc.wantAttempts, calls)
+		})
+	}
+
+	t.Run("should stop retrying once the context is done", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// test
+		attempts, err := RetryPolicy{Attempts: 3, Backoff: time.Hour}.retry(ctx, func() error { return errPublish })
+
+		// assert
+		assert.ErrorIs(t, err, errPublish)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	for attempt, ceiling := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  300 * time.Millisecond,
+		70: 300 * time.Millisecond,
+	} {
+		for i := 0; i < 100; i++ {
+			got := policy.backoff(attempt)
+			assert.GreaterOrEqual(t, got, time.Duration(0))
+			assert.LessOrEqual(t, got, ceiling, "backoff after attempt %d", attempt)
+		}
+	}
+
+	assert.Zero(t, RetryPolicy{}.backoff(1))
+}
@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+var ErrSchemaNotFound = errors.New("schema not found in the registry")
+
+type SchemaType string
+
+// Schema types as named by the schema registry, AVRO is the one assumed when none is informed.
+const (
+	SchemaTypeAvro     SchemaType = "AVRO"
+	SchemaTypeProtobuf SchemaType = "PROTOBUF"
+	SchemaTypeJSON     SchemaType = "JSON"
+)
+
+type Schema struct {
+	Type   SchemaType
+	Schema string
+}
+
+// SchemaRegistry resolves the IDs the serializers frame the records with. Registering a schema already
+// registered under the subject returns its existing ID.
+type SchemaRegistry interface {
+	RegisterSchema(ctx context.Context, subject string, schema Schema) (int, error)
+	SchemaByID(ctx context.Context, id int) (Schema, error)
+}
+
+// SchemaRegistryClient talks to a Confluent compatible schema registry through its REST API. Registered IDs and
+// fetched schemas never change, so both are kept in memory once resolved.
+type SchemaRegistryClient struct {
+	url        string
+	user       string
+	password   string
+	httpClient *http.Client
+
+	mu      *sync.RWMutex
+	ids     map[string]int
+	schemas map[int]Schema
+}
+
+// NewSchemaRegistryClient authenticates with basic auth when user is informed.
+func NewSchemaRegistryClient(registryURL, user, password string) SchemaRegistryClient {
+	return SchemaRegistryClient{
+		url:      strings.TrimSuffix(registryURL, "/"),
+		user:     user,
+		password: password,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		mu:      &sync.RWMutex{},
+		ids:     make(map[string]int),
+		schemas: make(map[int]Schema),
+	}
+}
+
+type registrySchema struct {
+	Schema     string     `json:"schema"`
+	SchemaType SchemaType `json:"schemaType,omitempty"`
+}
+
+type registryError struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+func (c SchemaRegistryClient) RegisterSchema(ctx context.Context, subject string, schema Schema) (int, error) {
+	cacheKey := subject + "\x00" + string(schema.Type) + "\x00" + schema.Schema
+	c.mu.RLock()
+	id, ok := c.ids[cacheKey]
+	c.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	body := registrySchema{Schema: schema.Schema}
+	if schema.Type != SchemaTypeAvro {
+		body.SchemaType = schema.Type
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &registered)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.ids[cacheKey] = registered.ID
+	c.schemas[registered.ID] = schema
+	c.mu.Unlock()
+
+	return registered.ID, nil
+}
+
+func (c SchemaRegistryClient) SchemaByID(ctx context.Context, id int) (Schema, error) {
+	c.mu.RLock()
+	schema, ok := c.schemas[id]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	var found registrySchema
+	err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &found)
+	if err != nil {
+		return Schema{}, err
+	}
+
+	schema = Schema{Type: found.SchemaType, Schema: found.Schema}
+	if schema.Type == "" {
+		schema.Type = SchemaTypeAvro
+	}
+
+	c.mu.Lock()
+	c.schemas[id] = schema
+	c.mu.Unlock()
+
+	return schema, nil
+}
+
+func (c SchemaRegistryClient) do(ctx context.Context, method, path string, body, result any) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	}
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var regErr registryError
+		_ = json.NewDecoder(resp.Body).Decode(&regErr)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("%w: %s", ErrSchemaNotFound, regErr.Message)
+		}
+		return fmt.Errorf("schema registry answered %d: %s", resp.StatusCode, regErr.Message)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,65 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+)
+
+func TestSchemaRegistryClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should register schema once and resolve it by id", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		registryURL := kfixtures.NewSchemaRegistry(t)
+		client := NewSchemaRegistryClient(registryURL, "", "")
+		schema := Schema{Type: SchemaTypeProtobuf, Schema: `syntax = "proto3"; message A { string a = 1; }`}
+
+		// test
+		first, firstErr := client.RegisterSchema(ctx, "topic-A", schema)
+		again, againErr := client.RegisterSchema(ctx, "topic-A", schema)
+		got, getErr := NewSchemaRegistryClient(registryURL, "", "").SchemaByID(ctx, first)
+
+		// assert
+		require.NoError(t, firstErr)
+		require.NoError(t, againErr)
+		require.NoError(t, getErr)
+		assert.Equal(t, first, again)
+		assert.Equal(t, schema, got)
+	})
+
+	t.Run("should default to avro schema type", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		registryURL := kfixtures.NewSchemaRegistry(t)
+		schema := Schema{Type: SchemaTypeAvro, Schema: `{"type":"record","name":"A","fields":[]}`}
+		id, err := NewSchemaRegistryClient(registryURL, "", "").RegisterSchema(ctx, "topic-A", schema)
+		require.NoError(t, err)
+
+		// test
+		got, err := NewSchemaRegistryClient(registryURL, "", "").SchemaByID(ctx, id)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, schema, got)
+	})
+
+	t.Run("should fail because schema is not registered", func(t *testing.T) {
+		t.Parallel()
+
+		// test
+		_, err := NewSchemaRegistryClient(kfixtures.NewSchemaRegistry(t), "", "").SchemaByID(context.Background(), 42)
+
+		// assert
+		assert.ErrorIs(t, err, ErrSchemaNotFound)
+	})
+}
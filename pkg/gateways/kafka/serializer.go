@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/tccav/identity-service/pkg/events/v1"
+)
+
+var (
+	ErrUnknownSerializer      = errors.New("unknown kafka serializer")
+	ErrSchemaRegistryRequired = errors.New("kafka serializer requires a schema registry")
+	ErrMalformedRecordValue   = errors.New("record value is not in the confluent wire format")
+)
+
+// Serializer formats, as informed in the configs.
+const (
+	SerializerJSON       = "json"
+	SerializerJSONSchema = "json-schema"
+	SerializerAvro       = "avro"
+	SerializerProtobuf   = "protobuf"
+)
+
+// framedSerializers are the formats framed in the wire format, by the content type they publish.
+var framedSerializers = map[string]string{
+	events.DataContentType:         SerializerJSONSchema,
+	events.AvroDataContentType:     SerializerAvro,
+	events.ProtobufDataContentType: SerializerProtobuf,
+}
+
+// Serializer encodes the event payloads into record values and back. ContentType and DataSchema are the
+// CloudEvents attributes of the records it encodes.
+type Serializer interface {
+	ContentType() string
+	DataSchema(eventType string) string
+	Serialize(ctx context.Context, topic, eventType string, data any) ([]byte, error)
+	Deserialize(ctx context.Context, value []byte, data any) error
+}
+
+// NewSerializer builds the serializer of the format. Every format but plain JSON frames the records in the
+// Confluent wire format, with the schema ID resolved from the registry.
+func NewSerializer(format string, registry SchemaRegistry) (Serializer, error) {
+	if format != SerializerJSON && registry == nil {
+		return nil, fmt.Errorf("%w: %q", ErrSchemaRegistryRequired, format)
+	}
+
+	switch format {
+	case SerializerJSON:
+		return JSONSerializer{}, nil
+	case SerializerJSONSchema:
+		return NewJSONSchemaSerializer(registry)
+	case SerializerAvro:
+		return NewAvroSerializer(registry)
+	case SerializerProtobuf:
+		return NewProtobufSerializer(registry)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSerializer, format)
+	}
+}
+
+// JSONSerializer publishes the plain JSON payloads, the format consumers got before the schema registry.
+type JSONSerializer struct{}
+
+func (JSONSerializer) ContentType() string {
+	return events.DataContentType
+}
+
+func (JSONSerializer) DataSchema(eventType string) string {
+	return events.DataSchema(eventType)
+}
+
+func (JSONSerializer) Serialize(_ context.Context, _, _ string, data any) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+func (JSONSerializer) Deserialize(_ context.Context, value []byte, data any) error {
+	return json.Unmarshal(value, data)
+}
+
+// jsonSchema is the JSON schema of an event type payload, along with the title its subject is after.
+type jsonSchema struct {
+	title  string
+	schema string
+}
+
+// JSONSchemaSerializer publishes the JSON payloads framed with the ID of their JSON schema.
+type JSONSchemaSerializer struct {
+	registry SchemaRegistry
+	schemas  map[string]jsonSchema
+}
+
+func NewJSONSchemaSerializer(registry SchemaRegistry) (JSONSchemaSerializer, error) {
+	entries, err := events.Schemas.ReadDir("schemas")
+	if err != nil {
+		return JSONSchemaSerializer{}, err
+	}
+
+	s := JSONSchemaSerializer{
+		registry: registry,
+		schemas:  make(map[string]jsonSchema, len(entries)),
+	}
+	for _, entry := range entries {
+		content, err := fs.ReadFile(events.Schemas, "schemas/"+entry.Name())
+		if err != nil {
+			return JSONSchemaSerializer{}, err
+		}
+
+		var schema struct {
+			Title string `json:"title"`
+		}
+		if err = json.Unmarshal(content, &schema); err != nil {
+			return JSONSchemaSerializer{}, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		s.schemas[strings.TrimSuffix(entry.Name(), ".json")] = jsonSchema{title: schema.Title, schema: string(content)}
+	}
+
+	return s, nil
+}
+
+func (JSONSchemaSerializer) ContentType() string {
+	return events.DataContentType
+}
+
+func (JSONSchemaSerializer) DataSchema(eventType string) string {
+	return events.DataSchema(eventType)
+}
+
+func (s JSONSchemaSerializer) Serialize(ctx context.Context, topic, eventType string, data any) ([]byte, error) {
+	schema, ok := s.schemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("no json schema for event type %q", eventType)
+	}
+
+	id, err := s.registry.RegisterSchema(ctx, subjectName(topic, schema.title), Schema{Type: SchemaTypeJSON, Schema: schema.schema})
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(appendWireHeader(make([]byte, 0, wireHeaderSize+len(payload)), id), payload...), nil
+}
+
+func (s JSONSchemaSerializer) Deserialize(_ context.Context, value []byte, data any) error {
+	_, payload, err := readWireHeader(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(payload, data)
+}
+
+// subjectName follows the topic record name strategy, since a topic holds events of different types.
+func subjectName(topic, recordName string) string {
+	return topic + "-" + recordName
+}
+
+// The Confluent wire format prefixes the payload with a zero magic byte and the big endian schema ID.
+const (
+	wireMagicByte  = 0
+	wireHeaderSize = 5
+)
+
+func appendWireHeader(dst []byte, schemaID int) []byte {
+	dst = append(dst, wireMagicByte)
+	return binary.BigEndian.AppendUint32(dst, uint32(schemaID))
+}
+
+func readWireHeader(value []byte) (int, []byte, error) {
+	if len(value) < wireHeaderSize || value[0] != wireMagicByte {
+		return 0, nil, ErrMalformedRecordValue
+	}
+	return int(binary.BigEndian.Uint32(value[1:wireHeaderSize])), value[wireHeaderSize:], nil
+}
+
+// isFramed tells if the record value is in the wire format, JSON payloads never start with a zero byte.
+func isFramed(value []byte) bool {
+	return len(value) >= wireHeaderSize && value[0] == wireMagicByte
+}
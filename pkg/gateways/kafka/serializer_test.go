@@ -0,0 +1,270 @@
+package kafka
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/events/v1"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+)
+
+func TestSerializers(t *testing.T) {
+	t.Parallel()
+
+	payloads := []struct {
+		eventType string
+		data      any
+		decoded   func() any
+	}{
+		{
+			eventType: events.TypeStudentRegistered,
+			data: events.StudentRegistered{
+				StudentID: "201320509911",
+				Name:      "Pedro Lopes",
+				CPF:       "52998224725",
+				Email:     "plopes@ol.com",
+				BirthDate: "1994-03-19",
+				CourseID:  "6e3a4f0c-2b1d-4c8e-9f7a-5d6b3c2a1e0f",
+				Status:    "pending",
+			},
+			decoded: func() any { return &events.StudentRegistered{} },
+		},
+		{
+			eventType: events.TypeStudentStatusChanged,
+			data: events.StudentStatusChanged{
+				StudentID:      "201320509911",
+				PreviousStatus: "pending",
+				Status:         "active",
+				ChangedAt:      "2023-07-23T09:00:00Z",
+			},
+			decoded: func() any { return &events.StudentStatusChanged{} },
+		},
+		{
+			eventType: events.TypeStudentErased,
+			data:      events.StudentErased{StudentID: "201320509911", ErasedAt: "2023-07-23T09:00:00Z"},
+			decoded:   func() any { return &events.StudentErased{} },
+		},
+	}
+
+	tt := []struct {
+		format          string
+		wantContentType string
+		wantFramed      bool
+	}{
+		{format: SerializerJSON, wantContentType: "application/json"},
+		{format: SerializerJSONSchema, wantContentType: "application/json", wantFramed: true},
+		{format: SerializerAvro, wantContentType: "application/avro", wantFramed: true},
+		{format: SerializerProtobuf, wantContentType: "application/protobuf", wantFramed: true},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.format, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			ctx := context.Background()
+			registry := NewSchemaRegistryClient(kfixtures.NewSchemaRegistry(t), "", "")
+			serializer, err := NewSerializer(tc.format, registry)
+			require.NoError(t, err)
+
+			ids := make(map[int]bool)
+			for _, payload := range payloads {
+				// test
+				value, err := serializer.Serialize(ctx, studentsTopic, payload.eventType, payload.data)
+				require.NoError(t, err)
+
+				decoded := payload.decoded()
+				err = serializer.Deserialize(ctx, value, decoded)
+
+				// assert
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantContentType, serializer.ContentType())
+				assert.Equal(t, tc.wantFramed, isFramed(value))
+				assert.Equal(t, payload.data, reflect.ValueOf(decoded).Elem().Interface())
+
+				if tc.wantFramed {
+					id, _, err := readWireHeader(value)
+					require.NoError(t, err)
+					ids[id] = true
+				}
+			}
+			if tc.wantFramed {
+				assert.Len(t, ids, len(payloads), "every event type has its own schema")
+			}
+		})
+	}
+}
+
+func TestSerializers_WireFormat(t *testing.T) {
+	t.Parallel()
+
+	erased := events.StudentErased{StudentID: "2011", ErasedAt: "2023"}
+
+	tt := []struct {
+		name   string
+		format string
+		want   []byte
+	}{
+		{
+			name:   "should frame avro binary encoding",
+			format: SerializerAvro,
+			want:   []byte("\x00\x00\x00\x00\x01\x082011\x082023"),
+		},
+		{
+			name:   "should frame protobuf message after the message indexes",
+			format: SerializerProtobuf,
+			want:   []byte("\x00\x00\x00\x00\x01\x00\x0a\x042011\x12\x042023"),
+		},
+		{
+			name:   "should frame json payload",
+			format: SerializerJSONSchema,
+			want:   []byte("\x00\x00\x00\x00\x01" + `{"student_id":"2011","erased_at":"2023"}`),
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			serializer, err := NewSerializer(tc.format, NewSchemaRegistryClient(kfixtures.NewSchemaRegistry(t), "", ""))
+			require.NoError(t, err)
+
+			// test
+			got, err := serializer.Serialize(context.Background(), studentsTopic, events.TypeStudentErased, erased)
+
+			// assert
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestSerializers_SchemaEvolution(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	registry := NewSchemaRegistryClient(kfixtures.NewSchemaRegistry(t), "", "")
+
+	// a later version of the schema with a field the payload does not have yet
+	avroID, err := registry.RegisterSchema(ctx, "students-erased", Schema{Type: SchemaTypeAvro, Schema: `{
+		"type": "record", "name": "StudentErased", "namespace": "tccav.identity.events.v1",
+		"fields": [
+			{"name": "student_id", "type": "string"},
+			{"name": "erased_by", "type": ["null", "string"]},
+			{"name": "erased_at", "type": "string"}
+		]
+	}`})
+	require.NoError(t, err)
+	protobufID, err := registry.RegisterSchema(ctx, "students-erased", Schema{Type: SchemaTypeProtobuf, Schema: `
+		syntax = "proto3";
+		message StudentErased {
+		  string student_id = 1;
+		  string erased_at = 2;
+		  string erased_by = 3;
+		}`})
+	require.NoError(t, err)
+
+	avroValue := append(appendWireHeader(nil, avroID), "\x082011\x02\x04op\x082023"...)
+	protobufValue := append(appendWireHeader(nil, protobufID), "\x00\x0a\x042011\x12\x042023\x1a\x02op"...)
+
+	avroSerializer, err := NewAvroSerializer(registry)
+	require.NoError(t, err)
+	protobufSerializer, err := NewProtobufSerializer(registry)
+	require.NoError(t, err)
+
+	// test
+	var fromAvro, fromProtobuf events.StudentErased
+	avroErr := avroSerializer.Deserialize(ctx, avroValue, &fromAvro)
+	protobufErr := protobufSerializer.Deserialize(ctx, protobufValue, &fromProtobuf)
+
+	// assert
+	want := events.StudentErased{StudentID: "2011", ErasedAt: "2023"}
+	require.NoError(t, avroErr)
+	require.NoError(t, protobufErr)
+	assert.Equal(t, want, fromAvro)
+	assert.Equal(t, want, fromProtobuf)
+}
+
+func TestNewSerializer(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		format   string
+		registry SchemaRegistry
+		wantErr  error
+	}{
+		{
+			name:   "should build json serializer without registry",
+			format: SerializerJSON,
+		},
+		{
+			name:    "should fail because avro requires the registry",
+			format:  SerializerAvro,
+			wantErr: ErrSchemaRegistryRequired,
+		},
+		{
+			name:     "should fail because format is unknown",
+			format:   "xml",
+			registry: NewSchemaRegistryClient("http://localhost:8081", "", ""),
+			wantErr:  ErrUnknownSerializer,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, err := NewSerializer(tc.format, tc.registry)
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
+
+func TestStudentEventsReader_decodePayload(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	registry := NewSchemaRegistryClient(kfixtures.NewSchemaRegistry(t), "", "")
+	reader, err := NewStudentEventsReader(nil, registry)
+	require.NoError(t, err)
+	serializer, err := NewProtobufSerializer(registry)
+	require.NoError(t, err)
+
+	erased := events.StudentErased{StudentID: "201320509911", ErasedAt: "2023-07-23T09:00:00Z"}
+	value, err := serializer.Serialize(ctx, studentsTopic, events.TypeStudentErased, erased)
+	require.NoError(t, err)
+
+	// test
+	framed, framedErr := reader.decodePayload(ctx, events.ProtobufDataContentType, entities.StudentEvent{
+		Type:    events.TypeStudentErased,
+		Payload: value,
+	})
+	plain, plainErr := reader.decodePayload(ctx, events.DataContentType, entities.StudentEvent{
+		Type:    events.TypeStudentErased,
+		Payload: []byte(`{"student_id":"201320509911"}`),
+	})
+
+	// assert
+	require.NoError(t, framedErr)
+	require.NoError(t, plainErr)
+	assert.JSONEq(t, `{"student_id":"201320509911","erased_at":"2023-07-23T09:00:00Z"}`, string(framed))
+	assert.Equal(t, `{"student_id":"201320509911"}`, string(plain))
+}
@@ -7,32 +7,74 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/encryption"
+	"github.com/tccav/identity-service/pkg/events/v1"
 )
 
 type StudentsGateway struct {
 	producer Producer
+	keys     identities.StudentKeysRepository
+	headers  map[string]string
 }
 
-func NewStudentsProducer(producer Producer) StudentsGateway {
+// NewStudentsProducer encrypts the student personal data in the events with the student key, so erasing the
+// student leaves the events already published unreadable.
+func NewStudentsProducer(producer Producer, keys identities.StudentKeysRepository) StudentsGateway {
 	return StudentsGateway{
 		producer: producer,
+		keys:     keys,
 	}
 }
 
+// Replaying returns a copy of the gateway whose events are marked with the replay ID in the ce_replay header,
+// so consumers tell them apart from the events published as they happened.
+func (g StudentsGateway) Replaying(replayID string) StudentsGateway {
+	g.headers = map[string]string{headerReplay: replayID}
+	return g
+}
+
+// studentsTopic records are keyed by the student ID, so the events of a student are kept in order.
+const studentsTopic = "identity.cdc.students.0"
+
 func (g StudentsGateway) ProduceStudentRegistered(ctx context.Context, student entities.Student, courseID string) error {
-	err := g.producer.produce(ctx, produceInput{
-		topic: "identity.cdc.students.0",
+	key, err := g.keys.GetOrCreateStudentKey(ctx, student.ID)
+	if err != nil {
+		return err
+	}
+
+	payload := events.StudentRegistered{
+		StudentID: student.ID,
+		CourseID:  courseID,
+		Status:    string(student.Status),
+		PIIKeyID:  key.ID,
+	}
+	fields := []struct {
+		sealed *string
+		value  string
+	}{
+		{&payload.Name, student.Name},
+		{&payload.CPF, student.CPF},
+		{&payload.Email, student.Email},
+		{&payload.BirthDate, student.BirthDate.Format(time.DateOnly)},
+	}
+	for _, field := range fields {
+		*field.sealed, err = encryption.SealField(key.Key, field.value, student.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = g.producer.produce(ctx, produceInput{
+		topic:   studentsTopic,
+		key:     student.ID,
+		headers: g.headers,
 		event: event{
-			ID:   uuid.NewString(),
-			Type: "student_registered",
-			Payload: studentRegisteredPayload{
-				StudentID: student.ID,
-				Name:      student.Name,
-				CPF:       student.CPF,
-				Email:     student.Email,
-				BirthDate: student.BirthDate.Format(time.DateOnly),
-				CourseID:  courseID,
-			},
+			ID:      uuid.NewString(),
+			Type:    events.TypeStudentRegistered,
+			Subject: student.ID,
+			Time:    time.Now().UTC(),
+			Data:    payload,
 		},
 	},
 	)
@@ -42,3 +84,52 @@ func (g StudentsGateway) ProduceStudentRegistered(ctx context.Context, student e
 
 	return nil
 }
+
+func (g StudentsGateway) ProduceStudentStatusChanged(ctx context.Context, transition entities.StudentStatusTransition) error {
+	err := g.producer.produce(ctx, produceInput{
+		topic:   studentsTopic,
+		key:     transition.StudentID,
+		headers: g.headers,
+		event: event{
+			ID:      uuid.NewString(),
+			Type:    events.TypeStudentStatusChanged,
+			Subject: transition.StudentID,
+			Time:    time.Now().UTC(),
+			Data: events.StudentStatusChanged{
+				StudentID:      transition.StudentID,
+				PreviousStatus: string(transition.From),
+				Status:         string(transition.To),
+				Reason:         transition.Reason,
+				ChangedAt:      transition.ChangedAt.Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (g StudentsGateway) ProduceStudentErased(ctx context.Context, erasure entities.StudentErasure) error {
+	err := g.producer.produce(ctx, produceInput{
+		topic:   studentsTopic,
+		key:     erasure.StudentID,
+		headers: g.headers,
+		event: event{
+			ID:      uuid.NewString(),
+			Type:    events.TypeStudentErased,
+			Subject: erasure.StudentID,
+			Time:    time.Now().UTC(),
+			Data: events.StudentErased{
+				StudentID: erasure.StudentID,
+				ErasedAt:  erasure.ErasedAt.Format(time.RFC3339),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
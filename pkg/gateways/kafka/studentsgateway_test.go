@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/events/v1"
+	"github.com/tccav/identity-service/pkg/gateways/kafka/kfixtures"
+)
+
+func TestStudentsGateway_ordering(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	gateway := NewStudentsProducer(NewProducer(kfixtures.NewKafkaClient(t), JSONSerializer{}), nil)
+	consumerClient := kfixtures.NewConsumerClient(t, studentsTopic)
+
+	// the student ids are unique to the test, since the students topic is shared by the tests
+	base := time.Now().UnixNano()
+	studentIDs := make([]string, 0, 6)
+	partitions := make(map[string]map[int32]bool)
+	for i := int64(0); i < 6; i++ {
+		studentID := strconv.FormatInt(base+i, 10)
+		studentIDs = append(studentIDs, studentID)
+		partitions[studentID] = make(map[int32]bool)
+	}
+
+	const steps = 5
+
+	// test
+	for step := 0; step < steps; step++ {
+		for _, studentID := range studentIDs {
+			err := gateway.ProduceStudentStatusChanged(ctx, entities.StudentStatusTransition{
+				StudentID: studentID,
+				From:      entities.StudentStatusPending,
+				To:        entities.StudentStatusActive,
+				Reason:    strconv.Itoa(step),
+				ChangedAt: time.Now(),
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	// assert
+	reasons := make(map[string][]string)
+	for received := 0; received < steps*len(studentIDs); {
+		fetches := consumerClient.PollFetches(ctx)
+		require.NoError(t, ctx.Err(), "not every event was consumed")
+
+		for iter := fetches.RecordIter(); !iter.Done(); {
+			record := iter.Next()
+			studentID := string(record.Key)
+			if _, ours := partitions[studentID]; !ours {
+				continue
+			}
+
+			var payload events.StudentStatusChanged
+			if err := json.Unmarshal(record.Value, &payload); err != nil || payload.StudentID != studentID {
+				continue
+			}
+			reasons[studentID] = append(reasons[studentID], payload.Reason)
+			partitions[studentID][record.Partition] = true
+			received++
+		}
+	}
+
+	for _, studentID := range studentIDs {
+		assert.Equal(t, []string{"0", "1", "2", "3", "4"}, reasons[studentID], "events of %s out of order", studentID)
+		assert.Len(t, partitions[studentID], 1, "events of %s spread across partitions", studentID)
+	}
+}
@@ -0,0 +1,256 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/encryption"
+	"github.com/tccav/identity-service/pkg/events/v1"
+)
+
+// StudentEventsReader scans the students topic from its start, it is meant for the rare cases every event about
+// a single student is needed, like a LGPD data subject access. Each read uses its own client, built from the
+// informed options. Personal data sealed with the student key is opened while the key exists.
+type StudentEventsReader struct {
+	keys        identities.StudentKeysRepository
+	serializers map[string]Serializer
+	opts        []kgo.Opt
+}
+
+// NewStudentEventsReader decodes the events published through the schema registry back into JSON, the registry
+// can be nil when every event was published as plain JSON.
+func NewStudentEventsReader(keys identities.StudentKeysRepository, registry SchemaRegistry, opts ...kgo.Opt) (StudentEventsReader, error) {
+	serializers := make(map[string]Serializer, len(framedSerializers))
+	if registry != nil {
+		for contentType, format := range framedSerializers {
+			serializer, err := NewSerializer(format, registry)
+			if err != nil {
+				return StudentEventsReader{}, err
+			}
+			serializers[contentType] = serializer
+		}
+	}
+
+	return StudentEventsReader{
+		keys:        keys,
+		serializers: serializers,
+		opts:        opts,
+	}, nil
+}
+
+// ReadStudentEvents reads every partition up to the end offsets found when it starts, events published
+// meanwhile are not waited for. The last offset of each partition is expected to hold a record, which holds
+// as long as the topic is neither compacted nor written by transactions.
+func (r StudentEventsReader) ReadStudentEvents(ctx context.Context, studentID string) ([]entities.StudentEvent, error) {
+	admClient, err := kgo.NewClient(r.opts...)
+	if err != nil {
+		return nil, err
+	}
+	adm := kadm.NewClient(admClient)
+	defer adm.Close()
+
+	starts, err := adm.ListStartOffsets(ctx, studentsTopic)
+	if err == nil {
+		err = starts.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ends, err := adm.ListEndOffsets(ctx, studentsTopic)
+	if err == nil {
+		err = ends.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[int32]int64)
+	starts.Each(func(start kadm.ListedOffset) {
+		end, ok := ends.Lookup(start.Topic, start.Partition)
+		if ok && end.Offset > start.Offset {
+			pending[start.Partition] = end.Offset
+		}
+	})
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	// the options are shared by concurrent reads, appending must not write to their backing array
+	opts := append(r.opts[:len(r.opts):len(r.opts)], kgo.ConsumePartitions(starts.KOffsets()))
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var (
+		studentEvents []entities.StudentEvent
+		decodeErr     error
+	)
+	for len(pending) > 0 && decodeErr == nil {
+		fetches := client.PollFetches(ctx)
+		if errs := fetches.Errors(); len(errs) > 0 {
+			return nil, errs[0].Err
+		}
+
+		fetches.EachRecord(func(record *kgo.Record) {
+			end, ok := pending[record.Partition]
+			if !ok || record.Offset >= end {
+				return
+			}
+			if record.Offset+1 >= end {
+				delete(pending, record.Partition)
+			}
+
+			event, ok := decodeStudentEvent(record, studentID)
+			if !ok || decodeErr != nil {
+				return
+			}
+
+			attributes, _ := cloudEventAttributes(record)
+			event.Payload, decodeErr = r.decodePayload(ctx, attributes.DataContentType, event)
+			studentEvents = append(studentEvents, event)
+		})
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	if len(studentEvents) == 0 {
+		return studentEvents, nil
+	}
+
+	key, err := r.keys.GetStudentKey(ctx, studentID)
+	switch {
+	case errors.Is(err, identities.ErrStudentKeyNotFound):
+		// the student was erased or never had a key, there is nothing to open
+		return studentEvents, nil
+	case err != nil:
+		return nil, err
+	}
+
+	for i := range studentEvents {
+		studentEvents[i].Payload, err = openPayload(studentEvents[i].Payload, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return studentEvents, nil
+}
+
+// studentPayloads builds the payload each student event type is decoded into.
+var studentPayloads = map[string]func() any{
+	events.TypeStudentRegistered:    func() any { return &events.StudentRegistered{} },
+	events.TypeStudentStatusChanged: func() any { return &events.StudentStatusChanged{} },
+	events.TypeStudentErased:        func() any { return &events.StudentErased{} },
+}
+
+// decodePayload turns a payload framed in the Confluent wire format back into JSON, with the serializer of
+// the content type it was published with. Plain JSON payloads are kept as they are.
+func (r StudentEventsReader) decodePayload(ctx context.Context, contentType string, event entities.StudentEvent) ([]byte, error) {
+	if !isFramed(event.Payload) {
+		return event.Payload, nil
+	}
+
+	newPayload, ok := studentPayloads[event.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown event type %q", ErrMalformedRecordValue, event.Type)
+	}
+
+	serializer, ok := r.serializers[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrSchemaRegistryRequired, contentType)
+	}
+
+	payload := newPayload()
+	err := serializer.Deserialize(ctx, event.Payload, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(payload)
+}
+
+// openPayload opens the sealed top level fields of the payload, the other ones are kept as they are.
+func openPayload(payload []byte, key entities.StudentKey) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload, nil
+	}
+
+	opened := false
+	for name, raw := range fields {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || !encryption.IsSealed(value) {
+			continue
+		}
+
+		value, err := encryption.OpenField(key.Key, value, key.StudentID)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[name], err = json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		opened = true
+	}
+
+	if !opened {
+		return payload, nil
+	}
+	return json.Marshal(fields)
+}
+
+// decodeStudentEvent tells if the record is an event about the student, records that can not be decoded
+// are not about anyone. Events published before the CloudEvents binding have the student only in the payload.
+func decodeStudentEvent(record *kgo.Record, studentID string) (entities.StudentEvent, bool) {
+	if attributes, ok := cloudEventAttributes(record); ok {
+		if attributes.Subject != studentID {
+			return entities.StudentEvent{}, false
+		}
+
+		publishedAt := attributes.Time
+		if publishedAt.IsZero() {
+			publishedAt = record.Timestamp
+		}
+
+		return entities.StudentEvent{
+			ID:          attributes.ID,
+			Type:        attributes.Type,
+			Topic:       record.Topic,
+			PublishedAt: publishedAt.UTC(),
+			Payload:     record.Value,
+		}, true
+	}
+
+	var evt consumedEvent
+	if err := json.Unmarshal(record.Value, &evt); err != nil {
+		return entities.StudentEvent{}, false
+	}
+
+	var subject struct {
+		StudentID string `json:"student_id"`
+	}
+	if err := json.Unmarshal(evt.Payload, &subject); err != nil || subject.StudentID != studentID {
+		return entities.StudentEvent{}, false
+	}
+
+	return entities.StudentEvent{
+		ID:          evt.ID,
+		Type:        evt.Type,
+		Topic:       record.Topic,
+		PublishedAt: record.Timestamp.UTC(),
+		Payload:     evt.Payload,
+	}, true
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
@@ -57,7 +59,31 @@ func InitProvider(logger *zap.Logger, version string, config config.Telemetry) (
 	// set global propagator to tracecontext (the default is no-op).
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
+	// Set up a metric exporter, the metrics are read and exported periodically.
+	metricExporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithInsecure(),
+		otlpmetricgrpc.WithEndpoint(config.OtelCollector),
+		otlpmetricgrpc.WithDialOption(grpc.WithBlock()),
+	)
+	if err != nil {
+		logger.Error("failed to create metric exporter", zap.Error(err))
+		_ = tracerProvider.Shutdown(ctx)
+		return nil, err
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+	)
+	otel.SetMeterProvider(meterProvider)
+
 	return func() {
+		// Shutdown will flush any remaining metrics and shut down the exporter.
+		err = meterProvider.Shutdown(ctx)
+		if err != nil {
+			logger.Error("failed to shutdown MeterProvider", zap.Error(err))
+		}
+
 		// Shutdown will flush any remaining spans and shut down the exporter.
 		err = tracerProvider.Shutdown(ctx)
 		if err != nil {
@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+)
+
+type AuditRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewAuditRepository(conn *pgxpool.Pool) AuditRepository {
+	return AuditRepository{
+		conn: conn,
+	}
+}
+
+func (a AuditRepository) RecordAudit(ctx context.Context, entry entities.AuditEntry) error {
+	const statement = `INSERT INTO audit_log (action, student_id, actor, created_at) VALUES ($1, $2, $3, $4)`
+
+	_, err := a.conn.Exec(ctx, statement, entry.Action, entry.StudentID, entry.Actor, entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type CoursesRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewCoursesRepository(conn *pgxpool.Pool) CoursesRepository {
+	return CoursesRepository{
+		conn: conn,
+	}
+}
+
+// SaveCourse keeps the status of a course already in the catalog, only CloseCourse changes it.
+func (c CoursesRepository) SaveCourse(ctx context.Context, course entities.Course) error {
+	const statement = `
+	INSERT INTO courses (id, name, status, updated_at) VALUES ($1, $2, $3, now())
+	ON CONFLICT (id) DO UPDATE SET name = excluded.name, updated_at = excluded.updated_at`
+
+	_, err := c.conn.Exec(ctx, statement, course.ID, course.Name, course.Status)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c CoursesRepository) CloseCourse(ctx context.Context, id string) error {
+	const statement = `UPDATE courses SET status = $2, updated_at = now() WHERE id = $1`
+
+	exec, err := c.conn.Exec(ctx, statement, id, entities.CourseStatusClosed)
+	if err != nil {
+		return err
+	}
+
+	if exec.RowsAffected() == 0 {
+		return identities.ErrCourseNotFound
+	}
+
+	return nil
+}
+
+func (c CoursesRepository) GetCourse(ctx context.Context, id string) (entities.Course, error) {
+	const query = `SELECT id, name, status FROM courses WHERE id = $1`
+
+	var course entities.Course
+	err := c.conn.QueryRow(ctx, query, id).Scan(&course.ID, &course.Name, &course.Status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.Course{}, identities.ErrCourseNotFound
+		}
+		return entities.Course{}, err
+	}
+
+	return course, nil
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type DeadLettersRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewDeadLettersRepository(conn *pgxpool.Pool) DeadLettersRepository {
+	return DeadLettersRepository{
+		conn: conn,
+	}
+}
+
+const deadLetterColumns = `id, topic, key, event_id, event_type, event_time, headers, value, error, attempts, failed_at`
+
+// deadLetterHeader is how each record header is stored in the headers column, keeping their order.
+type deadLetterHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (d DeadLettersRepository) SaveDeadLetter(ctx context.Context, deadLetter entities.DeadLetter) error {
+	const statement = `
+	INSERT INTO dead_letters (` + deadLetterColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	ON CONFLICT (id) DO UPDATE SET error = excluded.error, attempts = excluded.attempts`
+
+	headers := make([]deadLetterHeader, 0, len(deadLetter.Headers))
+	for _, header := range deadLetter.Headers {
+		headers = append(headers, deadLetterHeader(header))
+	}
+
+	_, err := d.conn.Exec(ctx, statement,
+		deadLetter.ID, deadLetter.Topic, deadLetter.Key, deadLetter.EventID, deadLetter.EventType, deadLetter.EventTime,
+		headers, deadLetter.Value, deadLetter.Error, deadLetter.Attempts, deadLetter.FailedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListDeadLetters lists the dead letters from the oldest failure.
+func (d DeadLettersRepository) ListDeadLetters(ctx context.Context, filter identities.DeadLettersFilter) ([]entities.DeadLetter, error) {
+	query := `
+	SELECT ` + deadLetterColumns + `
+	FROM dead_letters
+	WHERE ($1 = '' OR topic = $1)
+	AND ($2 = '' OR key = $2)`
+	args := []any{filter.Topic, filter.Key, filter.Limit}
+	if filter.After != nil {
+		query += `
+	AND (failed_at, id) > ($4, $5)`
+		args = append(args, filter.After.FailedAt, filter.After.ID)
+	}
+	query += `
+	ORDER BY failed_at, id
+	LIMIT $3`
+
+	rows, err := d.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.DeadLetter, error) {
+		return deadLetterFromRow(row)
+	})
+}
+
+func (d DeadLettersRepository) GetDeadLetter(ctx context.Context, id string) (entities.DeadLetter, error) {
+	const query = `
+	SELECT ` + deadLetterColumns + `
+	FROM dead_letters
+	WHERE id = $1`
+
+	if _, err := uuid.Parse(id); err != nil {
+		return entities.DeadLetter{}, identities.ErrDeadLetterNotFound
+	}
+
+	deadLetter, err := deadLetterFromRow(d.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.DeadLetter{}, identities.ErrDeadLetterNotFound
+		}
+		return entities.DeadLetter{}, err
+	}
+
+	return deadLetter, nil
+}
+
+func (d DeadLettersRepository) DeleteDeadLetter(ctx context.Context, id string) error {
+	const statement = `DELETE FROM dead_letters WHERE id = $1`
+
+	if _, err := uuid.Parse(id); err != nil {
+		return identities.ErrDeadLetterNotFound
+	}
+
+	exec, err := d.conn.Exec(ctx, statement, id)
+	if err != nil {
+		return err
+	}
+	if exec.RowsAffected() == 0 {
+		return identities.ErrDeadLetterNotFound
+	}
+
+	return nil
+}
+
+func (d DeadLettersRepository) CountDeadLetters(ctx context.Context) (int, error) {
+	const query = `SELECT count(*) FROM dead_letters`
+
+	var count int
+	err := d.conn.QueryRow(ctx, query).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (d DeadLettersRepository) SavePublishedEvent(ctx context.Context, topic, key string, eventTime time.Time) error {
+	const statement = `
+	INSERT INTO published_events (topic, key, event_time)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (topic, key) DO UPDATE SET event_time = greatest(published_events.event_time, excluded.event_time)`
+
+	_, err := d.conn.Exec(ctx, statement, topic, key, eventTime)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d DeadLettersRepository) GetLastPublishedEvent(ctx context.Context, topic, key string) (time.Time, error) {
+	const query = `SELECT event_time FROM published_events WHERE topic = $1 AND key = $2`
+
+	var eventTime time.Time
+	err := d.conn.QueryRow(ctx, query, topic, key).Scan(&eventTime)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return eventTime.UTC(), nil
+}
+
+func deadLetterFromRow(row pgx.Row) (entities.DeadLetter, error) {
+	var (
+		deadLetter entities.DeadLetter
+		headers    []deadLetterHeader
+	)
+	err := row.Scan(&deadLetter.ID, &deadLetter.Topic, &deadLetter.Key, &deadLetter.EventID, &deadLetter.EventType, &deadLetter.EventTime, &headers,
+		&deadLetter.Value, &deadLetter.Error, &deadLetter.Attempts, &deadLetter.FailedAt)
+	if err != nil {
+		return entities.DeadLetter{}, err
+	}
+	deadLetter.EventTime = deadLetter.EventTime.UTC()
+	deadLetter.FailedAt = deadLetter.FailedAt.UTC()
+
+	for _, header := range headers {
+		deadLetter.Headers = append(deadLetter.Headers, entities.EventHeader(header))
+	}
+	return deadLetter, nil
+}
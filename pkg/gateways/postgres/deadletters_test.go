@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+)
+
+func TestDeadLettersRepository(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	repository := NewDeadLettersRepository(pgfixtures.NewDB(t))
+
+	headers := []entities.EventHeader{{Key: "ce_id", Value: "event-id"}, {Key: "ce_type", Value: "student_erased"}}
+	first := entities.NewDeadLetter("identity.cdc.students.0", "201116548712", "event-id", "student_erased", time.Date(2023, 8, 6, 9, 59, 0, 0, time.UTC), headers, []byte(`{}`), 3, errors.New("timeout"))
+	first.FailedAt = time.Date(2023, 8, 6, 10, 0, 0, 0, time.UTC)
+	second := entities.NewDeadLetter("identity.auth.students.0", "201116548713", "event-id", "student_logged_in", time.Date(2023, 8, 6, 10, 0, 0, 0, time.UTC), headers, []byte(`{}`), 1, errors.New("timeout"))
+	second.FailedAt = first.FailedAt.Add(time.Minute)
+
+	require.NoError(t, repository.SaveDeadLetter(ctx, second))
+	require.NoError(t, repository.SaveDeadLetter(ctx, first))
+
+	// test and assert
+	got, err := repository.GetDeadLetter(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, got)
+
+	listed, err := repository.ListDeadLetters(ctx, identities.DeadLettersFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.DeadLetter{first, second}, listed)
+
+	listed, err = repository.ListDeadLetters(ctx, identities.DeadLettersFilter{
+		After: &identities.DeadLettersCursor{FailedAt: first.FailedAt, ID: first.ID},
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.DeadLetter{second}, listed)
+
+	listed, err = repository.ListDeadLetters(ctx, identities.DeadLettersFilter{Topic: "identity.cdc.students.0", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.DeadLetter{first}, listed)
+
+	listed, err = repository.ListDeadLetters(ctx, identities.DeadLettersFilter{Key: "201116548713", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.DeadLetter{second}, listed)
+
+	lastPublished, err := repository.GetLastPublishedEvent(ctx, first.Topic, first.Key)
+	require.NoError(t, err)
+	assert.Zero(t, lastPublished)
+
+	require.NoError(t, repository.SavePublishedEvent(ctx, first.Topic, first.Key, first.EventTime.Add(time.Minute)))
+	require.NoError(t, repository.SavePublishedEvent(ctx, first.Topic, first.Key, first.EventTime))
+	lastPublished, err = repository.GetLastPublishedEvent(ctx, first.Topic, first.Key)
+	require.NoError(t, err)
+	assert.Equal(t, first.EventTime.Add(time.Minute), lastPublished)
+
+	first.Attempts++
+	first.Error = "broker unreachable"
+	require.NoError(t, repository.SaveDeadLetter(ctx, first))
+	got, err = repository.GetDeadLetter(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, got)
+
+	count, err := repository.CountDeadLetters(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.NoError(t, repository.DeleteDeadLetter(ctx, first.ID))
+	assert.ErrorIs(t, repository.DeleteDeadLetter(ctx, first.ID), identities.ErrDeadLetterNotFound)
+
+	_, err = repository.GetDeadLetter(ctx, first.ID)
+	assert.ErrorIs(t, err, identities.ErrDeadLetterNotFound)
+	_, err = repository.GetDeadLetter(ctx, "not-a-uuid")
+	assert.ErrorIs(t, err, identities.ErrDeadLetterNotFound)
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+)
+
+type LoginHistoryRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewLoginHistoryRepository(conn *pgxpool.Pool) LoginHistoryRepository {
+	return LoginHistoryRepository{
+		conn: conn,
+	}
+}
+
+func (l LoginHistoryRepository) RecordLogin(ctx context.Context, attempt entities.LoginAttempt) error {
+	const statement = `
+	INSERT INTO student_logins (student_id, succeeded, failure_reason, attempted_at) VALUES ($1, $2, $3, $4)`
+
+	_, err := l.conn.Exec(ctx, statement, attempt.StudentID, attempt.Succeeded, attempt.FailureReason, attempt.AttemptedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListLogins returns the student login history from the latest attempt.
+func (l LoginHistoryRepository) ListLogins(ctx context.Context, studentID string) ([]entities.LoginAttempt, error) {
+	const query = `
+	SELECT student_id, succeeded, failure_reason, attempted_at
+	FROM student_logins
+	WHERE student_id = $1
+	ORDER BY attempted_at DESC`
+
+	rows, err := l.conn.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.LoginAttempt, error) {
+		var attempt entities.LoginAttempt
+		err := row.Scan(&attempt.StudentID, &attempt.Succeeded, &attempt.FailureReason, &attempt.AttemptedAt)
+		return attempt, err
+	})
+}
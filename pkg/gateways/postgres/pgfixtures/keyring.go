@@ -0,0 +1,22 @@
+package pgfixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/encryption"
+)
+
+// NewKeyring builds a keyring with a random master key, for the students repository.
+func NewKeyring(t *testing.T) encryption.Keyring {
+	t.Helper()
+
+	key, err := encryption.NewKey()
+	require.NoError(t, err)
+
+	keyring, err := encryption.NewKeyring(key)
+	require.NoError(t, err)
+
+	return keyring
+}
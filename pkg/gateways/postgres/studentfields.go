@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/encryption"
+)
+
+// Blind index fields, so the same value in both never gets the same index.
+const (
+	cpfIndexField   = "cpf"
+	emailIndexField = "email"
+)
+
+// studentColumns are the columns studentFromRow scans, in order.
+const studentColumns = `id, name, cpf, email, birth_date, status, coalesce(course_id::text, ''), created_at, erased_at,
+		data_key, cpf_encrypted, email_encrypted, birth_date_encrypted`
+
+var nonDigits = regexp.MustCompile(`\D`)
+
+// sealedStudent has the student personal data as it is stored, see the students encryption migration.
+type sealedStudent struct {
+	dataKey    string
+	cpf        string
+	email      string
+	birthDate  string
+	cpfIndex   []byte
+	emailIndex []byte
+}
+
+// seal encrypts the student personal data with a new data key, each field is bound to the student and to
+// its column so sealed values can not be swapped around.
+func (s StudentsRepository) seal(student entities.Student) (sealedStudent, error) {
+	key, wrapped, err := s.keyring.NewDataKey()
+	if err != nil {
+		return sealedStudent{}, err
+	}
+
+	sealed := sealedStudent{
+		dataKey:    wrapped,
+		cpfIndex:   s.cpfIndex(student.CPF),
+		emailIndex: s.emailIndex(student.Email),
+	}
+	fields := []struct {
+		sealed *string
+		column string
+		value  string
+	}{
+		{&sealed.cpf, "cpf", student.CPF},
+		{&sealed.email, "email", student.Email},
+		{&sealed.birthDate, "birth_date", student.BirthDate.Format(time.DateOnly)},
+	}
+	for _, field := range fields {
+		*field.sealed, err = encryption.SealField(key, field.value, fieldAdditionalData(student.ID, field.column))
+		if err != nil {
+			return sealedStudent{}, err
+		}
+	}
+
+	return sealed, nil
+}
+
+// cpfIndex normalizes the CPF the same way the plain unique index does.
+func (s StudentsRepository) cpfIndex(cpf string) []byte {
+	return s.keyring.BlindIndex(cpfIndexField, nonDigits.ReplaceAllString(cpf, ""))
+}
+
+// emailIndex normalizes the email the same way the plain unique index does.
+func (s StudentsRepository) emailIndex(email string) []byte {
+	return s.keyring.BlindIndex(emailIndexField, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// studentFromRow scans the studentColumns, opening the sealed fields. Students stored before the encryption,
+// and the erased ones, have no data key and are read as they are.
+func (s StudentsRepository) studentFromRow(row pgx.Row) (entities.Student, error) {
+	var (
+		student                                          entities.Student
+		cpf, email                                       pgtype.Text
+		birthDate                                        pgtype.Date
+		erasedAt                                         pgtype.Timestamptz
+		dataKey, sealedCPF, sealedEmail, sealedBirthDate pgtype.Text
+	)
+	err := row.Scan(
+		&student.ID, &student.Name, &cpf, &email, &birthDate, &student.Status, &student.CourseID, &student.CreatedAt, &erasedAt,
+		&dataKey, &sealedCPF, &sealedEmail, &sealedBirthDate,
+	)
+	if err != nil {
+		return entities.Student{}, err
+	}
+
+	student.CPF = cpf.String
+	student.Email = email.String
+	student.BirthDate = birthDate.Time
+	student.ErasedAt = erasedAt.Time
+	if !dataKey.Valid {
+		return student, nil
+	}
+
+	key, err := s.keyring.UnwrapDataKey(dataKey.String)
+	if err != nil {
+		return entities.Student{}, err
+	}
+
+	var openedBirthDate string
+	fields := []struct {
+		opened *string
+		column string
+		sealed string
+	}{
+		{&student.CPF, "cpf", sealedCPF.String},
+		{&student.Email, "email", sealedEmail.String},
+		{&openedBirthDate, "birth_date", sealedBirthDate.String},
+	}
+	for _, field := range fields {
+		*field.opened, err = encryption.OpenField(key, field.sealed, fieldAdditionalData(student.ID, field.column))
+		if err != nil {
+			return entities.Student{}, err
+		}
+	}
+
+	student.BirthDate, err = time.Parse(time.DateOnly, openedBirthDate)
+	if err != nil {
+		return entities.Student{}, err
+	}
+
+	return student, nil
+}
+
+func fieldAdditionalData(studentID string, column string) string {
+	return studentID + ":" + column
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+type StudentKeysRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewStudentKeysRepository(conn *pgxpool.Pool) StudentKeysRepository {
+	return StudentKeysRepository{
+		conn: conn,
+	}
+}
+
+// GetOrCreateStudentKey stores a new key unless the student already has one, concurrent calls all get the
+// stored key.
+func (k StudentKeysRepository) GetOrCreateStudentKey(ctx context.Context, studentID string) (entities.StudentKey, error) {
+	const statement = `
+	INSERT INTO student_keys (student_id, key_id, key) VALUES ($1, $2, $3)
+	ON CONFLICT (student_id) DO NOTHING`
+
+	key, err := entities.NewStudentKey(studentID)
+	if err != nil {
+		return entities.StudentKey{}, err
+	}
+
+	exec, err := k.conn.Exec(ctx, statement, key.StudentID, key.ID, key.Key)
+	if err != nil {
+		return entities.StudentKey{}, err
+	}
+
+	if exec.RowsAffected() == 1 {
+		return key, nil
+	}
+
+	return k.GetStudentKey(ctx, studentID)
+}
+
+func (k StudentKeysRepository) GetStudentKey(ctx context.Context, studentID string) (entities.StudentKey, error) {
+	const query = `SELECT key_id::text, student_id, key FROM student_keys WHERE student_id = $1`
+
+	var key entities.StudentKey
+	err := k.conn.QueryRow(ctx, query, studentID).Scan(&key.ID, &key.StudentID, &key.Key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.StudentKey{}, identities.ErrStudentKeyNotFound
+		}
+		return entities.StudentKey{}, err
+	}
+
+	return key, nil
+}
@@ -3,42 +3,83 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/tccav/identity-service/pkg/domain/entities"
 	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/encryption"
+)
+
+// Unique indexes on the student CPF and email blind indexes, see the students migrations.
+const (
+	cpfUniqueIndex   = "students_cpf_key"
+	emailUniqueIndex = "students_email_key"
 )
 
 type StudentsRepository struct {
-	conn *pgxpool.Pool
+	conn    *pgxpool.Pool
+	keyring encryption.Keyring
 }
 
-func NewStudentsRepository(conn *pgxpool.Pool) StudentsRepository {
+// NewStudentsRepository stores the student CPF, email and birth date encrypted by the keyring, along with
+// the CPF and email blind indexes that keep them unique.
+func NewStudentsRepository(conn *pgxpool.Pool, keyring encryption.Keyring) StudentsRepository {
 	return StudentsRepository{
-		conn: conn,
+		conn:    conn,
+		keyring: keyring,
 	}
 }
 
 func (s StudentsRepository) CreateStudent(ctx context.Context, student entities.Student) error {
 	const statement = `
-	INSERT INTO students (id, name, secret, birth_date, cpf, email) VALUES (
+	INSERT INTO students (id, name, secret, status, course_id, data_key, cpf_encrypted, email_encrypted, birth_date_encrypted, cpf_index, email_index) VALUES (
 		$1,
 	    $2,
 		$3,
 	    $4,
 	    $5,
-	 	$6                                                                       
+	 	$6,
+	 	$7,
+	 	$8,
+	 	$9,
+	 	$10,
+	 	$11
 	)`
 
-	exec, err := s.conn.Exec(ctx, statement, student.ID, student.Name, student.Secret, student.BirthDate, student.CPF, student.Email)
+	err := s.checkPlainConflicts(ctx, student)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := s.seal(student)
+	if err != nil {
+		return err
+	}
+
+	exec, err := s.conn.Exec(ctx, statement,
+		student.ID, student.Name, student.Secret, student.Status, courseID(student),
+		sealed.dataKey, sealed.cpf, sealed.email, sealed.birthDate, sealed.cpfIndex, sealed.emailIndex,
+	)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			return identities.ErrStudentAlreadyExists
+			switch pgErr.ConstraintName {
+			case cpfUniqueIndex:
+				return identities.ErrCPFAlreadyRegistered
+			case emailUniqueIndex:
+				return identities.ErrEmailAlreadyRegistered
+			default:
+				return identities.ErrStudentAlreadyExists
+			}
 		}
 		return err
 	}
@@ -50,6 +91,33 @@ func (s StudentsRepository) CreateStudent(ctx context.Context, student entities.
 	return nil
 }
 
+// checkPlainConflicts looks for the CPF and email among the students not encrypted yet, which the blind
+// indexes do not cover. Students are no longer stored in plain, so there is no race with the insert.
+func (s StudentsRepository) checkPlainConflicts(ctx context.Context, student entities.Student) error {
+	const query = `
+	SELECT
+		coalesce(bool_or(regexp_replace(cpf, '\D', '', 'g') = regexp_replace($1, '\D', '', 'g')), false),
+		coalesce(bool_or(lower(trim(email)) = lower(trim($2))), false)
+	FROM students
+	WHERE erased_at IS NULL
+	AND (regexp_replace(cpf, '\D', '', 'g') = regexp_replace($1, '\D', '', 'g') OR lower(trim(email)) = lower(trim($2)))`
+
+	var sameCPF, sameEmail bool
+	err := s.conn.QueryRow(ctx, query, student.CPF, student.Email).Scan(&sameCPF, &sameEmail)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case sameCPF:
+		return identities.ErrCPFAlreadyRegistered
+	case sameEmail:
+		return identities.ErrEmailAlreadyRegistered
+	default:
+		return nil
+	}
+}
+
 func (s StudentsRepository) GetStudentSecret(ctx context.Context, id string) (string, error) {
 	const query = `SELECT secret FROM students WHERE id=$1`
 
@@ -64,3 +132,396 @@ func (s StudentsRepository) GetStudentSecret(ctx context.Context, id string) (st
 
 	return secret, nil
 }
+
+func (s StudentsRepository) GetStudent(ctx context.Context, id string) (entities.Student, error) {
+	const query = `
+	SELECT ` + studentColumns + `
+	FROM students
+	WHERE id=$1`
+
+	student, err := s.studentFromRow(s.conn.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entities.Student{}, identities.ErrStudentNotFound
+		}
+		return entities.Student{}, err
+	}
+
+	return student, nil
+}
+
+func (s StudentsRepository) GetStudentStatus(ctx context.Context, id string) (entities.StudentStatus, error) {
+	const query = `SELECT status FROM students WHERE id=$1`
+
+	var status entities.StudentStatus
+	err := s.conn.QueryRow(ctx, query, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", identities.ErrStudentNotFound
+		}
+		return "", err
+	}
+
+	return status, nil
+}
+
+// UpdateStudentStatus only applies the transition if the student is still in its starting status.
+func (s StudentsRepository) UpdateStudentStatus(ctx context.Context, transition entities.StudentStatusTransition) error {
+	const statement = `UPDATE students SET status=$3 WHERE id=$1 AND status=$2`
+
+	exec, err := s.conn.Exec(ctx, statement, transition.StudentID, transition.From, transition.To)
+	if err != nil {
+		return err
+	}
+
+	if exec.RowsAffected() == 0 {
+		return identities.ErrStudentStatusChanged
+	}
+
+	return nil
+}
+
+// DuplicateGroup lists the students sharing the same normalized value of a unique field.
+type DuplicateGroup struct {
+	Field      string   `json:"field"`
+	Value      string   `json:"value"`
+	StudentIDs []string `json:"student_ids"`
+}
+
+// FindDuplicates reports the students that share a CPF or an email once normalized the same
+// way the unique indexes do. Only students not encrypted yet are compared, the encrypted ones are kept
+// unique by their blind indexes.
+func (s StudentsRepository) FindDuplicates(ctx context.Context) ([]DuplicateGroup, error) {
+	const query = `
+	SELECT 'cpf', regexp_replace(cpf, '\D', '', 'g') AS value, array_agg(id ORDER BY id)
+	FROM students
+	WHERE erased_at IS NULL AND cpf IS NOT NULL
+	GROUP BY value
+	HAVING count(*) > 1
+	UNION ALL
+	SELECT 'email', lower(trim(email)) AS value, array_agg(id ORDER BY id)
+	FROM students
+	WHERE erased_at IS NULL AND email IS NOT NULL
+	GROUP BY value
+	HAVING count(*) > 1
+	ORDER BY 1, 2`
+
+	rows, err := s.conn.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (DuplicateGroup, error) {
+		var group DuplicateGroup
+		err := row.Scan(&group.Field, &group.Value, &group.StudentIDs)
+		return group, err
+	})
+}
+
+// EncryptionBatch reports a batch of students having their personal data encrypted.
+type EncryptionBatch struct {
+	// LastID is where the next batch starts from, it is empty once there is nothing left.
+	LastID    string
+	Encrypted int
+	// Conflicts lists the students whose CPF or email is already used by an encrypted student, they are left
+	// in plain until the duplicate is solved.
+	Conflicts []string
+}
+
+// EncryptStudents encrypts the personal data of the students stored in plain, from the first id after the
+// informed one. Each student is updated on its own, so the table is never locked for long and the batch can
+// run along with the app.
+func (s StudentsRepository) EncryptStudents(ctx context.Context, after string, limit int) (EncryptionBatch, error) {
+	const query = `
+	SELECT id, cpf, email, birth_date
+	FROM students
+	WHERE data_key IS NULL AND erased_at IS NULL AND id > $1
+	ORDER BY id
+	LIMIT $2`
+
+	const statement = `
+	UPDATE students
+	SET data_key = $2, cpf_encrypted = $3, email_encrypted = $4, birth_date_encrypted = $5, cpf_index = $6, email_index = $7,
+		cpf = NULL, email = NULL, birth_date = NULL
+	WHERE id = $1 AND data_key IS NULL AND erased_at IS NULL`
+
+	rows, err := s.conn.Query(ctx, query, after, limit)
+	if err != nil {
+		return EncryptionBatch{}, err
+	}
+
+	students, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.Student, error) {
+		var student entities.Student
+		err := row.Scan(&student.ID, &student.CPF, &student.Email, &student.BirthDate)
+		return student, err
+	})
+	if err != nil {
+		return EncryptionBatch{}, err
+	}
+
+	var batch EncryptionBatch
+	for _, student := range students {
+		sealed, err := s.seal(student)
+		if err != nil {
+			return EncryptionBatch{}, err
+		}
+
+		exec, err := s.conn.Exec(ctx, statement,
+			student.ID, sealed.dataKey, sealed.cpf, sealed.email, sealed.birthDate, sealed.cpfIndex, sealed.emailIndex,
+		)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+				batch.Conflicts = append(batch.Conflicts, student.ID)
+				batch.LastID = student.ID
+				continue
+			}
+			return EncryptionBatch{}, err
+		}
+
+		batch.Encrypted += int(exec.RowsAffected())
+		batch.LastID = student.ID
+	}
+
+	return batch, nil
+}
+
+// StudentsRange selects the students streamed by StreamStudents, empty fields are not filtered by. After is
+// exclusive and Until inclusive, so the last ID of a batch is where the next one starts from.
+type StudentsRange struct {
+	After        string
+	Until        string
+	CreatedAfter time.Time
+	Limit        int
+}
+
+// StreamStudents lists the students in the range by ascending ID, a page at a time, erased ones included.
+// Paging by ID rather than by offset keeps every page as cheap as the first one on a large table.
+func (s StudentsRepository) StreamStudents(ctx context.Context, r StudentsRange) ([]entities.Student, error) {
+	const query = `
+	SELECT ` + studentColumns + `
+	FROM students
+	WHERE id > $1 AND ($2 = '' OR id <= $2) AND ($3::timestamptz IS NULL OR created_at > $3)
+	ORDER BY id
+	LIMIT $4`
+
+	var createdAfter *time.Time
+	if !r.CreatedAfter.IsZero() {
+		createdAfter = &r.CreatedAfter
+	}
+
+	rows, err := s.conn.Query(ctx, query, r.After, r.Until, createdAfter, r.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.Student, error) {
+		return s.studentFromRow(row)
+	})
+}
+
+// CreateStudents stores every student in a single transaction using the copy protocol, a conflict on
+// any of them aborts the whole batch.
+func (s StudentsRepository) CreateStudents(ctx context.Context, students []entities.Student) error {
+	rows := make([][]any, 0, len(students))
+	for _, student := range students {
+		sealed, err := s.seal(student)
+		if err != nil {
+			return err
+		}
+
+		rows = append(rows, []any{
+			student.ID, student.Name, student.Secret, student.Status, courseID(student),
+			sealed.dataKey, sealed.cpf, sealed.email, sealed.birthDate, sealed.cpfIndex, sealed.emailIndex,
+		})
+	}
+
+	return pgx.BeginFunc(ctx, s.conn, func(tx pgx.Tx) error {
+		count, err := tx.CopyFrom(
+			ctx,
+			pgx.Identifier{"students"},
+			[]string{"id", "name", "secret", "status", "course_id", "data_key", "cpf_encrypted", "email_encrypted", "birth_date_encrypted", "cpf_index", "email_index"},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return err
+		}
+
+		if count != int64(len(students)) {
+			return errors.New("students not stored")
+		}
+		return nil
+	})
+}
+
+// FindConflicts matches the students against the stored ones by id, CPF and email, either by their blind
+// indexes or, for students not encrypted yet, by their normalized values. Erased students only conflict by
+// id, the same way the unique indexes do.
+func (s StudentsRepository) FindConflicts(ctx context.Context, students []entities.Student) ([]error, error) {
+	const query = `
+	SELECT c.ord,
+		bool_or(s.id = c.id),
+		coalesce(bool_or(s.cpf_index = c.cpf_index OR regexp_replace(s.cpf, '\D', '', 'g') = regexp_replace(c.cpf, '\D', '', 'g')), false),
+		coalesce(bool_or(s.email_index = c.email_index OR lower(trim(s.email)) = lower(trim(c.email))), false)
+	FROM unnest($1::text[], $2::text[], $3::text[], $4::bytea[], $5::bytea[]) WITH ORDINALITY AS c(id, cpf, email, cpf_index, email_index, ord)
+	JOIN students s ON s.id = c.id
+		OR s.erased_at IS NULL AND (
+			s.cpf_index = c.cpf_index
+			OR s.email_index = c.email_index
+			OR regexp_replace(s.cpf, '\D', '', 'g') = regexp_replace(c.cpf, '\D', '', 'g')
+			OR lower(trim(s.email)) = lower(trim(c.email))
+		)
+	GROUP BY c.ord`
+
+	ids := make([]string, 0, len(students))
+	cpfs := make([]string, 0, len(students))
+	emails := make([]string, 0, len(students))
+	cpfIndexes := make([][]byte, 0, len(students))
+	emailIndexes := make([][]byte, 0, len(students))
+	for _, student := range students {
+		ids = append(ids, student.ID)
+		cpfs = append(cpfs, student.CPF)
+		emails = append(emails, student.Email)
+		cpfIndexes = append(cpfIndexes, s.cpfIndex(student.CPF))
+		emailIndexes = append(emailIndexes, s.emailIndex(student.Email))
+	}
+
+	rows, err := s.conn.Query(ctx, query, ids, cpfs, emails, cpfIndexes, emailIndexes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conflicts := make([]error, len(students))
+	for rows.Next() {
+		var (
+			ord                        int
+			sameID, sameCPF, sameEmail bool
+		)
+		err = rows.Scan(&ord, &sameID, &sameCPF, &sameEmail)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case sameID:
+			conflicts[ord-1] = identities.ErrStudentAlreadyExists
+		case sameCPF:
+			conflicts[ord-1] = identities.ErrCPFAlreadyRegistered
+		case sameEmail:
+			conflicts[ord-1] = identities.ErrEmailAlreadyRegistered
+		}
+	}
+
+	return conflicts, rows.Err()
+}
+
+// EraseStudent blanks every personal data column, including the sealed ones, and cancels the student, which also keeps it from logging in
+// again. The login history and the student key go in the same transaction, so no personal data is left behind
+// if any of them fails.
+func (s StudentsRepository) EraseStudent(ctx context.Context, erasure entities.StudentErasure) (entities.StudentErasure, error) {
+	const eraseStatement = `
+	UPDATE students
+	SET name = '', secret = '', cpf = NULL, email = NULL, birth_date = NULL,
+		data_key = NULL, cpf_encrypted = NULL, email_encrypted = NULL, birth_date_encrypted = NULL, cpf_index = NULL, email_index = NULL,
+		status = $2, erased_at = coalesce(erased_at, $3)
+	WHERE id = $1
+	RETURNING erased_at`
+
+	err := pgx.BeginFunc(ctx, s.conn, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, eraseStatement, erasure.StudentID, entities.StudentStatusCancelled, erasure.ErasedAt).
+			Scan(&erasure.ErasedAt)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return identities.ErrStudentNotFound
+			}
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM student_logins WHERE student_id = $1`, erasure.StudentID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM student_keys WHERE student_id = $1`, erasure.StudentID)
+		return err
+	})
+	if err != nil {
+		return entities.StudentErasure{}, err
+	}
+
+	erasure.ErasedAt = erasure.ErasedAt.UTC()
+	return erasure, nil
+}
+
+// courseID converts the student course to a nullable uuid, students registered before courses were tracked
+// have none.
+func courseID(student entities.Student) pgtype.UUID {
+	id, err := uuid.Parse(student.CourseID)
+	if err != nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+// SearchStudents lists the students from the latest registered one. The name is matched ignoring accents,
+// either by its words, by a part of it or by a similar one, all of them backed by the students name indexes.
+func (s StudentsRepository) SearchStudents(ctx context.Context, filter identities.StudentsFilter) ([]entities.Student, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Name != "" {
+		name := arg(filter.Name)
+		conditions = append(conditions, fmt.Sprintf(`(
+		to_tsvector('simple', immutable_unaccent(name)) @@ plainto_tsquery('simple', immutable_unaccent(%[1]s))
+		OR immutable_unaccent(lower(name)) LIKE '%%' || immutable_unaccent(lower(%[1]s)) || '%%'
+		OR immutable_unaccent(lower(name)) %% immutable_unaccent(lower(%[1]s))
+	)`, name))
+	}
+	if filter.Email != "" {
+		conditions = append(conditions, fmt.Sprintf("(email_index = %s OR lower(trim(email)) = lower(trim(%s)))",
+			arg(s.emailIndex(filter.Email)), arg(filter.Email)))
+	}
+	if filter.CPF != "" {
+		conditions = append(conditions, fmt.Sprintf(`(cpf_index = %s OR regexp_replace(cpf, '\D', '', 'g') = regexp_replace(%s, '\D', '', 'g'))`,
+			arg(s.cpfIndex(filter.CPF)), arg(filter.CPF)))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = %s", arg(filter.Status)))
+	}
+	if filter.CourseID != "" {
+		conditions = append(conditions, fmt.Sprintf("course_id = %s::uuid", arg(filter.CourseID)))
+	}
+	if !filter.RegisteredFrom.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at >= %s", arg(filter.RegisteredFrom)))
+	}
+	if !filter.RegisteredUntil.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("created_at < %s", arg(filter.RegisteredUntil)))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", arg(filter.After.CreatedAt), arg(filter.After.ID)))
+	}
+
+	query := `
+	SELECT ` + studentColumns + `
+	FROM students`
+	if len(conditions) > 0 {
+		query += "\n\tWHERE " + strings.Join(conditions, "\n\tAND ")
+	}
+	query += fmt.Sprintf("\n\tORDER BY created_at DESC, id DESC\n\tLIMIT %s", arg(filter.Limit))
+
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.Student, error) {
+		return s.studentFromRow(row)
+	})
+}
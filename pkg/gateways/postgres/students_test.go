@@ -0,0 +1,386 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+)
+
+var validStudent = entities.Student{
+	ID:        "201116548712",
+	Name:      "John Doe",
+	Secret:    "secret",
+	CPF:       "11111111030",
+	Email:     "jdoe@ol.com",
+	BirthDate: time.Date(1994, 3, 19, 0, 0, 0, 0, time.UTC),
+	Status:    entities.StudentStatusActive,
+}
+
+func TestStudentsRepository_CreateStudent(t *testing.T) {
+	t.Parallel()
+
+	withChanges := func(change func(s *entities.Student)) entities.Student {
+		s := validStudent
+		change(&s)
+		return s
+	}
+
+	tt := []struct {
+		name    string
+		input   entities.Student
+		wantErr error
+	}{
+		{
+			name:    "should fail because student id already exists",
+			input:   withChanges(func(s *entities.Student) { s.CPF, s.Email = "52998224725", "other@ol.com" }),
+			wantErr: identities.ErrStudentAlreadyExists,
+		},
+		{
+			name:    "should fail because cpf is already registered",
+			input:   withChanges(func(s *entities.Student) { s.ID, s.Email = "201116548799", "other@ol.com" }),
+			wantErr: identities.ErrCPFAlreadyRegistered,
+		},
+		{
+			name:    "should fail because email is already registered regardless of its case",
+			input:   withChanges(func(s *entities.Student) { s.ID, s.CPF, s.Email = "201116548799", "52998224725", " JDoe@OL.com" }),
+			wantErr: identities.ErrEmailAlreadyRegistered,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// prepare
+			ctx := context.Background()
+			repository := NewStudentsRepository(pgfixtures.NewDB(t), pgfixtures.NewKeyring(t))
+			require.NoError(t, repository.CreateStudent(ctx, validStudent))
+
+			// test
+			err := repository.CreateStudent(ctx, tc.input)
+
+			// assert
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestStudentsRepository_FindDuplicates(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	db := pgfixtures.NewDB(t)
+	repository := NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+
+	// duplicates can only exist in databases created before the unique indexes
+	_, err := db.Exec(ctx, `DROP INDEX students_plain_cpf_key; DROP INDEX students_plain_email_key`)
+	require.NoError(t, err)
+
+	for _, s := range []entities.Student{
+		validStudent,
+		{ID: "201116548713", CPF: "111.111.110-30", Email: "john@ol.com"},
+		{ID: "201116548714", CPF: "52998224725", Email: "JDOE@ol.com"},
+		{ID: "201116548715", CPF: "39053344705", Email: "unique@ol.com"},
+	} {
+		insertPlainStudent(t, db, s)
+	}
+	require.NoError(t, repository.CreateStudent(ctx, entities.Student{
+		ID: "201116548716", CPF: "71428793860", Email: "encrypted@ol.com", Status: entities.StudentStatusActive,
+	}))
+
+	// test
+	got, err := repository.FindDuplicates(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []DuplicateGroup{
+		{Field: "cpf", Value: "11111111030", StudentIDs: []string{"201116548712", "201116548713"}},
+		{Field: "email", Value: "jdoe@ol.com", StudentIDs: []string{"201116548712", "201116548714"}},
+	}, got)
+}
+
+func TestStudentsRepository_SearchStudents(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time {
+		return time.Date(2023, 7, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	courseID := "1b0e3bb2-5f1a-4d4c-9d8e-4c3a3a7c6f10"
+	joao := entities.Student{ID: "201116548712", Name: "João da Silva", CPF: "11111111030", Email: "jsilva@ol.com", Status: entities.StudentStatusActive, CreatedAt: day(1)}
+	joana := entities.Student{ID: "201116548713", Name: "Joana Pereira", CPF: "52998224725", Email: "jpereira@ol.com", Status: entities.StudentStatusActive, CreatedAt: day(2)}
+	maria := entities.Student{ID: "201116548714", Name: "Maria Souza", CPF: "39053344705", Email: "msouza@ol.com", Status: entities.StudentStatusSuspended, CourseID: courseID, CreatedAt: day(3)}
+
+	tt := []struct {
+		name   string
+		filter identities.StudentsFilter
+		want   []string
+	}{
+		{
+			name: "should list every student from the latest registered",
+			want: []string{maria.ID, joana.ID, joao.ID},
+		},
+		{
+			name:   "should find name words ignoring accents",
+			filter: identities.StudentsFilter{Name: "joao"},
+			want:   []string{joao.ID},
+		},
+		{
+			name:   "should find part of the name",
+			filter: identities.StudentsFilter{Name: "Joã"},
+			want:   []string{joana.ID, joao.ID},
+		},
+		{
+			name:   "should find similar name",
+			filter: identities.StudentsFilter{Name: "Mria Sousa"},
+			want:   []string{maria.ID},
+		},
+		{
+			name:   "should filter by normalized email",
+			filter: identities.StudentsFilter{Email: " JPereira@OL.com"},
+			want:   []string{joana.ID},
+		},
+		{
+			name:   "should filter by cpf with punctuation",
+			filter: identities.StudentsFilter{CPF: "111.111.110-30"},
+			want:   []string{joao.ID},
+		},
+		{
+			name:   "should filter by status and course",
+			filter: identities.StudentsFilter{Status: entities.StudentStatusSuspended, CourseID: courseID},
+			want:   []string{maria.ID},
+		},
+		{
+			name:   "should filter by registration range",
+			filter: identities.StudentsFilter{RegisteredFrom: day(2), RegisteredUntil: day(3)},
+			want:   []string{joana.ID},
+		},
+		{
+			name:   "should list the page after the cursor",
+			filter: identities.StudentsFilter{After: &identities.StudentsCursor{CreatedAt: maria.CreatedAt, ID: maria.ID}, Limit: 1},
+			want:   []string{joana.ID},
+		},
+	}
+
+	// prepare
+	ctx := context.Background()
+	db := pgfixtures.NewDB(t)
+	repository := NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+	for _, s := range []entities.Student{joao, joana, maria} {
+		require.NoError(t, repository.CreateStudent(ctx, s))
+		_, err := db.Exec(ctx, `UPDATE students SET created_at=$2 WHERE id=$1`, s.ID, s.CreatedAt)
+		require.NoError(t, err)
+	}
+
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if tc.filter.Limit == 0 {
+				tc.filter.Limit = 10
+			}
+
+			// test
+			got, err := repository.SearchStudents(ctx, tc.filter)
+
+			// assert
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got))
+			for _, s := range got {
+				ids = append(ids, s.ID)
+			}
+			assert.Equal(t, tc.want, ids)
+		})
+	}
+}
+
+func TestStudentsRepository_StreamStudents(t *testing.T) {
+	t.Parallel()
+
+	day := func(d int) time.Time {
+		return time.Date(2023, 7, d, 12, 0, 0, 0, time.UTC)
+	}
+
+	joao := entities.Student{ID: "201116548712", Name: "João da Silva", CPF: "11111111030", Email: "jsilva@ol.com", Status: entities.StudentStatusActive, CreatedAt: day(3)}
+	joana := entities.Student{ID: "201116548713", Name: "Joana Pereira", CPF: "52998224725", Email: "jpereira@ol.com", Status: entities.StudentStatusActive, CreatedAt: day(1)}
+	maria := entities.Student{ID: "201116548714", Name: "Maria Souza", CPF: "39053344705", Email: "msouza@ol.com", Status: entities.StudentStatusSuspended, CreatedAt: day(2)}
+
+	tt := []struct {
+		name   string
+		filter StudentsRange
+		want   []string
+	}{
+		{
+			name: "should list every student by id",
+			want: []string{joao.ID, joana.ID, maria.ID},
+		},
+		{
+			name:   "should list the page after the id",
+			filter: StudentsRange{After: joao.ID, Limit: 1},
+			want:   []string{joana.ID},
+		},
+		{
+			name:   "should list the students until the id",
+			filter: StudentsRange{Until: joana.ID},
+			want:   []string{joao.ID, joana.ID},
+		},
+		{
+			name:   "should list the students created after the date",
+			filter: StudentsRange{CreatedAfter: day(1)},
+			want:   []string{joao.ID, maria.ID},
+		},
+	}
+
+	// prepare
+	ctx := context.Background()
+	db := pgfixtures.NewDB(t)
+	repository := NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+	for _, s := range []entities.Student{joao, joana, maria} {
+		require.NoError(t, repository.CreateStudent(ctx, s))
+		_, err := db.Exec(ctx, `UPDATE students SET created_at=$2 WHERE id=$1`, s.ID, s.CreatedAt)
+		require.NoError(t, err)
+	}
+
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if tc.filter.Limit == 0 {
+				tc.filter.Limit = 10
+			}
+
+			// test
+			got, err := repository.StreamStudents(ctx, tc.filter)
+
+			// assert
+			require.NoError(t, err)
+			ids := make([]string, 0, len(got))
+			for _, s := range got {
+				ids = append(ids, s.ID)
+			}
+			assert.Equal(t, tc.want, ids)
+		})
+	}
+}
+
+func TestStudentsRepository_EraseStudent(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	db := pgfixtures.NewDB(t)
+	repository := NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+	keysRepository := NewStudentKeysRepository(db)
+
+	require.NoError(t, repository.CreateStudent(ctx, validStudent))
+	require.NoError(t, NewLoginHistoryRepository(db).RecordLogin(ctx, entities.NewLoginAttempt(validStudent.ID, "")))
+	_, err := keysRepository.GetOrCreateStudentKey(ctx, validStudent.ID)
+	require.NoError(t, err)
+
+	// test
+	got, err := repository.EraseStudent(ctx, entities.NewStudentErasure(validStudent.ID))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, validStudent.ID, got.StudentID)
+
+	student, err := repository.GetStudent(ctx, validStudent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, student.Name)
+	assert.Empty(t, student.CPF)
+	assert.Empty(t, student.Email)
+	assert.Equal(t, entities.StudentStatusCancelled, student.Status)
+	assert.WithinDuration(t, got.ErasedAt, student.ErasedAt, time.Millisecond)
+
+	logins, err := NewLoginHistoryRepository(db).ListLogins(ctx, validStudent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, logins)
+
+	_, err = keysRepository.GetStudentKey(ctx, validStudent.ID)
+	assert.ErrorIs(t, err, identities.ErrStudentKeyNotFound)
+
+	// the erased student CPF and email are free again, its id is not
+	other := validStudent
+	other.ID = "201116548713"
+	conflicts, err := repository.FindConflicts(ctx, []entities.Student{validStudent, other})
+	require.NoError(t, err)
+	assert.Equal(t, []error{identities.ErrStudentAlreadyExists, nil}, conflicts)
+	assert.NoError(t, repository.CreateStudent(ctx, other))
+
+	_, err = repository.EraseStudent(ctx, entities.NewStudentErasure("000000000"))
+	assert.ErrorIs(t, err, identities.ErrStudentNotFound)
+}
+
+func TestStudentsRepository_EncryptStudents(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	db := pgfixtures.NewDB(t)
+	repository := NewStudentsRepository(db, pgfixtures.NewKeyring(t))
+
+	plain := validStudent
+	plain.ID = "201116548713"
+	plain.CPF = "52998224725"
+	plain.Email = "plain@ol.com"
+	insertPlainStudent(t, db, validStudent)
+	insertPlainStudent(t, db, plain)
+
+	// a student stored in plain still conflicts with a new one
+	other := validStudent
+	other.ID = "201116548714"
+	other.Email = "other@ol.com"
+	require.ErrorIs(t, repository.CreateStudent(ctx, other), identities.ErrCPFAlreadyRegistered)
+
+	// test
+	first, err := repository.EncryptStudents(ctx, "", 1)
+	require.NoError(t, err)
+	second, err := repository.EncryptStudents(ctx, first.LastID, 1)
+	require.NoError(t, err)
+	last, err := repository.EncryptStudents(ctx, second.LastID, 1)
+	require.NoError(t, err)
+
+	// assert
+	assert.Equal(t, EncryptionBatch{LastID: validStudent.ID, Encrypted: 1}, first)
+	assert.Equal(t, EncryptionBatch{LastID: plain.ID, Encrypted: 1}, second)
+	assert.Equal(t, EncryptionBatch{}, last)
+
+	var plainValues int
+	err = db.QueryRow(ctx, `SELECT count(cpf) + count(email) + count(birth_date) FROM students`).Scan(&plainValues)
+	require.NoError(t, err)
+	assert.Zero(t, plainValues)
+
+	got, err := repository.GetStudent(ctx, validStudent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, validStudent.CPF, got.CPF)
+	assert.Equal(t, validStudent.Email, got.Email)
+	assert.Equal(t, validStudent.BirthDate, got.BirthDate)
+
+	found, err := repository.SearchStudents(ctx, identities.StudentsFilter{CPF: "111.111.110-30", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, validStudent.ID, found[0].ID)
+
+	// once encrypted, the blind index keeps the CPF unique
+	assert.ErrorIs(t, repository.CreateStudent(ctx, other), identities.ErrCPFAlreadyRegistered)
+}
+
+// insertPlainStudent stores the student the way it was before the personal data encryption.
+func insertPlainStudent(t *testing.T, db *pgxpool.Pool, student entities.Student) {
+	t.Helper()
+
+	_, err := db.Exec(context.Background(),
+		`INSERT INTO students (id, name, secret, birth_date, cpf, email, status) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		student.ID, student.Name, student.Secret, student.BirthDate, student.CPF, student.Email, entities.StudentStatusActive,
+	)
+	require.NoError(t, err)
+}
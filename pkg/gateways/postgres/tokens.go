@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+)
+
+// TokensRepository ignores the expired tokens right away, they are only deleted by RunCleanup.
+type TokensRepository struct {
+	conn *pgxpool.Pool
+}
+
+func NewTokensRepository(conn *pgxpool.Pool) TokensRepository {
+	return TokensRepository{
+		conn: conn,
+	}
+}
+
+func (t TokensRepository) Register(ctx context.Context, token entities.Token) error {
+	const statement = `
+	INSERT INTO tokens (id, student_id, digest, expires_at, issued_at, user_agent, ip)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (id) DO UPDATE SET
+		student_id = excluded.student_id, digest = excluded.digest, expires_at = excluded.expires_at,
+		issued_at = excluded.issued_at, user_agent = excluded.user_agent, ip = excluded.ip
+	WHERE tokens.expires_at <= now()`
+
+	_, err := t.conn.Exec(ctx, statement,
+		token.ID, token.UserID, token.Digest, token.ExpirationDate, token.IssuedAt, token.Client.UserAgent, token.Client.IP,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t TokensRepository) GetDigest(ctx context.Context, id string) (string, error) {
+	const query = `SELECT digest FROM tokens WHERE id = $1 AND expires_at > now()`
+
+	var digest string
+	err := t.conn.QueryRow(ctx, query, id).Scan(&digest)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", identities.ErrTokenNotEmitted
+		}
+		return "", err
+	}
+
+	return digest, nil
+}
+
+func (t TokensRepository) ListStudentSessions(ctx context.Context, studentID string) ([]entities.Token, error) {
+	const query = `
+	SELECT id, student_id, expires_at
+	FROM tokens
+	WHERE student_id = $1 AND expires_at > now()
+	ORDER BY expires_at`
+
+	rows, err := t.conn.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (entities.Token, error) {
+		var token entities.Token
+		err := row.Scan(&token.ID, &token.UserID, &token.ExpirationDate)
+		token.ExpirationDate = token.ExpirationDate.UTC()
+		return token, err
+	})
+}
+
+func (t TokensRepository) RevokeStudentSessions(ctx context.Context, studentID string) (int, error) {
+	const statement = `
+	WITH revoked AS (DELETE FROM tokens WHERE student_id = $1 RETURNING expires_at)
+	SELECT count(*) FROM revoked WHERE expires_at > now()`
+
+	var revoked int
+	err := t.conn.QueryRow(ctx, statement, studentID).Scan(&revoked)
+	if err != nil {
+		return 0, err
+	}
+
+	return revoked, nil
+}
+
+// DeleteExpiredTokens returns how many tokens were deleted.
+func (t TokensRepository) DeleteExpiredTokens(ctx context.Context) (int, error) {
+	const statement = `DELETE FROM tokens WHERE expires_at <= now()`
+
+	tag, err := t.conn.Exec(ctx, statement)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// RunCleanup deletes the expired tokens every interval until the context is done. A failed cleanup is reported
+// to onError and attempted again at the next interval.
+func (t TokensRepository) RunCleanup(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := t.DeleteExpiredTokens(ctx); err != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
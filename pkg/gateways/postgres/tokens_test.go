@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idcontracts"
+	"github.com/tccav/identity-service/pkg/gateways/postgres/pgfixtures"
+)
+
+func TestTokensRepository(t *testing.T) {
+	t.Parallel()
+
+	idcontracts.TokensRepository(t, func(t *testing.T) identities.TokensRepository {
+		return NewTokensRepository(pgfixtures.NewDB(t))
+	})
+}
+
+func TestTokensRepository_RunCleanup(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	db := pgfixtures.NewDB(t)
+	repository := NewTokensRepository(db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expired := entities.NewToken(uuid.NewString(), time.Now().Add(-time.Minute))
+	expired.Digest = "expired"
+	active := entities.NewToken(uuid.NewString(), time.Now().Add(time.Hour))
+	active.Digest = "active"
+	for _, token := range []entities.Token{expired, active} {
+		require.NoError(t, repository.Register(ctx, token))
+	}
+
+	// test
+	go repository.RunCleanup(ctx, 10*time.Millisecond, func(err error) {
+		assert.NoError(t, err)
+	})
+
+	// assert
+	assert.Eventually(t, func() bool {
+		var count int
+		err := db.QueryRow(ctx, `SELECT count(*) FROM tokens WHERE id = $1`, expired.ID).Scan(&count)
+		return err == nil && count == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	digest, err := repository.GetDigest(ctx, active.ID)
+	require.NoError(t, err)
+	assert.Equal(t, active.Digest, digest)
+}
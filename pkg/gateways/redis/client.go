@@ -0,0 +1,93 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
+)
+
+var ErrInvalidConnection = errors.New("invalid memory db connection")
+
+// Memory db deployment modes, as informed in the configs.
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+// Connection Addresses is the server of a standalone deployment, the sentinels of a sentinel one and the seed
+// nodes of a cluster. MasterName is the master the sentinels monitor. Username and Password are the ACL user,
+// the sentinels may have their own.
+type Connection struct {
+	Mode             string
+	Addresses        []string
+	MasterName       string
+	Username         string
+	Password         string
+	SentinelUsername string
+	SentinelPassword string
+	TLS              tlsconfig.Config
+}
+
+// NewUniversalClient connects to the deployment of the mode, unlike redis.NewUniversalClient, a cluster with
+// a single seed node is not taken for a standalone server.
+func NewUniversalClient(conn Connection) (redis.UniversalClient, error) {
+	opts, err := UniversalOptions(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch conn.Mode {
+	case ModeSentinel:
+		return redis.NewFailoverClient(opts.Failover()), nil
+	case ModeCluster:
+		return redis.NewClusterClient(opts.Cluster()), nil
+	default:
+		return redis.NewClient(opts.Simple()), nil
+	}
+}
+
+// UniversalOptions builds the client options of the connection, an empty mode is a standalone server.
+func UniversalOptions(conn Connection) (*redis.UniversalOptions, error) {
+	if len(conn.Addresses) == 0 {
+		return nil, fmt.Errorf("%w: no addresses", ErrInvalidConnection)
+	}
+
+	switch conn.Mode {
+	case "", ModeStandalone:
+		if len(conn.Addresses) > 1 {
+			return nil, fmt.Errorf("%w: %s mode takes a single address", ErrInvalidConnection, ModeStandalone)
+		}
+	case ModeSentinel:
+		if conn.MasterName == "" {
+			return nil, fmt.Errorf("%w: %s mode requires the master name", ErrInvalidConnection, ModeSentinel)
+		}
+	case ModeCluster:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidConnection, conn.Mode)
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:            conn.Addresses,
+		Username:         conn.Username,
+		Password:         conn.Password,
+		SentinelUsername: conn.SentinelUsername,
+		SentinelPassword: conn.SentinelPassword,
+	}
+	if conn.Mode == ModeSentinel {
+		opts.MasterName = conn.MasterName
+	}
+
+	if conn.TLS.Enabled {
+		tlsConfig, err := conn.TLS.Build()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConnection, err)
+		}
+		opts.TLSConfig = tlsConfig
+	}
+
+	return opts, nil
+}
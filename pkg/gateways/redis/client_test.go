@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/gateways/tlsconfig"
+)
+
+func TestUniversalOptions(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name       string
+		conn       Connection
+		want       *redis.UniversalOptions
+		wantClient any
+		wantErr    error
+	}{
+		{
+			name:       "should connect to a standalone server with an ACL user",
+			conn:       Connection{Addresses: []string{"localhost:6379"}, Username: "identity", Password: "secret"},
+			want:       &redis.UniversalOptions{Addrs: []string{"localhost:6379"}, Username: "identity", Password: "secret"},
+			wantClient: &redis.Client{},
+		},
+		{
+			name: "should connect to the master monitored by the sentinels",
+			conn: Connection{
+				Mode:             ModeSentinel,
+				Addresses:        []string{"sentinel-1:26379", "sentinel-2:26379"},
+				MasterName:       "identity",
+				SentinelUsername: "sentinel",
+				SentinelPassword: "sentinel-secret",
+			},
+			want: &redis.UniversalOptions{
+				Addrs:            []string{"sentinel-1:26379", "sentinel-2:26379"},
+				MasterName:       "identity",
+				SentinelUsername: "sentinel",
+				SentinelPassword: "sentinel-secret",
+			},
+			wantClient: &redis.Client{},
+		},
+		{
+			name:       "should connect to a cluster from a single seed node",
+			conn:       Connection{Mode: ModeCluster, Addresses: []string{"node-1:6379"}, MasterName: "ignored"},
+			want:       &redis.UniversalOptions{Addrs: []string{"node-1:6379"}},
+			wantClient: &redis.ClusterClient{},
+		},
+		{
+			name:    "should fail because there are no addresses",
+			conn:    Connection{Mode: ModeCluster},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because a standalone server has a single address",
+			conn:    Connection{Mode: ModeStandalone, Addresses: []string{"node-1:6379", "node-2:6379"}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because sentinels require the master name",
+			conn:    Connection{Mode: ModeSentinel, Addresses: []string{"sentinel-1:26379"}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the mode is unknown",
+			conn:    Connection{Mode: "replicated", Addresses: []string{"localhost:6379"}},
+			wantErr: ErrInvalidConnection,
+		},
+		{
+			name:    "should fail because the client certificate has no key",
+			conn:    Connection{Addresses: []string{"localhost:6379"}, TLS: tlsconfig.Config{Enabled: true, CertFile: "client.pem"}},
+			wantErr: ErrInvalidConnection,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, err := UniversalOptions(tc.conn)
+			client, clientErr := NewUniversalClient(tc.conn)
+
+			// assert
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.ErrorIs(t, clientErr, tc.wantErr)
+			assert.Equal(t, tc.want, got)
+			if tc.wantClient != nil {
+				defer client.Close()
+				assert.IsType(t, tc.wantClient, client)
+			}
+		})
+	}
+
+	t.Run("should connect over TLS", func(t *testing.T) {
+		t.Parallel()
+
+		// test
+		got, err := UniversalOptions(Connection{
+			Addresses: []string{"localhost:6379"},
+			TLS:       tlsconfig.Config{Enabled: true},
+		})
+
+		// assert
+		require.NoError(t, err)
+		require.NotNil(t, got.TLSConfig)
+		assert.Equal(t, uint16(tls.VersionTLS12), got.TLSConfig.MinVersion)
+	})
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyRepository stores the requests made with an idempotency key. Values are opaque to it,
+// the HTTP layer decides what is kept from each request.
+type IdempotencyRepository struct {
+	client redis.UniversalClient
+}
+
+func NewIdempotencyRepository(client redis.UniversalClient) IdempotencyRepository {
+	return IdempotencyRepository{
+		client: client,
+	}
+}
+
+// Reserve stores the value only if the key is free. When it is not, the value already stored is returned.
+func (i IdempotencyRepository) Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	// the stored value may expire between both calls, so reserving is attempted a second time
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := i.client.SetNX(ctx, parseIdempotencyKey(key), value, ttl).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		if reserved {
+			return nil, true, nil
+		}
+
+		stored, err := i.Get(ctx, key)
+		if err != nil {
+			return nil, false, err
+		}
+		if stored != nil {
+			return stored, false, nil
+		}
+	}
+
+	return nil, false, errors.New("unable to reserve idempotency key")
+}
+
+// Get returns a nil value when nothing is stored under the key.
+func (i IdempotencyRepository) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := i.client.Get(ctx, parseIdempotencyKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+// Complete replaces the reserved value, it is a no-op if the reservation has already expired.
+func (i IdempotencyRepository) Complete(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return i.client.SetXX(ctx, parseIdempotencyKey(key), value, ttl).Err()
+}
+
+// releaseScript deletes the key only while it holds the reserved value, comparing and deleting at once.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Release frees the key only while it holds the reserved value, once the reservation expired it may belong to
+// another request.
+func (i IdempotencyRepository) Release(ctx context.Context, key string, reserved []byte) error {
+	return releaseScript.Run(ctx, i.client, []string{parseIdempotencyKey(key)}, reserved).Err()
+}
+
+func parseIdempotencyKey(key string) string {
+	const idempotencyKeyTpl = "idempotency:%s"
+
+	return fmt.Sprintf(idempotencyKeyTpl, key)
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+func TestIdempotencyRepository(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	repository := NewIdempotencyRepository(rfixtures.NewDB(t))
+	key := uuid.NewString()
+
+	// test & assert
+	stored, reserved, err := repository.Reserve(ctx, key, []byte("in_progress"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Nil(t, stored)
+
+	stored, reserved, err = repository.Reserve(ctx, key, []byte("other"), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, []byte("in_progress"), stored)
+
+	// only the request holding the reservation releases it
+	require.NoError(t, repository.Release(ctx, key, []byte("other")))
+	stored, err = repository.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("in_progress"), stored)
+
+	require.NoError(t, repository.Release(ctx, key, []byte("in_progress")))
+	stored, err = repository.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	_, reserved, err = repository.Reserve(ctx, key, []byte("in_progress"), time.Minute)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, repository.Complete(ctx, key, []byte("done"), time.Minute))
+
+	stored, err = repository.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), stored)
+
+	require.NoError(t, repository.Release(ctx, key, []byte("in_progress")))
+	stored, err = repository.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("done"), stored)
+
+	require.NoError(t, repository.Release(ctx, key, []byte("done")))
+
+	// completing a released key must not recreate it
+	require.NoError(t, repository.Complete(ctx, key, []byte("done"), time.Minute))
+	stored, err = repository.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
@@ -0,0 +1,199 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+)
+
+const (
+	revokedTokensKey   = "revoked_tokens"
+	revocationsChannel = "token_revocations"
+)
+
+// TokenRevocationList keeps the revoked tokens of every replica in memory. Revocations are published on a
+// channel all replicas subscribe to, and kept in a sorted set by expiration, so a replica starting or
+// reconnecting loads the ones it missed. Each revocation is a token id and its expiration in unix milliseconds.
+type TokenRevocationList struct {
+	client    redis.UniversalClient
+	mu        *sync.RWMutex
+	revoked   map[string]time.Time
+	listeners *[]func(ids ...string)
+}
+
+func NewTokenRevocationList(client redis.UniversalClient) TokenRevocationList {
+	return TokenRevocationList{
+		client:    client,
+		mu:        &sync.RWMutex{},
+		revoked:   map[string]time.Time{},
+		listeners: &[]func(ids ...string){},
+	}
+}
+
+// Revoke lists the tokens in this replica right away, the others list them once the revocation reaches them.
+func (l TokenRevocationList) Revoke(ctx context.Context, tokens ...entities.Token) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(tokens))
+	revocations := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		expiration := token.ExpirationDate.UnixMilli()
+		members = append(members, redis.Z{Score: float64(expiration), Member: token.ID})
+		revocations = append(revocations, fmt.Sprintf("%s:%d", token.ID, expiration))
+	}
+
+	_, err := l.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, revokedTokensKey, members...)
+		pipe.ZRemRangeByScore(ctx, revokedTokensKey, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		pipe.Publish(ctx, revocationsChannel, strings.Join(revocations, ","))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	l.list(revocations)
+	return nil
+}
+
+func (l TokenRevocationList) IsRevoked(id string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	expiration, ok := l.revoked[id]
+	return ok && time.Now().Before(expiration)
+}
+
+// OnRevoke listeners are called once the tokens are listed, so they see them as revoked. Revocations loaded
+// when subscribing are passed on again, the ones missed while disconnected among them.
+func (l TokenRevocationList) OnRevoke(listener func(ids ...string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	*l.listeners = append(*l.listeners, listener)
+}
+
+// Backoff before receiving again after a failure, doubled on each failure in a row.
+const (
+	receiveBackoff    = 100 * time.Millisecond
+	receiveMaxBackoff = 10 * time.Second
+)
+
+// Run keeps the list up to date until the context is done, the expired tokens are forgotten every
+// evictionInterval. Failures are reported to onError, the subscription is then restored by itself after a
+// backoff. The list must be loaded before Run, see Load.
+func (l TokenRevocationList) Run(ctx context.Context, evictionInterval time.Duration, onError func(error)) {
+	pubSub := l.client.Subscribe(ctx, revocationsChannel)
+	defer pubSub.Close()
+
+	go func() {
+		ticker := time.NewTicker(evictionInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				l.evict(now)
+			}
+		}
+	}()
+
+	backoff := receiveBackoff
+	for {
+		received, err := pubSub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			onError(err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > receiveMaxBackoff {
+				backoff = receiveMaxBackoff
+			}
+			continue
+		}
+		backoff = receiveBackoff
+
+		switch msg := received.(type) {
+		case *redis.Subscription:
+			// revocations published while not subscribed are only found in the sorted set
+			if err = l.Load(ctx); err != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		case *redis.Message:
+			l.list(strings.Split(msg.Payload, ","))
+		}
+	}
+}
+
+// Load lists the revocations kept in redis, a replica must load them before verifying any token.
+func (l TokenRevocationList) Load(ctx context.Context) error {
+	members, err := l.client.ZRangeByScoreWithScores(ctx, revokedTokensKey, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	revocations := make([]string, 0, len(members))
+	for _, member := range members {
+		revocations = append(revocations, fmt.Sprintf("%s:%d", member.Member, int64(member.Score)))
+	}
+	l.list(revocations)
+	return nil
+}
+
+// list ignores the malformed revocations, no replica publishes them.
+func (l TokenRevocationList) list(revocations []string) {
+	l.mu.Lock()
+	ids := make([]string, 0, len(revocations))
+	for _, revocation := range revocations {
+		id, expiration, ok := strings.Cut(revocation, ":")
+		if !ok {
+			continue
+		}
+		millis, err := strconv.ParseInt(expiration, 10, 64)
+		if err != nil {
+			continue
+		}
+		l.revoked[id] = time.UnixMilli(millis)
+		ids = append(ids, id)
+	}
+	listeners := *l.listeners
+	l.mu.Unlock()
+
+	if len(ids) == 0 {
+		return
+	}
+	for _, listener := range listeners {
+		listener(ids...)
+	}
+}
+
+func (l TokenRevocationList) evict(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for id, expiration := range l.revoked {
+		if !now.Before(expiration) {
+			delete(l.revoked, id)
+		}
+	}
+}
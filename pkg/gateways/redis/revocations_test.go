@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+func TestTokenRevocationList(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reach the other replicas and the ones starting later", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		client := rfixtures.NewDB(t)
+
+		running := NewTokenRevocationList(client)
+		go running.Run(ctx, time.Minute, func(err error) { t.Error(err) })
+		// the subscription must be in place before publishing
+		require.Eventually(t, func() bool {
+			channels, err := client.PubSubNumSub(ctx, revocationsChannel).Result()
+			return err == nil && channels[revocationsChannel] > 0
+		}, 5*time.Second, 10*time.Millisecond)
+
+		token := entities.NewToken(uuid.NewString(), time.Now().Add(time.Hour))
+
+		// test
+		err := NewTokenRevocationList(client).Revoke(ctx, token)
+
+		// assert
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return running.IsRevoked(token.ID) }, 5*time.Second, 10*time.Millisecond)
+
+		starting := NewTokenRevocationList(client)
+		require.NoError(t, starting.Load(ctx))
+		assert.True(t, starting.IsRevoked(token.ID))
+	})
+}
+
+func TestTokenRevocationList_list(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	now := time.Now()
+	list := NewTokenRevocationList(nil)
+	var notified []string
+	list.OnRevoke(func(ids ...string) { notified = append(notified, ids...) })
+
+	// test
+	list.list([]string{
+		"revoked:" + formatMillis(now.Add(time.Hour)),
+		"expired:" + formatMillis(now.Add(-time.Second)),
+		"malformed",
+		"malformed:expiration",
+	})
+
+	// assert
+	assert.True(t, list.IsRevoked("revoked"))
+	assert.False(t, list.IsRevoked("expired"))
+	assert.False(t, list.IsRevoked("malformed"))
+	assert.False(t, list.IsRevoked("never-revoked"))
+	assert.Equal(t, []string{"revoked", "expired"}, notified)
+
+	list.evict(now)
+	assert.Len(t, list.revoked, 1)
+}
+
+func formatMillis(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
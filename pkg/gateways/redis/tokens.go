@@ -12,33 +12,153 @@ import (
 	"github.com/tccav/identity-service/pkg/domain/identities"
 )
 
+// TokensRepository works with standalone, sentinel and cluster deployments. In a cluster, the writes of a token
+// and of its student index are atomic within each slot only.
 type TokensRepository struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
-func NewTokensRepository(client *redis.Client) TokensRepository {
+func NewTokensRepository(client redis.UniversalClient) TokensRepository {
 	return TokensRepository{
 		client: client,
 	}
 }
 
+// registerTokenScript stores the token fields in a hash only if the token is not stored yet. ARGV has the
+// expiration in milliseconds followed by the fields.
+var registerTokenScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('HSET', KEYS[1], unpack(ARGV, 2))
+redis.call('PEXPIRE', KEYS[1], ARGV[1])
+return 1
+`)
+
+// getDigestScript reads the digest field of the token hash, or the whole value of the tokens stored as strings
+// before digests were.
+var getDigestScript = redis.NewScript(`
+local kind = redis.call('TYPE', KEYS[1]).ok
+if kind == 'hash' then
+	return redis.call('HGET', KEYS[1], 'digest')
+elseif kind == 'string' then
+	return redis.call('GET', KEYS[1])
+end
+return false
+`)
+
+// Register also indexes the token by its student, the index lives as long as the student latest token.
 func (t TokensRepository) Register(ctx context.Context, token entities.Token) error {
-	_, err := t.client.SetNX(ctx, parseTokenKey(token.ID), token.Hash, time.Until(token.ExpirationDate)).Result()
+	ttl := time.Until(token.ExpirationDate)
+	studentKey := parseStudentTokensKey(token.UserID)
+
+	_, err := t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		registerTokenScript.Eval(ctx, pipe, []string{parseTokenKey(token.ID)},
+			ttl.Milliseconds(),
+			"digest", token.Digest,
+			"user_id", token.UserID,
+			"issued_at", token.IssuedAt.UTC().Format(time.RFC3339Nano),
+			"user_agent", token.Client.UserAgent,
+			"ip", token.Client.IP,
+		)
+		pipe.SAdd(ctx, studentKey, token.ID)
+		pipe.ExpireNX(ctx, studentKey, ttl)
+		pipe.ExpireGT(ctx, studentKey, ttl)
+		return nil
+	})
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t TokensRepository) GetHash(ctx context.Context, id string) (string, error) {
-	hash, err := t.client.Get(ctx, parseTokenKey(id)).Result()
+func (t TokensRepository) GetDigest(ctx context.Context, id string) (string, error) {
+	digest, err := getDigestScript.Run(ctx, t.client, []string{parseTokenKey(id)}).Text()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return "", identities.ErrTokenNotEmitted
 		}
 		return "", err
 	}
-	return hash, nil
+	return digest, nil
+}
+
+// ListStudentSessions only knows tokens registered since they started being indexed by student. Expired
+// tokens found in the index are removed from it.
+func (t TokensRepository) ListStudentSessions(ctx context.Context, studentID string) ([]entities.Token, error) {
+	studentKey := parseStudentTokensKey(studentID)
+
+	ids, err := t.client.SMembers(ctx, studentKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	cmds, err := t.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.PTTL(ctx, parseTokenKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	tokens := make([]entities.Token, 0, len(ids))
+	var expired []any
+	for i, cmd := range cmds {
+		ttl := cmd.(*redis.DurationCmd).Val()
+		if ttl <= 0 {
+			expired = append(expired, ids[i])
+			continue
+		}
+
+		tokens = append(tokens, entities.Token{
+			ID:             ids[i],
+			UserID:         studentID,
+			ExpirationDate: now.Add(ttl),
+		})
+	}
+
+	if len(expired) > 0 {
+		err = t.client.SRem(ctx, studentKey, expired...).Err()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+// RevokeStudentSessions deletes the tokens found in the student index along with the index itself. Tokens
+// registered before the index existed are left to expire, their verification fails once the student status
+// refuses them.
+func (t TokensRepository) RevokeStudentSessions(ctx context.Context, studentID string) (int, error) {
+	studentKey := parseStudentTokensKey(studentID)
+
+	ids, err := t.client.SMembers(ctx, studentKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// each token is deleted on its own, in a cluster their keys may live in other slots than the index
+	deleted := make([]*redis.IntCmd, 0, len(ids))
+	_, err = t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			deleted = append(deleted, pipe.Del(ctx, parseTokenKey(id)))
+		}
+		pipe.Del(ctx, studentKey)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var revoked int
+	for _, cmd := range deleted {
+		revoked += int(cmd.Val())
+	}
+	return revoked, nil
 }
 
 func parseTokenKey(tokenID string) string {
@@ -46,3 +166,9 @@ func parseTokenKey(tokenID string) string {
 
 	return fmt.Sprintf(tokenKeyTpl, tokenID)
 }
+
+func parseStudentTokensKey(studentID string) string {
+	const studentTokensKeyTpl = "student_tokens:%s"
+
+	return fmt.Sprintf(studentTokensKeyTpl, studentID)
+}
@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tccav/identity-service/pkg/domain/entities"
+	"github.com/tccav/identity-service/pkg/domain/identities"
+	"github.com/tccav/identity-service/pkg/domain/identities/idcontracts"
+	"github.com/tccav/identity-service/pkg/gateways/redis/rfixtures"
+)
+
+func TestTokensRepository(t *testing.T) {
+	t.Parallel()
+
+	idcontracts.TokensRepository(t, func(t *testing.T) identities.TokensRepository {
+		return NewTokensRepository(rfixtures.NewDB(t))
+	})
+}
+
+func TestTokensRepository_Register(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	client := rfixtures.NewDB(t)
+	repository := NewTokensRepository(client)
+
+	token := entities.NewToken(uuid.NewString(), time.Now().Add(time.Hour))
+	token.IssuedAt = time.Date(2023, 8, 13, 10, 0, 0, 0, time.UTC)
+	token.Client = entities.AuthenticationClient{IP: "203.0.113.7", UserAgent: "test-agent"}
+	token.Hash = "signed.jwt.token"
+	token.Digest = "digest"
+
+	// test
+	err := repository.Register(ctx, token)
+
+	// assert
+	require.NoError(t, err)
+
+	fields, err := client.HGetAll(ctx, parseTokenKey(token.ID)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"digest":     token.Digest,
+		"user_id":    token.UserID,
+		"issued_at":  "2023-08-13T10:00:00Z",
+		"user_agent": token.Client.UserAgent,
+		"ip":         token.Client.IP,
+	}, fields)
+
+	ttl, err := client.PTTL(ctx, parseTokenKey(token.ID)).Result()
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour, ttl, float64(time.Minute))
+}
+
+func TestTokensRepository_GetDigest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should read the signed token of legacy entries", func(t *testing.T) {
+		t.Parallel()
+
+		// prepare
+		ctx := context.Background()
+		client := rfixtures.NewDB(t)
+		repository := NewTokensRepository(client)
+		id := uuid.NewString()
+		require.NoError(t, client.Set(ctx, parseTokenKey(id), "signed.jwt.token", time.Hour).Err())
+
+		// test
+		got, err := repository.GetDigest(ctx, id)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, "signed.jwt.token", got)
+	})
+}
+
+func TestTokensRepository_ListStudentSessions(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	client := rfixtures.NewDB(t)
+	repository := NewTokensRepository(client)
+	studentID := uuid.NewString()
+
+	active := entities.NewToken(studentID, time.Now().Add(time.Hour))
+	active.Digest = "active"
+	expired := entities.NewToken(studentID, time.Now().Add(time.Hour))
+	expired.Digest = "expired"
+	other := entities.NewToken(uuid.NewString(), time.Now().Add(time.Hour))
+	other.Digest = "other"
+
+	for _, token := range []entities.Token{active, expired, other} {
+		require.NoError(t, repository.Register(ctx, token))
+	}
+	require.NoError(t, client.Del(ctx, parseTokenKey(expired.ID)).Err())
+
+	// test
+	got, err := repository.ListStudentSessions(ctx, studentID)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, active.ID, got[0].ID)
+	assert.Equal(t, studentID, got[0].UserID)
+	assert.Empty(t, got[0].Digest)
+	assert.WithinDuration(t, active.ExpirationDate, got[0].ExpirationDate, time.Second)
+
+	members, err := client.SMembers(ctx, parseStudentTokensKey(studentID)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, []string{active.ID}, members)
+}
+
+func TestTokensRepository_RevokeStudentSessions(t *testing.T) {
+	t.Parallel()
+
+	// prepare
+	ctx := context.Background()
+	client := rfixtures.NewDB(t)
+	repository := NewTokensRepository(client)
+	studentID := uuid.NewString()
+
+	first := entities.NewToken(studentID, time.Now().Add(time.Hour))
+	first.Digest = "first"
+	second := entities.NewToken(studentID, time.Now().Add(time.Hour))
+	second.Digest = "second"
+	other := entities.NewToken(uuid.NewString(), time.Now().Add(time.Hour))
+	other.Digest = "other"
+
+	for _, token := range []entities.Token{first, second, other} {
+		require.NoError(t, repository.Register(ctx, token))
+	}
+
+	// test
+	got, err := repository.RevokeStudentSessions(ctx, studentID)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, got)
+
+	_, err = repository.GetDigest(ctx, first.ID)
+	assert.ErrorIs(t, err, identities.ErrTokenNotEmitted)
+	_, err = repository.GetDigest(ctx, second.ID)
+	assert.ErrorIs(t, err, identities.ErrTokenNotEmitted)
+	digest, err := repository.GetDigest(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.Digest, digest)
+
+	exists, err := client.Exists(ctx, parseStudentTokensKey(studentID)).Result()
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+}
@@ -0,0 +1,51 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidConfig = errors.New("invalid tls config")
+
+// Config is how the gateways verify the servers they connect to over TLS. CAFile replaces the system roots the
+// servers are verified with. CertFile and KeyFile are the client certificate, for servers requiring mutual TLS,
+// they go together. The files are only read when TLS is enabled.
+type Config struct {
+	Enabled  bool
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// Build reads the files of the config, it must only be called when TLS is enabled.
+func (c Config) Build() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CAFile != "" {
+		ca, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading CA: %s", ErrInvalidConfig, err)
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("%w: no PEM certificates in CA file %s", ErrInvalidConfig, c.CAFile)
+		}
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, fmt.Errorf("%w: client certificate and key must be informed together", ErrInvalidConfig)
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: loading client certificate: %s", ErrInvalidConfig, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
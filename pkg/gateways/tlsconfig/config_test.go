@@ -0,0 +1,133 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Build(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	caFile, certFile, keyFile := writeCertificates(t, dir)
+	emptyFile := writeFile(t, dir, "empty", "")
+
+	tt := []struct {
+		name     string
+		config   Config
+		wantCA   bool
+		wantCert bool
+		wantErr  error
+	}{
+		{
+			name:   "should verify the servers with the system roots",
+			config: Config{Enabled: true},
+		},
+		{
+			name:     "should verify the servers with the CA and present the client certificate",
+			config:   Config{Enabled: true, CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+			wantCA:   true,
+			wantCert: true,
+		},
+		{
+			name:    "should fail because the CA file does not exist",
+			config:  Config{Enabled: true, CAFile: filepath.Join(dir, "missing.pem")},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "should fail because the CA file has no certificates",
+			config:  Config{Enabled: true, CAFile: emptyFile},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "should fail because the client certificate has no key",
+			config:  Config{Enabled: true, CertFile: certFile},
+			wantErr: ErrInvalidConfig,
+		},
+		{
+			name:    "should fail because the client key does not match the certificate",
+			config:  Config{Enabled: true, CertFile: certFile, KeyFile: caFile},
+			wantErr: ErrInvalidConfig,
+		},
+	}
+	for _, testCase := range tt {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// test
+			got, err := tc.config.Build()
+
+			// assert
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint16(tls.VersionTLS12), got.MinVersion)
+			assert.Equal(t, tc.wantCA, got.RootCAs != nil)
+			assert.Equal(t, tc.wantCert, len(got.Certificates) == 1)
+		})
+	}
+}
+
+// writeCertificates writes a self-signed CA and a client certificate it signed, both PEM encoded.
+func writeCertificates(t *testing.T, dir string) (caFile, certFile, keyFile string) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "identity-service test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "identity-service"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	caFile = writeFile(t, dir, "ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})))
+	certFile = writeFile(t, dir, "client.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})))
+	keyFile = writeFile(t, dir, "client-key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: clientKeyDER})))
+	return caFile, certFile, keyFile
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}